@@ -0,0 +1,73 @@
+/*
+MIT License
+
+Copyright (c) 2020-2024 1Password
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ClusterOnePasswordConnectionSpec defines the desired state of ClusterOnePasswordConnection
+type ClusterOnePasswordConnectionSpec struct {
+	OnePasswordConnectionSpec `json:",inline"`
+
+	// AllowedNamespaces lists the namespaces whose OnePasswordItems and Deployments may select the connection.
+	// +optional
+	AllowedNamespaces []string `json:"allowedNamespaces,omitempty"`
+
+	// NamespaceSelector selects the namespaces whose OnePasswordItems and Deployments may select the
+	// connection, in addition to AllowedNamespaces. An empty selector ({}) allows every namespace.
+	// When neither is set, only ClusterOnePasswordItems can select the connection.
+	// +optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:printcolumn:name="Backend",type=string,JSONPath=`.spec.backend`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+// +kubebuilder:resource:scope=Cluster,shortName=copc
+// +kubebuilder:validation:XValidation:rule="!has(self.spec) || has(self.spec.tokenSecretRef.__namespace__)",message="spec.tokenSecretRef.namespace is required"
+
+// ClusterOnePasswordConnection is the Schema for the clusteronepasswordconnections API.
+// It holds credentials that ClusterOnePasswordItems, and OnePasswordItems and Deployments in the allowed
+// namespaces, select with spec.connectionRef or the operator.1password.io/connection annotation.
+type ClusterOnePasswordConnection struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ClusterOnePasswordConnectionSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ClusterOnePasswordConnectionList contains a list of ClusterOnePasswordConnection
+type ClusterOnePasswordConnectionList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ClusterOnePasswordConnection `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ClusterOnePasswordConnection{}, &ClusterOnePasswordConnectionList{})
+}
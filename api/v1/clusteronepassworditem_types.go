@@ -0,0 +1,112 @@
+/*
+MIT License
+
+Copyright (c) 2020-2024 1Password
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ClusterOnePasswordItemSpec defines the desired state of ClusterOnePasswordItem
+type ClusterOnePasswordItemSpec struct {
+	// NamespaceSelector selects the namespaces the Secret is created in.
+	// An empty selector matches every namespace.
+	NamespaceSelector metav1.LabelSelector `json:"namespaceSelector"`
+
+	// SecretName is the name of the Secret created in each selected namespace.
+	// Defaults to the name of the ClusterOnePasswordItem.
+	// +optional
+	SecretName string `json:"secretName,omitempty"`
+
+	// OnePasswordItemSpec describes the 1Password item and how it is mapped to the Secret.
+	// It accepts the same fields as the spec of a OnePasswordItem.
+	OnePasswordItemSpec `json:",inline"`
+}
+
+// ClusterOnePasswordItemNamespaceStatus is the sync status of the Secret in a single namespace.
+type ClusterOnePasswordItemNamespaceStatus struct {
+	// Namespace the Secret is created in.
+	Namespace string `json:"namespace"`
+	// Status of the Secret in the namespace, one of True, False, Unknown.
+	Status metav1.ConditionStatus `json:"status"`
+	// Last time the status transit from one value to another.
+	// +optional
+	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
+	// Human-readable message indicating why the Secret could not be synced.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// ClusterOnePasswordItemStatus defines the observed state of ClusterOnePasswordItem
+type ClusterOnePasswordItemStatus struct {
+	Conditions []OnePasswordItemCondition `json:"conditions"`
+
+	// Namespaces lists the sync status of the Secret in every selected namespace.
+	// +optional
+	Namespaces []ClusterOnePasswordItemNamespaceStatus `json:"namespaces,omitempty"`
+
+	// KeyCollisions lists the values of the item that map to the same Secret data key.
+	// +optional
+	KeyCollisions []KeyCollision `json:"keyCollisions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:resource:scope=Cluster,shortName=copi
+// +kubebuilder:validation:XValidation:rule="size(self.metadata.name) <= 63",message="name must be no more than 63 characters"
+
+// ClusterOnePasswordItem is the Schema for the clusteronepassworditems API.
+// It keeps a Secret built from a single 1Password item in every namespace matched by its selector.
+type ClusterOnePasswordItem struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Kubernetes secret type. More info: https://kubernetes.io/docs/concepts/configuration/secret/#secret-types
+	Type   string                       `json:"type,omitempty"`
+	Spec   ClusterOnePasswordItemSpec   `json:"spec,omitempty"`
+	Status ClusterOnePasswordItemStatus `json:"status,omitempty"`
+}
+
+// SecretName returns the name of the Secret managed in each selected namespace.
+func (c *ClusterOnePasswordItem) SecretName() string {
+	if c.Spec.SecretName != "" {
+		return c.Spec.SecretName
+	}
+	return c.Name
+}
+
+// +kubebuilder:object:root=true
+
+// ClusterOnePasswordItemList contains a list of ClusterOnePasswordItem
+type ClusterOnePasswordItemList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ClusterOnePasswordItem `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ClusterOnePasswordItem{}, &ClusterOnePasswordItemList{})
+}
@@ -0,0 +1,143 @@
+/*
+MIT License
+
+Copyright (c) 2020-2024 1Password
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Backends a OnePasswordConnection can use.
+const (
+	ConnectionBackendConnect        = "connect"
+	ConnectionBackendServiceAccount = "serviceAccount"
+)
+
+// SecretKeyReference references a key of a Secret.
+type SecretKeyReference struct {
+	// Name of the Secret.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Namespace of the Secret. Required by ClusterOnePasswordConnections for tokenSecretRef, other
+	// references default to the namespace of tokenSecretRef. A OnePasswordConnection always reads the
+	// Secret from its own namespace.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// Key of the Secret data holding the value.
+	// +kubebuilder:validation:MinLength=1
+	Key string `json:"key"`
+}
+
+// SecretReference references a Secret.
+type SecretReference struct {
+	// Name of the Secret.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Namespace of the Secret. Defaults to the namespace of tokenSecretRef for ClusterOnePasswordConnections.
+	// A OnePasswordConnection always reads the Secret from its own namespace.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// ConnectTransportSpec configures how the 1Password Connect server is reached.
+type ConnectTransportSpec struct {
+	// CASecretRef references the Secret key holding a PEM encoded CA bundle trusted for the Connect
+	// server, in addition to the system roots.
+	// +optional
+	CASecretRef *SecretKeyReference `json:"caSecretRef,omitempty"`
+
+	// ClientCertificateSecretRef references a kubernetes.io/tls Secret whose tls.crt and tls.key are
+	// presented to the Connect server for mutual TLS.
+	// +optional
+	ClientCertificateSecretRef *SecretReference `json:"clientCertificateSecretRef,omitempty"`
+
+	// ProxyURL is the URL of the proxy requests are sent through. Defaults to the proxy of the operator
+	// environment.
+	// +optional
+	ProxyURL string `json:"proxyURL,omitempty"`
+
+	// Timeout bounds every request to the Connect server, e.g. 10s. No timeout is set by default.
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+
+	// OperationTimeout bounds every operation on the Connect server, including all its requests and
+	// retries, e.g. 1m. No timeout is set by default.
+	// +optional
+	OperationTimeout *metav1.Duration `json:"operationTimeout,omitempty"`
+
+	// UserAgent is the user agent sent to the Connect server.
+	// +optional
+	UserAgent string `json:"userAgent,omitempty"`
+}
+
+// OnePasswordConnectionSpec defines the 1Password account and credentials of a connection.
+// +kubebuilder:validation:XValidation:rule="self.backend != 'connect' || has(self.host)",message="host is required for the connect backend"
+type OnePasswordConnectionSpec struct {
+	// Backend is the 1Password API used by the connection.
+	// +kubebuilder:validation:Enum=connect;serviceAccount
+	Backend string `json:"backend"`
+
+	// Host is the URL of the 1Password Connect server, e.g. http://onepassword-connect:8080.
+	// Required for the connect backend.
+	// +optional
+	Host string `json:"host,omitempty"`
+
+	// TokenSecretRef references the Secret key holding the Connect token or Service Account token.
+	TokenSecretRef SecretKeyReference `json:"tokenSecretRef"`
+
+	// Connect configures the TLS, proxy and timeout of the connect backend.
+	// +optional
+	Connect *ConnectTransportSpec `json:"connect,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:printcolumn:name="Backend",type=string,JSONPath=`.spec.backend`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+// +kubebuilder:resource:shortName=opc
+
+// OnePasswordConnection is the Schema for the onepasswordconnections API.
+// It holds the credentials that OnePasswordItems and Deployments in the same namespace select
+// with spec.connectionRef or the operator.1password.io/connection annotation.
+type OnePasswordConnection struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec OnePasswordConnectionSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// OnePasswordConnectionList contains a list of OnePasswordConnection
+type OnePasswordConnectionList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OnePasswordConnection `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&OnePasswordConnection{}, &OnePasswordConnectionList{})
+}
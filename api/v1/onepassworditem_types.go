@@ -25,6 +25,7 @@ SOFTWARE.
 package v1
 
 import (
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -35,11 +36,84 @@ import (
 // Each key in Data is a secret data key, and its value is a Go template string
 // that will be rendered using the 1Password item's fields as context.
 type SecretTemplate struct {
+	// Ref references a OnePasswordTemplate or ClusterOnePasswordTemplate whose data and partials are used.
+	// Keys in Data and Partials take precedence over the ones of the referenced template.
+	// +optional
+	Ref *TemplateReference `json:"ref,omitempty"`
+
 	// Data is a map of secret data key names to Go template strings.
 	// Templates can access fields via .Fields (flat map), .Sections (nested by section),
 	// or .FieldsByID (by field ID).
 	// +optional
 	Data map[string]string `json:"data,omitempty"`
+
+	// Partials are named Go templates that can be included in Data with {{ template "<name>" . }}.
+	// +optional
+	Partials map[string]string `json:"partials,omitempty"`
+}
+
+// Kinds a TemplateReference can point to.
+const (
+	OnePasswordTemplateKind        = "OnePasswordTemplate"
+	ClusterOnePasswordTemplateKind = "ClusterOnePasswordTemplate"
+)
+
+// TemplateReference references a OnePasswordTemplate or ClusterOnePasswordTemplate.
+type TemplateReference struct {
+	// Kind of the referenced template. A OnePasswordTemplate must be in the namespace of the
+	// referencing resource. Defaults to OnePasswordTemplate.
+	// +kubebuilder:validation:Enum=OnePasswordTemplate;ClusterOnePasswordTemplate
+	// +optional
+	Kind string `json:"kind,omitempty"`
+
+	// Name of the referenced template.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+}
+
+// KindOrDefault returns the kind of the referenced template, defaulting to OnePasswordTemplate.
+func (r *TemplateReference) KindOrDefault() string {
+	if r.Kind == "" {
+		return OnePasswordTemplateKind
+	}
+	return r.Kind
+}
+
+// String returns the reference as "<kind>/<name>".
+func (r *TemplateReference) String() string {
+	return r.KindOrDefault() + "/" + r.Name
+}
+
+// Kinds a ConnectionReference can point to.
+const (
+	OnePasswordConnectionKind        = "OnePasswordConnection"
+	ClusterOnePasswordConnectionKind = "ClusterOnePasswordConnection"
+)
+
+// ConnectionReference references a OnePasswordConnection or ClusterOnePasswordConnection.
+type ConnectionReference struct {
+	// Kind of the referenced connection. A OnePasswordConnection must be in the namespace of the
+	// referencing resource. Defaults to OnePasswordConnection.
+	// +kubebuilder:validation:Enum=OnePasswordConnection;ClusterOnePasswordConnection
+	// +optional
+	Kind string `json:"kind,omitempty"`
+
+	// Name of the referenced connection.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+}
+
+// KindOrDefault returns the kind of the referenced connection, defaulting to OnePasswordConnection.
+func (r *ConnectionReference) KindOrDefault() string {
+	if r.Kind == "" {
+		return OnePasswordConnectionKind
+	}
+	return r.Kind
+}
+
+// String returns the reference as "<kind>/<name>".
+func (r *ConnectionReference) String() string {
+	return r.KindOrDefault() + "/" + r.Name
 }
 
 // ImagePullSecretConfig configures automatic dockerconfigjson generation for image pull secrets.
@@ -47,6 +121,7 @@ type SecretTemplate struct {
 // 1Password item fields, and automatically sets the secret type to kubernetes.io/dockerconfigjson.
 type ImagePullSecretConfig struct {
 	// RegistryField is the label of the 1Password field containing the registry URL (e.g. "ghcr.io").
+	// When empty, the registry is derived from the item's primary URL.
 	RegistryField string `json:"registryField,omitempty"`
 	// UsernameField is the label of the 1Password field containing the registry username.
 	UsernameField string `json:"usernameField,omitempty"`
@@ -54,6 +129,399 @@ type ImagePullSecretConfig struct {
 	PasswordField string `json:"passwordField,omitempty"`
 	// EmailField is the label of the 1Password field containing the email (optional).
 	EmailField string `json:"emailField,omitempty"`
+
+	// Registries lists additional registry entries merged into the same .dockerconfigjson.
+	// Each entry may read its credentials from a different item or section.
+	// Every registry may only appear once.
+	// +optional
+	Registries []RegistryCredentials `json:"registries,omitempty"`
+
+	// ServiceAccounts lists the names of ServiceAccounts in the Secret's namespace that the
+	// operator appends the Secret to as an image pull secret. The reference is removed again
+	// when the resource is deleted or the ServiceAccount is no longer listed.
+	// +optional
+	ServiceAccounts []string `json:"serviceAccounts,omitempty"`
+	// ServiceAccountSelector selects additional ServiceAccounts in the Secret's namespace by label
+	// that the operator appends the Secret to as an image pull secret.
+	// +optional
+	ServiceAccountSelector *metav1.LabelSelector `json:"serviceAccountSelector,omitempty"`
+}
+
+// RegistryCredentials maps the fields of a 1Password item to a single registry entry of a .dockerconfigjson.
+type RegistryCredentials struct {
+	// ItemPath is the path of the 1Password item holding the credentials, in the format
+	// vaults/{vault}/items/{item}. Defaults to the itemPath of the resource.
+	// +optional
+	ItemPath string `json:"itemPath,omitempty"`
+	// Section is the title of the item section the fields are looked up in.
+	// When empty, fields are looked up in the whole item.
+	// +optional
+	Section string `json:"section,omitempty"`
+	// Registry is the registry host (e.g. "docker.io"). Takes precedence over RegistryField.
+	// +optional
+	Registry string `json:"registry,omitempty"`
+	// RegistryField is the label of the 1Password field containing the registry URL.
+	// When both Registry and RegistryField are empty, the registry is derived from the item's primary URL.
+	// +optional
+	RegistryField string `json:"registryField,omitempty"`
+	// UsernameField is the label of the 1Password field containing the registry username.
+	UsernameField string `json:"usernameField"`
+	// PasswordField is the label of the 1Password field containing the registry password or token.
+	PasswordField string `json:"passwordField"`
+	// EmailField is the label of the 1Password field containing the email (optional).
+	// +optional
+	EmailField string `json:"emailField,omitempty"`
+}
+
+// HasDefaultEntry reports whether the top-level fields of the config describe a registry entry.
+func (c *ImagePullSecretConfig) HasDefaultEntry() bool {
+	return c.RegistryField != "" || c.UsernameField != "" || c.PasswordField != "" || c.EmailField != ""
+}
+
+// AttachesToServiceAccounts reports whether the config selects any ServiceAccounts.
+func (c *ImagePullSecretConfig) AttachesToServiceAccounts() bool {
+	return c != nil && (len(c.ServiceAccounts) > 0 || c.ServiceAccountSelector != nil)
+}
+
+// TLSConfig configures generation of a kubernetes.io/tls Secret from a certificate item.
+// Each value is read from the field with the given label or, when the file name is set instead,
+// from the file attachment with that name.
+type TLSConfig struct {
+	// CertificateField is the label of the field containing the PEM encoded certificate,
+	// optionally followed by its intermediate certificates.
+	// +optional
+	CertificateField string `json:"certificateField,omitempty"`
+	// CertificateFile is the name of the file attachment containing the PEM encoded certificate.
+	// +optional
+	CertificateFile string `json:"certificateFile,omitempty"`
+	// PrivateKeyField is the label of the field containing the PEM encoded private key.
+	// +optional
+	PrivateKeyField string `json:"privateKeyField,omitempty"`
+	// PrivateKeyFile is the name of the file attachment containing the PEM encoded private key.
+	// +optional
+	PrivateKeyFile string `json:"privateKeyFile,omitempty"`
+	// CAField is the label of the field containing the PEM encoded CA bundle, written to ca.crt.
+	// +optional
+	CAField string `json:"caField,omitempty"`
+	// CAFile is the name of the file attachment containing the PEM encoded CA bundle, written to ca.crt.
+	// +optional
+	CAFile string `json:"caFile,omitempty"`
+	// PrivateKeyFormat is the encoding of tls.key. PKCS8 writes a "PRIVATE KEY" block,
+	// PKCS1 writes an "RSA PRIVATE KEY" or "EC PRIVATE KEY" block. Defaults to PKCS8.
+	// +kubebuilder:validation:Enum=PKCS8;PKCS1
+	// +optional
+	PrivateKeyFormat string `json:"privateKeyFormat,omitempty"`
+}
+
+// KeystoreConfig configures generation of PKCS#12 and Java keystores from PEM encoded certificates.
+type KeystoreConfig struct {
+	// CertificateField is the label of the field containing the PEM encoded certificate chain.
+	// +optional
+	CertificateField string `json:"certificateField,omitempty"`
+	// CertificateFile is the name of the file attachment containing the PEM encoded certificate chain.
+	// +optional
+	CertificateFile string `json:"certificateFile,omitempty"`
+	// PrivateKeyField is the label of the field containing the PEM encoded private key.
+	// +optional
+	PrivateKeyField string `json:"privateKeyField,omitempty"`
+	// PrivateKeyFile is the name of the file attachment containing the PEM encoded private key.
+	// +optional
+	PrivateKeyFile string `json:"privateKeyFile,omitempty"`
+	// CAField is the label of the field containing the PEM encoded CA certificates for the truststore.
+	// +optional
+	CAField string `json:"caField,omitempty"`
+	// CAFile is the name of the file attachment containing the PEM encoded CA certificates for the truststore.
+	// +optional
+	CAFile string `json:"caFile,omitempty"`
+	// PasswordField is the label of the field containing the password of the stores.
+	// +kubebuilder:validation:MinLength=1
+	PasswordField string `json:"passwordField"`
+	// PasswordKey, when set, also writes the store password to the Secret under this key.
+	// +optional
+	PasswordKey string `json:"passwordKey,omitempty"`
+	// Alias is the alias of the private key entry. Defaults to "certificate".
+	// +optional
+	Alias string `json:"alias,omitempty"`
+	// Formats selects the keystore formats to generate. Defaults to both.
+	// +kubebuilder:validation:items:Enum=PKCS12;JKS
+	// +optional
+	Formats []string `json:"formats,omitempty"`
+}
+
+// SSHAuthConfig configures generation of a kubernetes.io/ssh-auth Secret from an SSH Key item.
+type SSHAuthConfig struct {
+	// PrivateKeyField is the label of the field containing the private key.
+	// When empty, the first field of type SSH key is used.
+	// +optional
+	PrivateKeyField string `json:"privateKeyField,omitempty"`
+	// IncludePublicKey adds the public key in authorized_keys format as ssh-publickey.
+	// +optional
+	IncludePublicKey bool `json:"includePublicKey,omitempty"`
+	// IncludeFingerprint adds the SHA256 fingerprint of the key as ssh-fingerprint.
+	// +optional
+	IncludeFingerprint bool `json:"includeFingerprint,omitempty"`
+}
+
+// BasicAuthConfig configures generation of a kubernetes.io/basic-auth Secret from a Login item.
+type BasicAuthConfig struct {
+	// UsernameField is the label of the field containing the username.
+	// When empty, the built-in username field of the item is used.
+	// +optional
+	UsernameField string `json:"usernameField,omitempty"`
+	// PasswordField is the label of the field containing the password.
+	// When empty, the built-in password field of the item is used.
+	// +optional
+	PasswordField string `json:"passwordField,omitempty"`
+}
+
+// HtpasswdConfig configures generation of a bcrypt htpasswd file for ingress basic auth.
+type HtpasswdConfig struct {
+	// Users lists the entries of the file.
+	// When empty, a single entry is generated from the username and password of the item of the resource.
+	// +optional
+	Users []HtpasswdUser `json:"users,omitempty"`
+	// Key is the Secret key the file is written to. Defaults to "auth", as ingress-nginx expects.
+	// +optional
+	Key string `json:"key,omitempty"`
+	// Cost is the bcrypt cost of the hashes. Defaults to 10.
+	// +kubebuilder:validation:Minimum=4
+	// +kubebuilder:validation:Maximum=16
+	// +optional
+	Cost int `json:"cost,omitempty"`
+}
+
+// HtpasswdUser maps the fields of an item to an htpasswd entry.
+type HtpasswdUser struct {
+	// ItemPath is the path of the 1Password item holding the credentials, in the format
+	// vaults/{vault}/items/{item}. Defaults to the itemPath of the resource.
+	// +optional
+	ItemPath string `json:"itemPath,omitempty"`
+	// UsernameField is the label of the field containing the username.
+	// When empty, the built-in username field of the item is used.
+	// +optional
+	UsernameField string `json:"usernameField,omitempty"`
+	// PasswordField is the label of the field containing the password.
+	// When empty, the built-in password field of the item is used.
+	// +optional
+	PasswordField string `json:"passwordField,omitempty"`
+}
+
+// DatabaseConfig configures generation of normalized connection keys and URIs from a Database item.
+type DatabaseConfig struct {
+	// Type overrides the database type read from the item's type field.
+	// +kubebuilder:validation:Enum=postgresql;mysql;mariadb;mongodb;redis
+	// +optional
+	Type string `json:"type,omitempty"`
+	// Params are added to the generated URIs as query parameters, e.g. sslmode: require.
+	// They take precedence over parameters from the item's connection options field.
+	// +optional
+	Params map[string]string `json:"params,omitempty"`
+}
+
+// AWSConfig configures generation of AWS shared credentials and config files.
+type AWSConfig struct {
+	// Profiles lists the profiles written to the files.
+	// When empty, a single "default" profile is generated from the item of the resource.
+	// +optional
+	Profiles []AWSProfileConfig `json:"profiles,omitempty"`
+}
+
+// AWSProfileConfig maps the fields of an API Credential item to an AWS profile.
+type AWSProfileConfig struct {
+	// Name is the name of the profile. Defaults to "default".
+	// +optional
+	Name string `json:"name,omitempty"`
+	// ItemPath is the path of the 1Password item holding the credentials, in the format
+	// vaults/{vault}/items/{item}. Defaults to the itemPath of the resource.
+	// +optional
+	ItemPath string `json:"itemPath,omitempty"`
+	// AccessKeyIDField is the label of the field containing the access key ID.
+	// When empty, a field labeled "access key id" or "username" is used.
+	// +optional
+	AccessKeyIDField string `json:"accessKeyIdField,omitempty"`
+	// SecretAccessKeyField is the label of the field containing the secret access key.
+	// When empty, a field labeled "secret access key", "credential" or "password" is used.
+	// +optional
+	SecretAccessKeyField string `json:"secretAccessKeyField,omitempty"`
+	// SessionTokenField is the label of the field containing the session token of temporary credentials.
+	// When empty, a field labeled "session token" is used if present.
+	// +optional
+	SessionTokenField string `json:"sessionTokenField,omitempty"`
+	// Region is the region of the profile, written to the config file. Takes precedence over RegionField.
+	// +optional
+	Region string `json:"region,omitempty"`
+	// RegionField is the label of the field containing the region.
+	// When both Region and RegionField are empty, a field labeled "region" is used if present.
+	// +optional
+	RegionField string `json:"regionField,omitempty"`
+}
+
+// GCPConfig configures generation of a GCP service account key file.
+type GCPConfig struct {
+	// KeyField is the label of the field containing the service account key JSON.
+	// +optional
+	KeyField string `json:"keyField,omitempty"`
+	// KeyFile is the name of the file attachment containing the service account key JSON.
+	// When both KeyField and KeyFile are empty, the first attached .json file is used.
+	// +optional
+	KeyFile string `json:"keyFile,omitempty"`
+	// Key is the Secret key the service account key is written to. Defaults to "key.json".
+	// +optional
+	Key string `json:"key,omitempty"`
+}
+
+// KubeconfigConfig configures generation of a kubeconfig from the server, CA and token of an item.
+type KubeconfigConfig struct {
+	// ServerField is the label of the field containing the API server URL.
+	// When empty, a field labeled "server" is used, falling back to the primary URL of the item.
+	// +optional
+	ServerField string `json:"serverField,omitempty"`
+	// CAField is the label of the field containing the PEM encoded CA bundle of the API server.
+	// +optional
+	CAField string `json:"caField,omitempty"`
+	// CAFile is the name of the file attachment containing the PEM encoded CA bundle of the API server.
+	// When both CAField and CAFile are empty, a field labeled "certificate authority" or "ca" is used if present.
+	// +optional
+	CAFile string `json:"caFile,omitempty"`
+	// TokenField is the label of the field containing the bearer token.
+	// When empty, a field labeled "token" or "credential" is used.
+	// +optional
+	TokenField string `json:"tokenField,omitempty"`
+	// Name is the name of the cluster, user and context. Defaults to "default".
+	// +optional
+	Name string `json:"name,omitempty"`
+	// Namespace is the default namespace of the context.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+	// Key is the Secret key the kubeconfig is written to. Defaults to "kubeconfig".
+	// +optional
+	Key string `json:"key,omitempty"`
+}
+
+// Key naming strategies of the default mapping.
+const (
+	KeyNamingLabel        = "label"
+	KeyNamingSectionLabel = "section.label"
+	KeyNamingID           = "id"
+)
+
+// Collision policies of the default mapping.
+const (
+	KeyCollisionError  = "error"
+	KeyCollisionFirst  = "first"
+	KeyCollisionLast   = "last"
+	KeyCollisionSuffix = "suffix"
+)
+
+// KeyStrategy configures how the default mapping names the Secret data keys of fields,
+// URLs and files, and what happens when several of them map to the same key.
+type KeyStrategy struct {
+	// Naming selects the key of a field: its label, its section title and label joined with a dot
+	// (fields outside of a section keep their label), or its ID. URLs and files are always keyed
+	// by their label and name. Defaults to label.
+	// +kubebuilder:validation:Enum=label;section.label;id
+	// +optional
+	Naming string `json:"naming,omitempty"`
+
+	// OnCollision selects what happens when several values map to the same key, in the order
+	// fields, URLs, files: fail the sync, keep the first or the last value, or keep the first and
+	// add the others with a "_2", "_3", ... suffix. When unset, fields win over URLs and URLs over
+	// files, and the last of several fields wins.
+	// +kubebuilder:validation:Enum=error;first;last;suffix
+	// +optional
+	OnCollision string `json:"onCollision,omitempty"`
+}
+
+// NamingOrDefault returns the key naming strategy, defaulting to label.
+func (k *KeyStrategy) NamingOrDefault() string {
+	if k == nil || k.Naming == "" {
+		return KeyNamingLabel
+	}
+	return k.Naming
+}
+
+// Key cases of a KeyTransform.
+const (
+	KeyCaseUpperSnake = "UPPER_SNAKE"
+	KeyCaseLowerSnake = "lower_snake"
+	KeyCaseCamel      = "camelCase"
+	KeyCaseKebab      = "kebab"
+)
+
+// KeyTransform rewrites the keys of the default mapping, e.g. into environment variable names.
+type KeyTransform struct {
+	// Case splits the key into words at non-alphanumeric characters and case changes, and joins
+	// them in the given case, e.g. "API Key" becomes API_KEY with UPPER_SNAKE.
+	// +kubebuilder:validation:Enum=UPPER_SNAKE;lower_snake;camelCase;kebab
+	// +optional
+	Case string `json:"case,omitempty"`
+
+	// Prefix is prepended to every key after the case is applied.
+	// +kubebuilder:validation:Pattern=`^[-._a-zA-Z0-9]*$`
+	// +optional
+	Prefix string `json:"prefix,omitempty"`
+
+	// Suffix is appended to every key after the case is applied.
+	// +kubebuilder:validation:Pattern=`^[-._a-zA-Z0-9]*$`
+	// +optional
+	Suffix string `json:"suffix,omitempty"`
+}
+
+// FieldTypeNotes selects the notes field of an item in FieldTypeRules, which is a STRING field
+// with the NOTES purpose.
+const FieldTypeNotes = "NOTES"
+
+// FieldTypeRules selects the fields of the default mapping by type and controls how their values are rendered.
+type FieldTypeRules struct {
+	// Include only exports fields of these types. NOTES selects the notes field of the item.
+	// +kubebuilder:validation:items:Enum=STRING;CONCEALED;EMAIL;URL;OTP;DATE;MONTH_YEAR;MENU;ADDRESS;PHONE;REFERENCE;CREDIT_CARD_NUMBER;CREDIT_CARD_TYPE;SSH_KEY;UNKNOWN;NOTES
+	// +optional
+	Include []string `json:"include,omitempty"`
+
+	// Exclude skips fields of these types. NOTES selects the notes field of the item.
+	// +kubebuilder:validation:items:Enum=STRING;CONCEALED;EMAIL;URL;OTP;DATE;MONTH_YEAR;MENU;ADDRESS;PHONE;REFERENCE;CREDIT_CARD_NUMBER;CREDIT_CARD_TYPE;SSH_KEY;UNKNOWN;NOTES
+	// +optional
+	Exclude []string `json:"exclude,omitempty"`
+
+	// FormatValues renders values according to their type: dates as RFC 3339, month/year fields
+	// as "2006-01", OTP fields as the TOTP secret of their otpauth:// URI and addresses on a single line.
+	// +optional
+	FormatValues bool `json:"formatValues,omitempty"`
+}
+
+// FilesConfig selects the file attachments of the item used for the Secret.
+type FilesConfig struct {
+	// Select lists the attachments to use. Attachments that are not selected are never downloaded.
+	// When empty, every attachment is used.
+	// +optional
+	Select []FileSelector `json:"select,omitempty"`
+
+	// MaxSize is the maximum size of a selected attachment. Larger attachments fail the sync
+	// before they are downloaded.
+	// +optional
+	MaxSize *resource.Quantity `json:"maxSize,omitempty"`
+}
+
+// FileSelector selects file attachments by name.
+type FileSelector struct {
+	// Name of the attachment, or a glob pattern such as "*.pem". A name without glob characters
+	// must match an attachment.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Key renames the attachment, as if it had this file name. Only valid when Name matches a single attachment.
+	// +kubebuilder:validation:Pattern=`^[-._a-zA-Z0-9]+$`
+	// +optional
+	Key string `json:"key,omitempty"`
+}
+
+// KeyCollision reports values of the item that map to the same Secret data key.
+type KeyCollision struct {
+	// Key is the Secret data key the values map to.
+	Key string `json:"key"`
+	// Sources describes the colliding values in the order they are considered,
+	// e.g. `field "password" (section "db")`, `url "website"` or `file "config.json"`.
+	Sources []string `json:"sources"`
 }
 
 // OnePasswordItemSpec defines the desired state of OnePasswordItem
@@ -63,6 +531,12 @@ type OnePasswordItemSpec struct {
 
 	ItemPath string `json:"itemPath,omitempty"`
 
+	// ConnectionRef selects the OnePasswordConnection or ClusterOnePasswordConnection the items are
+	// read with. When empty, the operator.1password.io/connection annotation is used, and then the
+	// credentials the operator was started with.
+	// +optional
+	ConnectionRef *ConnectionReference `json:"connectionRef,omitempty"`
+
 	// Template defines Go templates for generating custom secret data.
 	// When set, the secret data will be generated by rendering the templates
 	// instead of using the default 1:1 field-to-key mapping.
@@ -74,6 +548,97 @@ type OnePasswordItemSpec struct {
 	// mapped by the config, and sets the secret type to kubernetes.io/dockerconfigjson.
 	// +optional
 	ImagePullSecret *ImagePullSecretConfig `json:"imagePullSecret,omitempty"`
+
+	// TLS configures generation of a kubernetes.io/tls Secret with tls.crt, tls.key and ca.crt.
+	// The PEM data is validated and normalized, and the secret type is set to kubernetes.io/tls.
+	// +optional
+	TLS *TLSConfig `json:"tls,omitempty"`
+
+	// Keystore configures generation of keystore.p12/truststore.p12 and keystore.jks/truststore.jks
+	// from the PEM encoded certificate, private key and CA of the item.
+	// +optional
+	Keystore *KeystoreConfig `json:"keystore,omitempty"`
+
+	// SSHAuth configures generation of a kubernetes.io/ssh-auth Secret with the private key in OpenSSH format.
+	// +optional
+	SSHAuth *SSHAuthConfig `json:"sshAuth,omitempty"`
+
+	// BasicAuth configures generation of a kubernetes.io/basic-auth Secret with username and password keys.
+	// +optional
+	BasicAuth *BasicAuthConfig `json:"basicAuth,omitempty"`
+
+	// Htpasswd configures generation of a bcrypt htpasswd file, e.g. for ingress-nginx or Traefik basic auth.
+	// +optional
+	Htpasswd *HtpasswdConfig `json:"htpasswd,omitempty"`
+
+	// Database configures generation of normalized keys (type, host, port, database, username, password)
+	// and connection URIs from a Database item.
+	// +optional
+	Database *DatabaseConfig `json:"database,omitempty"`
+
+	// AWS configures generation of AWS shared credentials and config files from API Credential items.
+	// +optional
+	AWS *AWSConfig `json:"aws,omitempty"`
+
+	// GCP configures generation of a validated GCP service account key file.
+	// +optional
+	GCP *GCPConfig `json:"gcp,omitempty"`
+
+	// Kubeconfig configures generation of a kubeconfig from server, CA and token fields.
+	// +optional
+	Kubeconfig *KubeconfigConfig `json:"kubeconfig,omitempty"`
+
+	// KeyStrategy configures the keys of the default mapping of fields, URLs and files,
+	// and how values mapping to the same key are handled.
+	// +optional
+	KeyStrategy *KeyStrategy `json:"keyStrategy,omitempty"`
+
+	// KeyTransform rewrites the keys of the default mapping of fields, URLs and files,
+	// e.g. into environment variable names for envFrom.
+	// +optional
+	KeyTransform *KeyTransform `json:"keyTransform,omitempty"`
+
+	// FieldTypes selects the fields of the default mapping by type, e.g. only concealed fields,
+	// and can render dates, OTP fields and addresses according to their type.
+	// +optional
+	FieldTypes *FieldTypeRules `json:"fieldTypes,omitempty"`
+
+	// Files selects and renames the file attachments of the item, and limits their size.
+	// The selection applies to every output format.
+	// +optional
+	Files *FilesConfig `json:"files,omitempty"`
+}
+
+// ReferencedItemPaths returns the paths of the items, other than ItemPath, the spec reads fields from.
+func (s *OnePasswordItemSpec) ReferencedItemPaths() []string {
+	if s == nil {
+		return nil
+	}
+
+	var paths []string
+	seen := map[string]bool{s.ItemPath: true}
+	add := func(path string) {
+		if path != "" && !seen[path] {
+			seen[path] = true
+			paths = append(paths, path)
+		}
+	}
+	if s.ImagePullSecret != nil {
+		for _, registry := range s.ImagePullSecret.Registries {
+			add(registry.ItemPath)
+		}
+	}
+	if s.Htpasswd != nil {
+		for _, user := range s.Htpasswd.Users {
+			add(user.ItemPath)
+		}
+	}
+	if s.AWS != nil {
+		for _, profile := range s.AWS.Profiles {
+			add(profile.ItemPath)
+		}
+	}
+	return paths
 }
 
 type OnePasswordItemConditionType string
@@ -81,6 +646,8 @@ type OnePasswordItemConditionType string
 const (
 	// OnePasswordItemReady means the Kubernetes secret is ready for use.
 	OnePasswordItemReady OnePasswordItemConditionType = "Ready"
+	// OnePasswordItemBackendUnavailable means 1Password could not be reached to sync the Kubernetes secret.
+	OnePasswordItemBackendUnavailable OnePasswordItemConditionType = "BackendUnavailable"
 )
 
 type OnePasswordItemCondition struct {
@@ -102,6 +669,10 @@ type OnePasswordItemStatus struct {
 	// Important: Run "make" to regenerate code after modifying this file
 
 	Conditions []OnePasswordItemCondition `json:"conditions"`
+
+	// KeyCollisions lists the values of the item that map to the same Secret data key.
+	// +optional
+	KeyCollisions []KeyCollision `json:"keyCollisions,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -0,0 +1,68 @@
+/*
+MIT License
+
+Copyright (c) 2020-2024 1Password
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// OnePasswordTemplateSpec defines the templates shared by the resources referencing a
+// OnePasswordTemplate or ClusterOnePasswordTemplate.
+type OnePasswordTemplateSpec struct {
+	// Data is a map of secret data key names to Go template strings, rendered like
+	// spec.template.data of a OnePasswordItem.
+	// +optional
+	Data map[string]string `json:"data,omitempty"`
+
+	// Partials are named Go templates that can be included in Data with {{ template "<name>" . }}.
+	// +optional
+	Partials map[string]string `json:"partials,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+// +kubebuilder:resource:shortName=opt
+
+// OnePasswordTemplate is the Schema for the onepasswordtemplates API.
+// It holds templates that OnePasswordItems in the same namespace reference with spec.template.ref.
+type OnePasswordTemplate struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec OnePasswordTemplateSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// OnePasswordTemplateList contains a list of OnePasswordTemplate
+type OnePasswordTemplateList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OnePasswordTemplate `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&OnePasswordTemplate{}, &OnePasswordTemplateList{})
+}
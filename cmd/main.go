@@ -49,6 +49,7 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/certwatcher"
+	"sigs.k8s.io/controller-runtime/pkg/cluster"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	"sigs.k8s.io/controller-runtime/pkg/metrics/filters"
@@ -103,6 +104,11 @@ func main() {
 	var enableAnnotations bool
 	var allowEmptyValues bool
 	var tlsOpts []func(*tls.Config)
+	connectTransport, err := op.ConnectTransportFromEnvironment()
+	if err != nil {
+		setupLog.Error(err, "invalid 1Password Connect configuration")
+		os.Exit(1)
+	}
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080",
 		"The address the metrics endpoint binds to. "+
 			"Use :8443 for HTTPS or :8080 for HTTP, or leave as 0 to disable the metrics service.")
@@ -126,6 +132,21 @@ func main() {
 	// NOTE: Empty values are available only when using the Connect. SDK doesn't return fields with empty values.
 	flag.BoolVar(&allowEmptyValues, "allow-empty-values", false,
 		"(Connect Only) If set, empty field values from 1Password items will be included in Kubernetes secrets.")
+	flag.StringVar(&connectTransport.CAFile, "connect-ca-file", connectTransport.CAFile,
+		"(Connect Only) PEM encoded CA bundle trusted for the Connect server, in addition to the system roots.")
+	flag.StringVar(&connectTransport.ClientCertificateFile, "connect-client-cert-file",
+		connectTransport.ClientCertificateFile, "(Connect Only) PEM encoded client certificate for mutual TLS.")
+	flag.StringVar(&connectTransport.ClientKeyFile, "connect-client-key-file", connectTransport.ClientKeyFile,
+		"(Connect Only) PEM encoded client key for mutual TLS.")
+	flag.StringVar(&connectTransport.ProxyURL, "connect-proxy-url", connectTransport.ProxyURL,
+		"(Connect Only) URL of the proxy requests to Connect are sent through. Defaults to HTTPS_PROXY.")
+	flag.DurationVar(&connectTransport.Timeout, "connect-timeout", connectTransport.Timeout,
+		"(Connect Only) Timeout of every request to Connect. 0 means no timeout.")
+	flag.DurationVar(&connectTransport.OperationTimeout, "connect-operation-timeout", connectTransport.OperationTimeout,
+		"(Connect Only) Timeout of every operation on Connect, including all its requests and retries. "+
+			"0 means no timeout.")
+	flag.StringVar(&connectTransport.UserAgent, "connect-user-agent", connectTransport.UserAgent,
+		"(Connect Only) User agent sent to Connect.")
 	opts := zap.Options{
 		Development: true,
 	}
@@ -289,19 +310,74 @@ func main() {
 	}
 
 	// Setup One Password Client
-	opClient, err := opclient.NewFromEnvironment(ctx, opclient.Config{
+	opClientConfig := opclient.Config{
 		Logger:  setupLog,
 		Version: version.OperatorVersion,
-	})
-	if err != nil {
-		setupLog.Error(err, "unable to create 1Password client")
+		Backend: os.Getenv(opclient.BackendEnv),
+	}
+	var opClient opclient.Client
+	lazyOpClient, err := newLazyOpClient(ctx, mgr, opClientConfig, deploymentNamespace, connectTransport)
+	if errors.Is(err, opclient.ErrNoCredentials) {
+		// Resources can still select a OnePasswordConnection.
+		setupLog.Info("No default 1Password credentials set. Only resources selecting a connection are synced")
+	} else {
+		if err != nil {
+			setupLog.Error(err, "1Password is unavailable, retrying in the background. Items are requeued until it is available")
+		}
+		if err := mgr.AddReadyzCheck("onepassword", lazyOpClient.Check); err != nil {
+			setupLog.Error(err, "unable to set up the 1Password ready check")
+			os.Exit(1)
+		}
+		opClient = lazyOpClient
+	}
+	var shadow *op.ShadowComparer
+	if shadowBackend := os.Getenv(opclient.ShadowBackendEnv); shadowBackend != "" {
+		shadowConfig := opClientConfig
+		shadowConfig.Backend = shadowBackend
+		shadowClient, err := newLazyOpClient(ctx, mgr, shadowConfig, deploymentNamespace, connectTransport)
+		if errors.Is(err, opclient.ErrNoCredentials) {
+			setupLog.Error(err, "unable to create the 1Password client of the shadow backend")
+			os.Exit(1)
+		} else if err != nil {
+			setupLog.Error(err, "The shadow backend is unavailable, retrying in the background")
+		}
+		setupLog.Info("Comparing Secrets with the shadow backend, without changing them", "backend", shadowBackend)
+		shadow = op.NewShadowComparer(shadowClient, mgr.GetEventRecorderFor("onepassword-operator-shadow"))
+	}
+	// Connections and their token Secrets are read from a cache. The token Secret of a
+	// ClusterOnePasswordConnection may be outside the watched namespaces, so a separate cache covering
+	// every namespace is used when they are restricted.
+	var connectionsCluster cluster.Cluster = mgr
+	if watchNamespace != "" {
+		connectionsCluster, err = cluster.New(mgr.GetConfig(), func(o *cluster.Options) {
+			o.Scheme = mgr.GetScheme()
+		})
+		if err != nil {
+			setupLog.Error(err, "unable to create the cache of the connections")
+			os.Exit(1)
+		}
+		if err := mgr.Add(connectionsCluster); err != nil {
+			setupLog.Error(err, "unable to add the cache of the connections")
+			os.Exit(1)
+		}
+	}
+	connections := op.NewConnectionClients(connectionsCluster.GetClient(),
+		func(ctx context.Context, credentials opclient.Credentials) (opclient.Client, error) {
+			// The backend of a connection is selected from its credentials, not by OP_BACKEND.
+			connectionConfig := opClientConfig
+			connectionConfig.Backend = ""
+			return newConnectionOpClient(ctx, connectionConfig, credentials)
+		})
+	if err := connections.EvictDeletedConnections(ctx, connectionsCluster.GetCache()); err != nil {
+		setupLog.Error(err, "unable to watch the connections")
 		os.Exit(1)
 	}
 
 	if err = (&controller.OnePasswordItemReconciler{
-		Client:   mgr.GetClient(),
-		Scheme:   mgr.GetScheme(),
-		OpClient: opClient,
+		Client:      mgr.GetClient(),
+		Scheme:      mgr.GetScheme(),
+		OpClient:    opClient,
+		Connections: connections,
 		Config: controller.ReconcilerConfig{
 			EnableAnnotations: enableAnnotations,
 			AllowEmptyValues:  allowEmptyValues,
@@ -311,11 +387,27 @@ func main() {
 		os.Exit(1)
 	}
 
+	if err = (&controller.ClusterOnePasswordItemReconciler{
+		Client:      mgr.GetClient(),
+		Scheme:      mgr.GetScheme(),
+		OpClient:    opClient,
+		Connections: connections,
+		Config: controller.ReconcilerConfig{
+			EnableAnnotations: enableAnnotations,
+			AllowEmptyValues:  allowEmptyValues,
+		},
+		WatchedNamespaces: watchedNamespaces,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "ClusterOnePasswordItem")
+		os.Exit(1)
+	}
+
 	r, _ := regexp.Compile(annotationRegExpString)
 	if err = (&controller.DeploymentReconciler{
 		Client:             mgr.GetClient(),
 		Scheme:             mgr.GetScheme(),
 		OpClient:           opClient,
+		Connections:        connections,
 		OpAnnotationRegExp: r,
 		Recorder:           mgr.GetEventRecorderFor("onepassword-operator-deployment"),
 		Config: controller.ReconcilerConfig{
@@ -353,11 +445,12 @@ func main() {
 
 	// Setup update secrets task
 	updatedSecretsPoller := op.NewSecretUpdateHandler(
-		mgr.GetClient(), mgr.GetAPIReader(), opClient,
+		mgr.GetClient(), mgr.GetAPIReader(), opClient, connections,
 		op.SecretUpdateHandlerConfig{
 			ShouldAutoRestartWorkloadsGlobally: shouldAutoRestartWorkloads(),
 			AllowEmptyValues:                   allowEmptyValues,
 			WatchedNamespaces:                  watchedNamespaces,
+			Shadow:                             shadow,
 		})
 	done := make(chan bool)
 	ticker := time.NewTicker(getPollingIntervalForUpdatingSecrets())
@@ -400,6 +493,88 @@ func main() {
 	}
 }
 
+// newLazyOpClient creates a 1Password client with newOpClient, retrying in the background while the manager
+// runs when it fails, so the operator starts while 1Password is unreachable. The error of the first attempt
+// is returned with the client, except for ErrNoCredentials, which is not retried.
+func newLazyOpClient(
+	ctx context.Context,
+	mgr ctrl.Manager,
+	cfg opclient.Config,
+	namespace string,
+	connectTransport opclient.ConnectTransportSource,
+) (*opclient.LazyClient, error) {
+	lazyClient := opclient.NewLazyClient(cfg.Logger, func(ctx context.Context) (opclient.Client, error) {
+		return newOpClient(ctx, mgr, cfg, namespace, connectTransport)
+	})
+	err := lazyClient.Init(ctx)
+	if errors.Is(err, opclient.ErrNoCredentials) {
+		return nil, err
+	}
+	if err != nil && !opclient.IsTransientError(err) {
+		// Retrying can't fix the configuration, e.g. an unknown backend or an invalid certificate.
+		setupLog.Error(err, "invalid 1Password configuration", "backend", cfg.Backend)
+		os.Exit(1)
+	}
+	if err := mgr.Add(lazyClient); err != nil {
+		setupLog.Error(err, "unable to retry creating the 1Password client")
+		os.Exit(1)
+	}
+	return lazyClient, err
+}
+
+// newConnectionOpClient creates the client of a connection. Like the default client, it wraps the failures
+// of the backend with opclient.ErrBackendUnavailable, so the items selecting the connection are requeued
+// until it is available. Connections are not part of the readiness of the operator.
+func newConnectionOpClient(
+	ctx context.Context,
+	cfg opclient.Config,
+	credentials opclient.Credentials,
+) (opclient.Client, error) {
+	lazyClient := opclient.NewLazyClient(cfg.Logger, func(ctx context.Context) (opclient.Client, error) {
+		return opclient.New(ctx, cfg, credentials)
+	})
+	if err := lazyClient.Init(ctx); err != nil {
+		if opclient.IsTransientError(err) {
+			return nil, fmt.Errorf("%w: %w", opclient.ErrBackendUnavailable, err)
+		}
+		return nil, err
+	}
+	return lazyClient, nil
+}
+
+// newOpClient creates the default 1Password client. When a token is read from a file or Secret, or Connect
+// certificates are read from files, the client reloads them while the manager runs, so rotated credentials
+// are used without restarting the operator.
+func newOpClient(
+	ctx context.Context,
+	mgr ctrl.Manager,
+	cfg opclient.Config,
+	namespace string,
+	connectTransport opclient.ConnectTransportSource,
+) (opclient.Client, error) {
+	sources, reloadable, err := op.CredentialSourcesFromEnvironment(mgr.GetAPIReader(), namespace)
+	if err != nil {
+		return nil, err
+	}
+	sources.ConnectTransport = connectTransport
+	if !reloadable && len(connectTransport.Files()) == 0 {
+		credentials, err := sources.Credentials(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return opclient.New(ctx, cfg, credentials)
+	}
+
+	reloadingClient, err := opclient.NewReloadingClient(ctx, cfg, sources, opclient.DefaultReloadInterval)
+	if err != nil {
+		return nil, err
+	}
+	if err := mgr.Add(reloadingClient); err != nil {
+		return nil, fmt.Errorf("unable to watch 1Password credentials: %w", err)
+	}
+	return reloadingClient, nil
+}
+
 // getWatchNamespace returns the Namespace the operator should be watching for changes
 func getWatchNamespace() (string, error) {
 	// WatchNamespaceEnvVar is the constant for env variable WATCH_NAMESPACE
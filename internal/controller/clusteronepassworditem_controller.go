@@ -0,0 +1,474 @@
+/*
+MIT License
+
+Copyright (c) 2020-2024 1Password
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	onepasswordv1 "github.com/1Password/onepassword-operator/api/v1"
+	kubeSecrets "github.com/1Password/onepassword-operator/pkg/kubernetessecrets"
+	"github.com/1Password/onepassword-operator/pkg/logs"
+	op "github.com/1Password/onepassword-operator/pkg/onepassword"
+	opclient "github.com/1Password/onepassword-operator/pkg/onepassword/client"
+	"github.com/1Password/onepassword-operator/pkg/onepassword/model"
+	"github.com/1Password/onepassword-operator/pkg/utils"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var logClusterOnePasswordItem = logf.Log.WithName("controller_clusteronepassworditem")
+
+// ClusterOnePasswordItemReconciler reconciles a ClusterOnePasswordItem object
+type ClusterOnePasswordItemReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	OpClient opclient.Client
+	// Connections builds the clients of items selecting a ClusterOnePasswordConnection.
+	Connections *op.ConnectionClients
+	Config      ReconcilerConfig
+	// WatchedNamespaces restricts the namespaces Secrets are created in when the operator
+	// only watches a subset of namespaces. An empty list means all namespaces.
+	WatchedNamespaces []string
+}
+
+// +kubebuilder:rbac:groups=onepassword.com,resources=clusteronepassworditems,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=onepassword.com,resources=clusteronepassworditems/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=onepassword.com,resources=clusteronepassworditems/finalizers,verbs=update
+// +kubebuilder:rbac:groups=onepassword.com,resources=clusteronepasswordtemplates,verbs=get;list;watch
+// +kubebuilder:rbac:groups=onepassword.com,resources=clusteronepasswordconnections,verbs=get;list;watch
+
+// Reconcile creates or updates the Secret described by a ClusterOnePasswordItem in every
+// namespace matched by its namespace selector, and removes it from namespaces that no longer match.
+func (r *ClusterOnePasswordItemReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	reqLogger := logClusterOnePasswordItem.WithValues("Request.Name", req.Name)
+	reqLogger.V(logs.DebugLevel).Info("Reconciling ClusterOnePasswordItem")
+
+	clusterItem := &onepasswordv1.ClusterOnePasswordItem{}
+	err := r.Get(ctx, req.NamespacedName, clusterItem)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	if clusterItem.DeletionTimestamp.IsZero() {
+		// Adds a finalizer so the Secrets in every namespace can be cleaned up on deletion.
+		if !utils.ContainsString(clusterItem.Finalizers, finalizer) {
+			clusterItem.Finalizers = append(clusterItem.Finalizers, finalizer)
+			if err = r.Update(ctx, clusterItem); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+
+		namespaceStatuses, err := r.handleClusterOnePasswordItem(ctx, clusterItem)
+		if err != nil {
+			if strings.Contains(err.Error(), "rate limit") {
+				reqLogger.V(logs.InfoLevel).Info("1Password rate limit hit. Requeuing after 15 minutes.")
+				return ctrl.Result{RequeueAfter: 15 * time.Minute}, nil
+			}
+		}
+		if updateStatusErr := r.updateStatus(ctx, clusterItem, namespaceStatuses, err); updateStatusErr != nil {
+			return ctrl.Result{}, fmt.Errorf("cannot update status: %s", updateStatusErr)
+		}
+		if opclient.IsBackendUnavailable(err) {
+			reqLogger.V(logs.InfoLevel).Info("1Password is unavailable. Requeuing.", "requeueAfter", backendUnavailableRequeueDelay.String())
+			return ctrl.Result{RequeueAfter: backendUnavailableRequeueDelay}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	if utils.ContainsString(clusterItem.Finalizers, finalizer) {
+		if err = r.cleanupKubernetesSecrets(ctx, clusterItem, nil); err != nil {
+			return ctrl.Result{}, err
+		}
+
+		clusterItem.Finalizers = utils.RemoveString(clusterItem.Finalizers, finalizer)
+		if err = r.Update(ctx, clusterItem); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	return ctrl.Result{}, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *ClusterOnePasswordItemReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	err := mgr.GetFieldIndexer().IndexField(context.Background(), &onepasswordv1.ClusterOnePasswordItem{},
+		templateRefIndex, func(obj client.Object) []string {
+			return templateRefIndexValues(&obj.(*onepasswordv1.ClusterOnePasswordItem).Spec.OnePasswordItemSpec)
+		})
+	if err != nil {
+		return err
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&onepasswordv1.ClusterOnePasswordItem{}).
+		Watches(
+			&corev1.Namespace{},
+			handler.EnqueueRequestsFromMapFunc(r.requestsForNamespace),
+			builder.WithPredicates(namespaceLabelsChangedPredicate()),
+		).
+		Watches(
+			&corev1.ServiceAccount{},
+			handler.EnqueueRequestsFromMapFunc(r.requestsForServiceAccount),
+			builder.WithPredicates(serviceAccountCreatedOrRelabeledPredicate()),
+		).
+		Watches(
+			&onepasswordv1.ClusterOnePasswordTemplate{},
+			handler.EnqueueRequestsFromMapFunc(r.requestsForTemplate),
+		).
+		Named("clusteronepassworditem").
+		Complete(r)
+}
+
+// requestsForNamespace enqueues every ClusterOnePasswordItem when a namespace is created, deleted
+// or relabeled. All of them are enqueued because a relabeled namespace may stop matching a selector.
+func (r *ClusterOnePasswordItemReconciler) requestsForNamespace(ctx context.Context, _ client.Object) []reconcile.Request {
+	clusterItems := &onepasswordv1.ClusterOnePasswordItemList{}
+	if err := r.List(ctx, clusterItems); err != nil {
+		logClusterOnePasswordItem.Error(err, "Failed to list ClusterOnePasswordItems")
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0, len(clusterItems.Items))
+	for _, clusterItem := range clusterItems.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&clusterItem)})
+	}
+	return requests
+}
+
+// requestsForServiceAccount enqueues the ClusterOnePasswordItems that attach their image pull secret
+// to ServiceAccounts, so new or relabeled ServiceAccounts are picked up.
+func (r *ClusterOnePasswordItemReconciler) requestsForServiceAccount(ctx context.Context, _ client.Object) []reconcile.Request {
+	clusterItems := &onepasswordv1.ClusterOnePasswordItemList{}
+	if err := r.List(ctx, clusterItems); err != nil {
+		logClusterOnePasswordItem.Error(err, "Failed to list ClusterOnePasswordItems")
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, clusterItem := range clusterItems.Items {
+		if clusterItem.Spec.ImagePullSecret.AttachesToServiceAccounts() {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&clusterItem)})
+		}
+	}
+	return requests
+}
+
+// requestsForTemplate enqueues the ClusterOnePasswordItems referencing the ClusterOnePasswordTemplate,
+// so their Secrets are rendered again when the template changes.
+func (r *ClusterOnePasswordItemReconciler) requestsForTemplate(ctx context.Context, tmpl client.Object) []reconcile.Request {
+	clusterItems := &onepasswordv1.ClusterOnePasswordItemList{}
+	ref := templateRefIndexValue(onepasswordv1.ClusterOnePasswordTemplateKind, tmpl.GetName())
+	if err := r.List(ctx, clusterItems, client.MatchingFields{templateRefIndex: ref}); err != nil {
+		logClusterOnePasswordItem.Error(err, "Failed to list ClusterOnePasswordItems referencing template", "Template", ref)
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0, len(clusterItems.Items))
+	for _, clusterItem := range clusterItems.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&clusterItem)})
+	}
+	return requests
+}
+
+// namespaceLabelsChangedPredicate ignores namespace updates that do not change its labels.
+func namespaceLabelsChangedPredicate() predicate.Predicate {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return !reflect.DeepEqual(e.ObjectOld.GetLabels(), e.ObjectNew.GetLabels())
+		},
+		GenericFunc: func(event.GenericEvent) bool {
+			return false
+		},
+	}
+}
+
+func (r *ClusterOnePasswordItemReconciler) handleClusterOnePasswordItem(
+	ctx context.Context,
+	clusterItem *onepasswordv1.ClusterOnePasswordItem,
+) ([]onepasswordv1.ClusterOnePasswordItemNamespaceStatus, error) {
+	selector, err := metav1.LabelSelectorAsSelector(&clusterItem.Spec.NamespaceSelector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid namespaceSelector: %w", err)
+	}
+
+	namespaces, err := r.selectNamespaces(ctx, selector)
+	if err != nil {
+		return nil, err
+	}
+
+	// Remove Secrets from namespaces that no longer match the selector.
+	selected := make(map[string]bool, len(namespaces))
+	for _, ns := range namespaces {
+		selected[ns] = true
+	}
+	if err = r.cleanupKubernetesSecrets(ctx, clusterItem, selected); err != nil {
+		return nil, err
+	}
+
+	if len(namespaces) == 0 {
+		return nil, nil
+	}
+
+	connection, err := op.ConnectionReferenceFor(&clusterItem.Spec.OnePasswordItemSpec, clusterItem.Annotations)
+	if err != nil {
+		return nil, err
+	}
+	opClient, err := op.ClientForConnection(ctx, r.Connections, r.OpClient, "", connection)
+	if err != nil {
+		return nil, err
+	}
+
+	item, err := op.GetOnePasswordItemByPath(ctx, opClient, clusterItem.Spec.ItemPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve item: %w", err)
+	}
+	linkedItems, err := op.GetReferencedItems(ctx, opClient, &clusterItem.Spec.OnePasswordItemSpec)
+	if err != nil {
+		return nil, err
+	}
+	spec, err := kubeSecrets.ResolveSecretTemplate(ctx, r.Client, "", &clusterItem.Spec.OnePasswordItemSpec)
+	if err != nil {
+		return nil, err
+	}
+	clusterItem.Status.KeyCollisions = kubeSecrets.KeyCollisions(*item, r.Config.AllowEmptyValues, spec)
+
+	var failed []string
+	statuses := make([]onepasswordv1.ClusterOnePasswordItemNamespaceStatus, 0, len(namespaces))
+	for _, ns := range namespaces {
+		status := onepasswordv1.ClusterOnePasswordItemNamespaceStatus{
+			Namespace: ns,
+			Status:    metav1.ConditionTrue,
+		}
+		if err := r.createKubernetesSecret(ctx, clusterItem, spec, connection, ns, item, linkedItems); err != nil {
+			logClusterOnePasswordItem.Error(err, "Failed to sync secret", "Namespace", ns)
+			status.Status = metav1.ConditionFalse
+			status.Message = err.Error()
+			failed = append(failed, ns)
+		}
+		statuses = append(statuses, status)
+	}
+
+	if len(failed) > 0 {
+		return statuses, fmt.Errorf("failed to sync secret in namespaces: %s", strings.Join(failed, ", "))
+	}
+	return statuses, nil
+}
+
+// selectNamespaces returns the sorted names of the active namespaces matching the selector.
+func (r *ClusterOnePasswordItemReconciler) selectNamespaces(ctx context.Context, selector labels.Selector) ([]string, error) {
+	namespaceList := &corev1.NamespaceList{}
+	if err := r.List(ctx, namespaceList, client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		return nil, fmt.Errorf("failed to list namespaces: %w", err)
+	}
+
+	var namespaces []string
+	for _, ns := range namespaceList.Items {
+		if ns.Status.Phase == corev1.NamespaceTerminating || !ns.DeletionTimestamp.IsZero() {
+			continue
+		}
+		if len(r.WatchedNamespaces) > 0 && !utils.ContainsString(r.WatchedNamespaces, ns.Name) {
+			continue
+		}
+		namespaces = append(namespaces, ns.Name)
+	}
+	sort.Strings(namespaces)
+	return namespaces, nil
+}
+
+func (r *ClusterOnePasswordItemReconciler) createKubernetesSecret(
+	ctx context.Context,
+	clusterItem *onepasswordv1.ClusterOnePasswordItem,
+	spec *onepasswordv1.OnePasswordItemSpec,
+	connection *onepasswordv1.ConnectionReference,
+	namespace string,
+	item *model.Item,
+	linkedItems map[string]*model.Item,
+) error {
+	if err := r.checkSecretOwner(ctx, clusterItem, namespace); err != nil {
+		return err
+	}
+
+	secretType := kubeSecrets.SecretTypeForSpec(clusterItem.Type, &clusterItem.Spec.OnePasswordItemSpec)
+
+	var annotations map[string]string
+	if r.Config.EnableAnnotations {
+		annotations = make(map[string]string, len(clusterItem.Annotations))
+		for k, v := range clusterItem.Annotations {
+			annotations[k] = v
+		}
+	}
+
+	secretLabels := make(map[string]string, len(clusterItem.Labels)+1)
+	for k, v := range clusterItem.Labels {
+		secretLabels[k] = v
+	}
+	secretLabels[op.ClusterItemLabel] = clusterItem.Name
+
+	gvk, err := apiutil.GVKForObject(clusterItem, r.Scheme)
+	if err != nil {
+		return fmt.Errorf("could not to retrieve group version kind: %w", err)
+	}
+	ownerRef := &metav1.OwnerReference{
+		APIVersion: gvk.GroupVersion().String(),
+		Kind:       gvk.Kind,
+		Name:       clusterItem.GetName(),
+		UID:        clusterItem.GetUID(),
+	}
+
+	annotations = op.SetConnectionAnnotation(annotations, connection)
+
+	autoRestart := clusterItem.Annotations[op.AutoRestartWorkloadAnnotation]
+	err = kubeSecrets.CreateKubernetesSecretFromItem(ctx, r.Client, clusterItem.SecretName(), namespace, item,
+		autoRestart, secretLabels, annotations, secretType, ownerRef, r.Config.AllowEmptyValues, spec, linkedItems)
+	if err != nil {
+		return err
+	}
+
+	return kubeSecrets.SyncServiceAccountImagePullSecret(ctx, r.Client, clusterItem.SecretName(), namespace,
+		clusterItem.Spec.ImagePullSecret)
+}
+
+// checkSecretOwner fails when the namespace has a Secret named like the ClusterOnePasswordItem that it
+// does not manage, so a Secret of another tenant is never overwritten, then deleted on cleanup. A Secret
+// is managed when it is owned by the ClusterOnePasswordItem or labeled with its name.
+func (r *ClusterOnePasswordItemReconciler) checkSecretOwner(
+	ctx context.Context,
+	clusterItem *onepasswordv1.ClusterOnePasswordItem,
+	namespace string,
+) error {
+	secret := &corev1.Secret{}
+	err := r.Get(ctx, types.NamespacedName{Name: clusterItem.SecretName(), Namespace: namespace}, secret)
+	if errors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	for _, ref := range secret.OwnerReferences {
+		if ref.UID == clusterItem.UID {
+			return nil
+		}
+	}
+	if secret.Labels[op.ClusterItemLabel] == clusterItem.Name {
+		return nil
+	}
+	return fmt.Errorf("conflict: Secret %s already exists and is not managed by ClusterOnePasswordItem %s",
+		secret.Name, clusterItem.Name)
+}
+
+// cleanupKubernetesSecrets deletes the Secrets managed by the ClusterOnePasswordItem in every
+// namespace that is not part of keep. A nil keep deletes all of them.
+func (r *ClusterOnePasswordItemReconciler) cleanupKubernetesSecrets(
+	ctx context.Context,
+	clusterItem *onepasswordv1.ClusterOnePasswordItem,
+	keep map[string]bool,
+) error {
+	secrets := &corev1.SecretList{}
+	if err := r.List(ctx, secrets, client.MatchingLabels{op.ClusterItemLabel: clusterItem.Name}); err != nil {
+		return fmt.Errorf("failed to list secrets: %w", err)
+	}
+
+	for i := range secrets.Items {
+		secret := &secrets.Items[i]
+		if keep[secret.Namespace] {
+			continue
+		}
+		err := kubeSecrets.SyncServiceAccountImagePullSecret(ctx, r.Client, secret.Name, secret.Namespace, nil)
+		if err != nil {
+			return err
+		}
+		logClusterOnePasswordItem.Info(fmt.Sprintf("Deleting Secret %v at namespace '%v'", secret.Name, secret.Namespace))
+		if err := r.Delete(ctx, secret); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *ClusterOnePasswordItemReconciler) updateStatus(
+	ctx context.Context,
+	clusterItem *onepasswordv1.ClusterOnePasswordItem,
+	namespaceStatuses []onepasswordv1.ClusterOnePasswordItemNamespaceStatus,
+	err error,
+) error {
+	existingCondition := findCondition(clusterItem.Status.Conditions, onepasswordv1.OnePasswordItemReady)
+	updatedCondition := existingCondition
+	if err != nil {
+		updatedCondition.Message = err.Error()
+		updatedCondition.Status = metav1.ConditionFalse
+	} else {
+		updatedCondition.Message = ""
+		updatedCondition.Status = metav1.ConditionTrue
+	}
+
+	if existingCondition.Status != updatedCondition.Status {
+		updatedCondition.LastTransitionTime = metav1.Now()
+	}
+
+	// Keep the transition time of namespaces whose status did not change.
+	existingStatuses := make(map[string]onepasswordv1.ClusterOnePasswordItemNamespaceStatus)
+	for _, status := range clusterItem.Status.Namespaces {
+		existingStatuses[status.Namespace] = status
+	}
+	for i, status := range namespaceStatuses {
+		existing, ok := existingStatuses[status.Namespace]
+		if ok && existing.Status == status.Status {
+			namespaceStatuses[i].LastTransitionTime = existing.LastTransitionTime
+		} else {
+			namespaceStatuses[i].LastTransitionTime = metav1.Now()
+		}
+	}
+
+	// A failure before the namespaces were synced leaves the previous per-namespace status in place.
+	if namespaceStatuses == nil && err != nil {
+		namespaceStatuses = clusterItem.Status.Namespaces
+	}
+
+	clusterItem.Status.Conditions = append([]onepasswordv1.OnePasswordItemCondition{updatedCondition},
+		backendConditions(clusterItem.Status.Conditions, err)...)
+	clusterItem.Status.Namespaces = namespaceStatuses
+	return r.Status().Update(ctx, clusterItem)
+}
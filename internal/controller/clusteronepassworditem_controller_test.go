@@ -0,0 +1,178 @@
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	onepasswordv1 "github.com/1Password/onepassword-operator/api/v1"
+	op "github.com/1Password/onepassword-operator/pkg/onepassword"
+)
+
+var _ = Describe("ClusterOnePasswordItem controller", func() {
+	const teamLabel = "team"
+
+	createNamespace := func(ctx context.Context, name string, labels map[string]string) {
+		ns := &v1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   name,
+				Labels: labels,
+			},
+		}
+		Expect(k8sClient.Create(ctx, ns)).Should(Succeed())
+	}
+
+	BeforeEach(func() {
+		err := k8sClient.DeleteAllOf(context.Background(), &onepasswordv1.ClusterOnePasswordItem{})
+		Expect(err).ToNot(HaveOccurred())
+
+		item := item1.ToModel()
+		mockGetItemByIDFunc.Return(item, nil)
+	})
+
+	It("Should create the secret in every selected namespace and follow relabeling", func() {
+		ctx := context.Background()
+		createNamespace(ctx, "cluster-item-a", map[string]string{teamLabel: "payments"})
+		createNamespace(ctx, "cluster-item-b", map[string]string{teamLabel: "payments"})
+		createNamespace(ctx, "cluster-item-c", map[string]string{teamLabel: "search"})
+
+		key := types.NamespacedName{Name: "shared-credentials"}
+		toCreate := &onepasswordv1.ClusterOnePasswordItem{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: key.Name,
+			},
+			Spec: onepasswordv1.ClusterOnePasswordItemSpec{
+				NamespaceSelector: metav1.LabelSelector{
+					MatchLabels: map[string]string{teamLabel: "payments"},
+				},
+				OnePasswordItemSpec: onepasswordv1.OnePasswordItemSpec{
+					ItemPath: item1.Path,
+				},
+			},
+		}
+
+		By("Creating a new ClusterOnePasswordItem successfully")
+		Expect(k8sClient.Create(ctx, toCreate)).Should(Succeed())
+
+		By("Creating the K8s secret in each matching namespace")
+		for _, ns := range []string{"cluster-item-a", "cluster-item-b"} {
+			secret := &v1.Secret{}
+			Eventually(func() error {
+				return k8sClient.Get(ctx, types.NamespacedName{Name: key.Name, Namespace: ns}, secret)
+			}, timeout, interval).Should(Succeed())
+			Expect(secret.Data).Should(Equal(item1.SecretData))
+			Expect(secret.Labels).Should(HaveKeyWithValue(op.ClusterItemLabel, key.Name))
+		}
+
+		By("Not creating the K8s secret in a namespace that does not match")
+		Consistently(func() error {
+			return k8sClient.Get(ctx, types.NamespacedName{Name: key.Name, Namespace: "cluster-item-c"}, &v1.Secret{})
+		}, duration/5, interval).ShouldNot(Succeed())
+
+		By("Reporting per-namespace status")
+		Eventually(func() []onepasswordv1.ClusterOnePasswordItemNamespaceStatus {
+			created := &onepasswordv1.ClusterOnePasswordItem{}
+			if err := k8sClient.Get(ctx, key, created); err != nil {
+				return nil
+			}
+			return created.Status.Namespaces
+		}, timeout, interval).Should(HaveLen(2))
+
+		By("Creating the K8s secret once a namespace is relabeled to match")
+		nsC := &v1.Namespace{}
+		Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "cluster-item-c"}, nsC)).Should(Succeed())
+		nsC.Labels[teamLabel] = "payments"
+		Expect(k8sClient.Update(ctx, nsC)).Should(Succeed())
+		Eventually(func() error {
+			return k8sClient.Get(ctx, types.NamespacedName{Name: key.Name, Namespace: "cluster-item-c"}, &v1.Secret{})
+		}, timeout, interval).Should(Succeed())
+
+		By("Removing the K8s secret once a namespace is relabeled to not match")
+		nsA := &v1.Namespace{}
+		Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "cluster-item-a"}, nsA)).Should(Succeed())
+		nsA.Labels[teamLabel] = "search"
+		Expect(k8sClient.Update(ctx, nsA)).Should(Succeed())
+		Eventually(func() error {
+			return k8sClient.Get(ctx, types.NamespacedName{Name: key.Name, Namespace: "cluster-item-a"}, &v1.Secret{})
+		}, timeout, interval).ShouldNot(Succeed())
+
+		By("Deleting the ClusterOnePasswordItem successfully")
+		Eventually(func() error {
+			f := &onepasswordv1.ClusterOnePasswordItem{}
+			if err := k8sClient.Get(ctx, key, f); err != nil {
+				return err
+			}
+			return k8sClient.Delete(ctx, f)
+		}, timeout, interval).Should(Succeed())
+
+		Eventually(func() int {
+			secrets := &v1.SecretList{}
+			if err := k8sClient.List(ctx, secrets, client.MatchingLabels{op.ClusterItemLabel: key.Name}); err != nil {
+				return -1
+			}
+			return len(secrets.Items)
+		}, timeout, interval).Should(Equal(0))
+	})
+
+	It("Should not overwrite or delete a Secret it does not manage", func() {
+		ctx := context.Background()
+		createNamespace(ctx, "cluster-item-tenant", map[string]string{teamLabel: "billing"})
+		foreignData := map[string][]byte{"token": []byte("tenant-token")}
+		foreign := &v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "billing-credentials",
+				Namespace: "cluster-item-tenant",
+			},
+			Data: foreignData,
+		}
+		Expect(k8sClient.Create(ctx, foreign)).Should(Succeed())
+
+		key := types.NamespacedName{Name: "billing-credentials"}
+		toCreate := &onepasswordv1.ClusterOnePasswordItem{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: key.Name,
+			},
+			Spec: onepasswordv1.ClusterOnePasswordItemSpec{
+				NamespaceSelector: metav1.LabelSelector{
+					MatchLabels: map[string]string{teamLabel: "billing"},
+				},
+				OnePasswordItemSpec: onepasswordv1.OnePasswordItemSpec{
+					ItemPath: item1.Path,
+				},
+			},
+		}
+
+		By("Creating a new ClusterOnePasswordItem successfully")
+		Expect(k8sClient.Create(ctx, toCreate)).Should(Succeed())
+
+		By("Reporting a conflict in the namespace status")
+		Eventually(func() string {
+			created := &onepasswordv1.ClusterOnePasswordItem{}
+			if err := k8sClient.Get(ctx, key, created); err != nil || len(created.Status.Namespaces) == 0 {
+				return ""
+			}
+			return created.Status.Namespaces[0].Message
+		}, timeout, interval).Should(ContainSubstring("conflict"))
+
+		secretKey := types.NamespacedName{Name: foreign.Name, Namespace: foreign.Namespace}
+		secret := &v1.Secret{}
+		Expect(k8sClient.Get(ctx, secretKey, secret)).Should(Succeed())
+		Expect(secret.Data).Should(Equal(foreignData))
+		Expect(secret.Labels).ShouldNot(HaveKey(op.ClusterItemLabel))
+
+		By("Keeping the Secret once the ClusterOnePasswordItem is deleted")
+		Expect(k8sClient.Delete(ctx, toCreate)).Should(Succeed())
+		Eventually(func() error {
+			return k8sClient.Get(ctx, key, &onepasswordv1.ClusterOnePasswordItem{})
+		}, timeout, interval).ShouldNot(Succeed())
+		Consistently(func() error {
+			return k8sClient.Get(ctx, secretKey, &v1.Secret{})
+		}, duration/5, interval).Should(Succeed())
+	})
+})
@@ -57,6 +57,7 @@ type DeploymentReconciler struct {
 	client.Client
 	Scheme             *runtime.Scheme
 	OpClient           opclient.Client
+	Connections        *op.ConnectionClients
 	OpAnnotationRegExp *regexp.Regexp
 	Recorder           record.EventRecorder
 	Config             ReconcilerConfig
@@ -111,6 +112,11 @@ func (r *DeploymentReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 				r.Recorder.Event(deployment, corev1.EventTypeWarning, "RateLimited", "1Password rate limit hit. Requeuing after 15 minutes.")
 				return ctrl.Result{RequeueAfter: 15 * time.Minute}, nil
 			}
+			if opclient.IsBackendUnavailable(err) {
+				reqLogger.V(logs.InfoLevel).Info("1Password is unavailable. Requeuing.", "requeueAfter", backendUnavailableRequeueDelay.String())
+				r.Recorder.Event(deployment, corev1.EventTypeWarning, "BackendUnavailable", fmt.Sprintf("1Password is unavailable: %s", err.Error()))
+				return ctrl.Result{RequeueAfter: backendUnavailableRequeueDelay}, nil
+			}
 			r.Recorder.Event(deployment, corev1.EventTypeWarning, "ReconcileError", fmt.Sprintf("Failed to sync secret from 1Password: %s", err.Error()))
 			return ctrl.Result{}, err
 		}
@@ -206,7 +212,17 @@ func (r *DeploymentReconciler) handleApplyingDeployment(ctx context.Context, dep
 		return nil
 	}
 
-	item, err := op.GetOnePasswordItemByPath(ctx, r.OpClient, annotations[op.ItemPathAnnotation])
+	connection, err := op.ConnectionReferenceFor(nil, annotations)
+	if err != nil {
+		return err
+	}
+	opClient, err := op.ClientForConnection(ctx, r.Connections, r.OpClient, namespace, connection)
+	if err != nil {
+		return err
+	}
+	annotations = op.SetConnectionAnnotation(annotations, connection)
+
+	item, err := op.GetOnePasswordItemByPath(ctx, opClient, annotations[op.ItemPathAnnotation])
 	if err != nil {
 		return fmt.Errorf("failed to retrieve item: %w", err)
 	}
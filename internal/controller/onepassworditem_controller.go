@@ -27,6 +27,7 @@ package controller
 import (
 	"context"
 	"fmt"
+	"reflect"
 	"strings"
 	"time"
 
@@ -42,28 +43,42 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
 var logOnePasswordItem = logf.Log.WithName("controller_onepassworditem")
 var finalizer = "onepassword.com/finalizer.secret"
 
+// backendUnavailableRequeueDelay is how long a resource waits before it is synced again while 1Password
+// is unreachable.
+const backendUnavailableRequeueDelay = 30 * time.Second
+
 // OnePasswordItemReconciler reconciles a OnePasswordItem object
 type OnePasswordItemReconciler struct {
 	client.Client
 	Scheme   *runtime.Scheme
 	OpClient opclient.Client
-	Config   ReconcilerConfig
+	// Connections builds the clients of items selecting a OnePasswordConnection or ClusterOnePasswordConnection.
+	Connections *op.ConnectionClients
+	Config      ReconcilerConfig
 }
 
 // +kubebuilder:rbac:groups=onepassword.com,resources=onepassworditems,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=onepassword.com,resources=onepassworditems/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=onepassword.com,resources=onepassworditems/finalizers,verbs=update
+// +kubebuilder:rbac:groups=onepassword.com,resources=onepasswordtemplates,verbs=get;list;watch
+// +kubebuilder:rbac:groups=onepassword.com,resources=onepasswordconnections;clusteronepasswordconnections,verbs=get;list;watch
 
 // +kubebuilder:rbac:groups="",resources=pods,verbs=get
 // +kubebuilder:rbac:groups="",resources=pods;services;services/finalizers;endpoints;persistentvolumeclaims;events;configmaps;secrets;namespaces,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=serviceaccounts,verbs=get;list;watch;update;patch
 // +kubebuilder:rbac:groups=apps,resources=daemonsets;deployments;replicasets;statefulsets,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=apps,resources=replicasets;deployments,verbs=get
 // +kubebuilder:rbac:groups=apps,resourceNames=onepassword-connect-operator,resources=deployments/finalizers,verbs=update
@@ -115,11 +130,21 @@ func (r *OnePasswordItemReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 		if updateStatusErr := r.updateStatus(ctx, onepassworditem, err); updateStatusErr != nil {
 			return ctrl.Result{}, fmt.Errorf("cannot update status: %s", updateStatusErr)
 		}
+		if opclient.IsBackendUnavailable(err) {
+			reqLogger.V(logs.InfoLevel).Info("1Password is unavailable. Requeuing.", "requeueAfter", backendUnavailableRequeueDelay.String())
+			return ctrl.Result{RequeueAfter: backendUnavailableRequeueDelay}, nil
+		}
 		return ctrl.Result{}, err
 	}
 	// If one password finalizer exists then we must cleanup associated secrets
 	if utils.ContainsString(onepassworditem.Finalizers, finalizer) {
 
+		// Remove the secret from the ServiceAccounts it was attached to
+		err = kubeSecrets.SyncServiceAccountImagePullSecret(ctx, r.Client, onepassworditem.Name, onepassworditem.Namespace, nil)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+
 		// Delete associated kubernetes secret
 		if err = r.cleanupKubernetesSecret(ctx, onepassworditem); err != nil {
 			return ctrl.Result{}, err
@@ -135,12 +160,90 @@ func (r *OnePasswordItemReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *OnePasswordItemReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	err := mgr.GetFieldIndexer().IndexField(context.Background(), &onepasswordv1.OnePasswordItem{},
+		templateRefIndex, func(obj client.Object) []string {
+			return templateRefIndexValues(&obj.(*onepasswordv1.OnePasswordItem).Spec)
+		})
+	if err != nil {
+		return err
+	}
+
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&onepasswordv1.OnePasswordItem{}).
+		Watches(
+			&corev1.ServiceAccount{},
+			handler.EnqueueRequestsFromMapFunc(r.requestsForServiceAccount),
+			builder.WithPredicates(serviceAccountCreatedOrRelabeledPredicate()),
+		).
+		Watches(
+			&onepasswordv1.OnePasswordTemplate{},
+			handler.EnqueueRequestsFromMapFunc(r.requestsForTemplate),
+		).
+		Watches(
+			&onepasswordv1.ClusterOnePasswordTemplate{},
+			handler.EnqueueRequestsFromMapFunc(r.requestsForTemplate),
+		).
 		Named("onepassworditem").
 		Complete(r)
 }
 
+// requestsForServiceAccount enqueues the OnePasswordItems in the ServiceAccount's namespace that
+// attach their image pull secret to ServiceAccounts, so new or relabeled ServiceAccounts are picked up.
+func (r *OnePasswordItemReconciler) requestsForServiceAccount(ctx context.Context, sa client.Object) []reconcile.Request {
+	onePasswordItems := &onepasswordv1.OnePasswordItemList{}
+	if err := r.List(ctx, onePasswordItems, client.InNamespace(sa.GetNamespace())); err != nil {
+		logOnePasswordItem.Error(err, "Failed to list OnePasswordItems")
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, item := range onePasswordItems.Items {
+		if item.Spec.ImagePullSecret.AttachesToServiceAccounts() {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&item)})
+		}
+	}
+	return requests
+}
+
+// requestsForTemplate enqueues the OnePasswordItems that reference the template, so their Secrets are
+// rendered again when the template changes. A OnePasswordTemplate is only referenced from its namespace,
+// a ClusterOnePasswordTemplate from every namespace.
+func (r *OnePasswordItemReconciler) requestsForTemplate(ctx context.Context, tmpl client.Object) []reconcile.Request {
+	onePasswordItems := &onepasswordv1.OnePasswordItemList{}
+	kind := onepasswordv1.OnePasswordTemplateKind
+	if _, ok := tmpl.(*onepasswordv1.ClusterOnePasswordTemplate); ok {
+		kind = onepasswordv1.ClusterOnePasswordTemplateKind
+	}
+	ref := templateRefIndexValue(kind, tmpl.GetName())
+	err := r.List(ctx, onePasswordItems, client.InNamespace(tmpl.GetNamespace()), client.MatchingFields{templateRefIndex: ref})
+	if err != nil {
+		logOnePasswordItem.Error(err, "Failed to list OnePasswordItems referencing template", "Template", ref)
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0, len(onePasswordItems.Items))
+	for _, item := range onePasswordItems.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&item)})
+	}
+	return requests
+}
+
+// serviceAccountCreatedOrRelabeledPredicate only passes ServiceAccount creations and label changes,
+// which are the events that can change whether a ServiceAccount is selected.
+func serviceAccountCreatedOrRelabeledPredicate() predicate.Predicate {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return !reflect.DeepEqual(e.ObjectOld.GetLabels(), e.ObjectNew.GetLabels())
+		},
+		DeleteFunc: func(event.DeleteEvent) bool {
+			return false
+		},
+		GenericFunc: func(event.GenericEvent) bool {
+			return false
+		},
+	}
+}
+
 func (r *OnePasswordItemReconciler) cleanupKubernetesSecret(ctx context.Context, onePasswordItem *onepasswordv1.OnePasswordItem) error {
 	kubernetesSecret := &corev1.Secret{}
 	kubernetesSecret.Name = onePasswordItem.Name
@@ -171,19 +274,36 @@ func (r *OnePasswordItemReconciler) handleOnePasswordItem(ctx context.Context, r
 		annotations = nil
 	}
 
-	item, err := op.GetOnePasswordItemByPath(ctx, r.OpClient, resource.Spec.ItemPath)
+	connection, err := op.ConnectionReferenceFor(&resource.Spec, resource.Annotations)
+	if err != nil {
+		return err
+	}
+	opClient, err := op.ClientForConnection(ctx, r.Connections, r.OpClient, resource.Namespace, connection)
+	if err != nil {
+		return err
+	}
+	annotations = op.SetConnectionAnnotation(annotations, connection)
+
+	item, err := op.GetOnePasswordItemByPath(ctx, opClient, resource.Spec.ItemPath)
 	if err != nil {
 		return fmt.Errorf("failed to retrieve item: %w", err)
 	}
 
-	// Extract template and imagePullSecret config from spec.
-	secretTemplate := resource.Spec.Template
-	imagePullSecret := resource.Spec.ImagePullSecret
+	linkedItems, err := op.GetReferencedItems(ctx, opClient, &resource.Spec)
+	if err != nil {
+		return err
+	}
 
-	// If imagePullSecret is configured, automatically set secret type to dockerconfigjson.
-	if imagePullSecret != nil && secretType == "" {
-		secretType = string(corev1.SecretTypeDockerConfigJson)
+	spec, err := kubeSecrets.ResolveSecretTemplate(ctx, r.Client, resource.Namespace, &resource.Spec)
+	if err != nil {
+		return err
 	}
+	resource.Status.KeyCollisions = kubeSecrets.KeyCollisions(*item, r.Config.AllowEmptyValues, spec)
+
+	imagePullSecret := resource.Spec.ImagePullSecret
+
+	// Automatically set the secret type required by the configured output, e.g. dockerconfigjson.
+	secretType = kubeSecrets.SecretTypeForSpec(secretType, &resource.Spec)
 
 	// Create owner reference.
 	gvk, err := apiutil.GVKForObject(resource, r.Scheme)
@@ -197,7 +317,13 @@ func (r *OnePasswordItemReconciler) handleOnePasswordItem(ctx context.Context, r
 		UID:        resource.GetUID(),
 	}
 
-	return kubeSecrets.CreateKubernetesSecretFromItem(ctx, r.Client, secretName, resource.Namespace, item, autoRestart, labels, annotations, secretType, ownerRef, r.Config.AllowEmptyValues, secretTemplate, imagePullSecret)
+	err = kubeSecrets.CreateKubernetesSecretFromItem(ctx, r.Client, secretName, resource.Namespace, item, autoRestart, labels, annotations, secretType, ownerRef, r.Config.AllowEmptyValues, spec, linkedItems)
+	if err != nil {
+		return err
+	}
+
+	// Always sync so ServiceAccounts that are no longer selected are detached.
+	return kubeSecrets.SyncServiceAccountImagePullSecret(ctx, r.Client, secretName, resource.Namespace, imagePullSecret)
 }
 
 func (r *OnePasswordItemReconciler) updateStatus(ctx context.Context, resource *onepasswordv1.OnePasswordItem, err error) error {
@@ -215,10 +341,32 @@ func (r *OnePasswordItemReconciler) updateStatus(ctx context.Context, resource *
 		updatedCondition.LastTransitionTime = metav1.Now()
 	}
 
-	resource.Status.Conditions = []onepasswordv1.OnePasswordItemCondition{updatedCondition}
+	resource.Status.Conditions = append([]onepasswordv1.OnePasswordItemCondition{updatedCondition},
+		backendConditions(resource.Status.Conditions, err)...)
 	return r.Status().Update(ctx, resource)
 }
 
+// backendConditions returns the BackendUnavailable condition to set next to the Ready condition. It is only
+// added once 1Password was unreachable, and is then kept as False so the recovery is visible.
+func backendConditions(conditions []onepasswordv1.OnePasswordItemCondition, err error) []onepasswordv1.OnePasswordItemCondition {
+	existingCondition := findCondition(conditions, onepasswordv1.OnePasswordItemBackendUnavailable)
+	updatedCondition := existingCondition
+	if opclient.IsBackendUnavailable(err) {
+		updatedCondition.Message = err.Error()
+		updatedCondition.Status = metav1.ConditionTrue
+	} else if existingCondition.Status == metav1.ConditionUnknown {
+		return nil
+	} else {
+		updatedCondition.Message = ""
+		updatedCondition.Status = metav1.ConditionFalse
+	}
+
+	if existingCondition.Status != updatedCondition.Status {
+		updatedCondition.LastTransitionTime = metav1.Now()
+	}
+	return []onepasswordv1.OnePasswordItemCondition{updatedCondition}
+}
+
 func findCondition(conditions []onepasswordv1.OnePasswordItemCondition, t onepasswordv1.OnePasswordItemConditionType) onepasswordv1.OnePasswordItemCondition {
 	for _, c := range conditions {
 		if c.Type == t {
@@ -3,18 +3,30 @@ package controller
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"path/filepath"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
 	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
+	"github.com/1Password/connect-sdk-go/onepassword"
+
 	onepasswordv1 "github.com/1Password/onepassword-operator/api/v1"
+	kubeSecrets "github.com/1Password/onepassword-operator/pkg/kubernetessecrets"
+	opclient "github.com/1Password/onepassword-operator/pkg/onepassword/client"
+	"github.com/1Password/onepassword-operator/pkg/onepassword/client/connect"
+	"github.com/1Password/onepassword-operator/pkg/onepassword/client/testing/cassette"
+	"github.com/1Password/onepassword-operator/pkg/onepassword/client/testing/chaos"
 	"github.com/1Password/onepassword-operator/pkg/onepassword/model"
+	"github.com/1Password/onepassword-operator/pkg/testhelper/fakeconnect"
 )
 
 const (
@@ -542,6 +554,379 @@ var _ = Describe("OnePasswordItem controller", func() {
 
 			Expect(createdSecret.Type).To(Equal(v1.SecretTypeDockerConfigJson))
 		})
+
+		It("Should attach the secret to the configured ServiceAccounts and detach it on deletion", func() {
+			ctx := context.Background()
+			item := item1.ToModel()
+			item.Fields = []model.ItemField{
+				{ID: "field-1", Label: "registry", Value: "ghcr.io"},
+				{ID: "field-2", Label: "username", Value: "testuser"},
+				{ID: "field-3", Label: "password", Value: "testpass"},
+			}
+			mockGetItemByIDFunc.Return(item, nil)
+
+			serviceAccount := &v1.ServiceAccount{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "image-puller",
+					Namespace: namespace,
+				},
+			}
+			Expect(k8sClient.Create(ctx, serviceAccount)).Should(Succeed())
+			saKey := types.NamespacedName{Name: serviceAccount.Name, Namespace: namespace}
+
+			key := types.NamespacedName{
+				Name:      "attached-pull-secret",
+				Namespace: namespace,
+			}
+			toCreate := &onepasswordv1.OnePasswordItem{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      key.Name,
+					Namespace: key.Namespace,
+				},
+				Spec: onepasswordv1.OnePasswordItemSpec{
+					ItemPath: item1.Path,
+					ImagePullSecret: &onepasswordv1.ImagePullSecretConfig{
+						RegistryField:   "registry",
+						UsernameField:   "username",
+						PasswordField:   "password",
+						ServiceAccounts: []string{serviceAccount.Name},
+					},
+				},
+			}
+
+			By("Creating a new OnePasswordItem with serviceAccounts")
+			Expect(k8sClient.Create(ctx, toCreate)).Should(Succeed())
+
+			By("Verifying the ServiceAccount references the secret")
+			Eventually(func() []v1.LocalObjectReference {
+				sa := &v1.ServiceAccount{}
+				if err := k8sClient.Get(ctx, saKey, sa); err != nil {
+					return nil
+				}
+				return sa.ImagePullSecrets
+			}, timeout, interval).Should(ContainElement(v1.LocalObjectReference{Name: key.Name}))
+
+			By("Deleting the OnePasswordItem")
+			Expect(k8sClient.Delete(ctx, toCreate)).Should(Succeed())
+
+			By("Verifying the reference is removed from the ServiceAccount")
+			Eventually(func() []v1.LocalObjectReference {
+				sa := &v1.ServiceAccount{}
+				if err := k8sClient.Get(ctx, saKey, sa); err != nil {
+					return nil
+				}
+				return sa.ImagePullSecrets
+			}, timeout, interval).ShouldNot(ContainElement(v1.LocalObjectReference{Name: key.Name}))
+		})
+	})
+
+	Context("Shared templates", func() {
+		It("Should render the K8s secret again when its ClusterOnePasswordTemplate changes", func() {
+			ctx := context.Background()
+			tmpl := &onepasswordv1.ClusterOnePasswordTemplate{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "shared-dsn",
+				},
+				Spec: onepasswordv1.OnePasswordTemplateSpec{
+					Data: map[string]string{"dsn": "user={{ .Fields.username }}"},
+				},
+			}
+			Expect(k8sClient.Create(ctx, tmpl)).Should(Succeed())
+			DeferCleanup(func() {
+				Expect(k8sClient.Delete(context.Background(), tmpl)).Should(Succeed())
+			})
+
+			key := types.NamespacedName{
+				Name:      "cluster-templated-item",
+				Namespace: namespace,
+			}
+			toCreate := &onepasswordv1.OnePasswordItem{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      key.Name,
+					Namespace: key.Namespace,
+				},
+				Spec: onepasswordv1.OnePasswordItemSpec{
+					ItemPath: item1.Path,
+					Template: &onepasswordv1.SecretTemplate{
+						Ref: &onepasswordv1.TemplateReference{
+							Kind: onepasswordv1.ClusterOnePasswordTemplateKind,
+							Name: tmpl.Name,
+						},
+					},
+				},
+			}
+
+			By("Creating a new OnePasswordItem successfully")
+			Expect(k8sClient.Create(ctx, toCreate)).Should(Succeed())
+
+			By("Rendering the K8s secret from the ClusterOnePasswordTemplate")
+			Eventually(func() string {
+				secret := &v1.Secret{}
+				if err := k8sClient.Get(ctx, key, secret); err != nil {
+					return ""
+				}
+				return string(secret.Data["dsn"])
+			}, timeout, interval).Should(Equal("user=" + username))
+
+			By("Rendering the K8s secret again once the ClusterOnePasswordTemplate changes")
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: tmpl.Name}, tmpl)).Should(Succeed())
+			tmpl.Spec.Data["dsn"] = "username={{ .Fields.username }}"
+			Expect(k8sClient.Update(ctx, tmpl)).Should(Succeed())
+			Eventually(func() string {
+				secret := &v1.Secret{}
+				if err := k8sClient.Get(ctx, key, secret); err != nil {
+					return ""
+				}
+				return string(secret.Data["dsn"])
+			}, timeout, interval).Should(Equal("username=" + username))
+		})
+	})
+
+	Context("1Password failures", func() {
+		AfterEach(func() {
+			chaosOpClient.Clear()
+		})
+
+		It("Should create the K8s secret once 1Password recovers", func() {
+			ctx := context.Background()
+			chaosOpClient.Inject(chaos.Fault{Operation: "GetItemByID", Err: chaos.ServerError(), Times: 2})
+
+			key := types.NamespacedName{
+				Name:      "recovered-item",
+				Namespace: namespace,
+			}
+			toCreate := &onepasswordv1.OnePasswordItem{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      key.Name,
+					Namespace: key.Namespace,
+				},
+				Spec: onepasswordv1.OnePasswordItemSpec{
+					ItemPath: item1.Path,
+				},
+			}
+
+			By("Creating a new OnePasswordItem successfully")
+			Expect(k8sClient.Create(ctx, toCreate)).Should(Succeed())
+
+			By("Creating the K8s secret once the errors stop")
+			createdSecret := &v1.Secret{}
+			Eventually(func() error {
+				return k8sClient.Get(ctx, key, createdSecret)
+			}, timeout, interval).Should(Succeed())
+			Expect(createdSecret.Data).Should(Equal(item1.SecretData))
+		})
+
+		It("Should requeue the OnePasswordItem when 1Password is rate limited", func() {
+			ctx := context.Background()
+			chaosOpClient.Inject(chaos.Fault{Operation: "GetItemByID", Err: chaos.RateLimitError()})
+
+			key := types.NamespacedName{
+				Name:      "rate-limited-item",
+				Namespace: namespace,
+			}
+			toCreate := &onepasswordv1.OnePasswordItem{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      key.Name,
+					Namespace: key.Namespace,
+				},
+				Spec: onepasswordv1.OnePasswordItemSpec{
+					ItemPath: item1.Path,
+				},
+			}
+
+			By("Creating a new OnePasswordItem successfully")
+			Expect(k8sClient.Create(ctx, toCreate)).Should(Succeed())
+
+			By("Requeuing the reconciliation instead of failing it")
+			Eventually(func() (time.Duration, error) {
+				result, err := onePasswordItemReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+				return result.RequeueAfter, err
+			}, timeout, interval).Should(Equal(15 * time.Minute))
+
+			Consistently(func() error {
+				return k8sClient.Get(ctx, key, &v1.Secret{})
+			}, time.Second, interval).ShouldNot(Succeed())
+		})
+
+		It("Should report BackendUnavailable while 1Password is unreachable", func() {
+			ctx := context.Background()
+			chaosOpClient.Inject(chaos.Fault{
+				Operation: "GetItemByID",
+				Err:       fmt.Errorf("%w: connection refused", opclient.ErrBackendUnavailable),
+			})
+
+			key := types.NamespacedName{
+				Name:      "unavailable-item",
+				Namespace: namespace,
+			}
+			toCreate := &onepasswordv1.OnePasswordItem{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      key.Name,
+					Namespace: key.Namespace,
+				},
+				Spec: onepasswordv1.OnePasswordItemSpec{
+					ItemPath: item1.Path,
+				},
+			}
+
+			By("Creating a new OnePasswordItem successfully")
+			Expect(k8sClient.Create(ctx, toCreate)).Should(Succeed())
+
+			By("Requeuing the reconciliation with a BackendUnavailable condition")
+			Eventually(func() (time.Duration, error) {
+				result, err := onePasswordItemReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+				return result.RequeueAfter, err
+			}, timeout, interval).Should(Equal(backendUnavailableRequeueDelay))
+
+			item := &onepasswordv1.OnePasswordItem{}
+			Expect(k8sClient.Get(ctx, key, item)).Should(Succeed())
+			condition := findCondition(item.Status.Conditions, onepasswordv1.OnePasswordItemBackendUnavailable)
+			Expect(condition.Status).Should(Equal(metav1.ConditionTrue))
+			Expect(condition.Message).Should(ContainSubstring("connection refused"))
+
+			By("Creating the K8s secret once 1Password is reachable again")
+			chaosOpClient.Clear()
+			Eventually(func() (metav1.ConditionStatus, error) {
+				if _, err := onePasswordItemReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key}); err != nil {
+					return "", err
+				}
+				if err := k8sClient.Get(ctx, key, item); err != nil {
+					return "", err
+				}
+				return findCondition(item.Status.Conditions, onepasswordv1.OnePasswordItemBackendUnavailable).Status, nil
+			}, timeout, interval).Should(Equal(metav1.ConditionFalse))
+			Expect(k8sClient.Get(ctx, key, &v1.Secret{})).Should(Succeed())
+		})
+	})
+
+	Context("Recorded 1Password sessions", func() {
+		It("Should create the K8s secret from a replayed cassette", func() {
+			ctx := context.Background()
+			replayer, err := cassette.NewReplayerFromFile(filepath.Join("testdata", "cassettes", "onepassworditem.json"))
+			Expect(err).ToNot(HaveOccurred())
+
+			// The mocked client of the manager knows no vault titled Production, so only the reconciler
+			// replaying the cassette can create the secret.
+			replayReconciler := &OnePasswordItemReconciler{
+				Client:   k8sClient,
+				Scheme:   k8sClient.Scheme(),
+				OpClient: replayer,
+			}
+
+			key := types.NamespacedName{
+				Name:      "replayed-item",
+				Namespace: namespace,
+			}
+			toCreate := &onepasswordv1.OnePasswordItem{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      key.Name,
+					Namespace: key.Namespace,
+				},
+				Spec: onepasswordv1.OnePasswordItemSpec{
+					ItemPath: "vaults/Production/items/database",
+				},
+			}
+
+			By("Creating a new OnePasswordItem successfully")
+			Expect(k8sClient.Create(ctx, toCreate)).Should(Succeed())
+
+			By("Reconciling with the recorded interactions")
+			Eventually(func() error {
+				_, err := replayReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+				return err
+			}, timeout, interval).Should(Succeed())
+
+			createdSecret := &v1.Secret{}
+			Expect(k8sClient.Get(ctx, key, createdSecret)).Should(Succeed())
+			Expect(createdSecret.Data).Should(HaveKeyWithValue("username", []byte(cassette.Redacted)))
+			Expect(createdSecret.Data).Should(HaveKeyWithValue("password", []byte(cassette.Redacted)))
+			Expect(createdSecret.Annotations).Should(HaveKeyWithValue(kubeSecrets.VersionAnnotation, "7"))
+		})
+	})
+
+	// The other contexts reconcile with the mocked client of the manager, whose faults are injected with
+	// chaos. This one runs the Connect client against the fake Connect server, with its own reconciler.
+	Context("Fake Connect server", func() {
+		It("Should sync the K8s secret with the items served by Connect", func() {
+			ctx := context.Background()
+			server := fakeconnect.NewServer("connect-token")
+			DeferCleanup(server.Close)
+			vault := server.AddVault("Staging")
+			stored, err := server.AddItem(vault.ID, onepassword.Item{
+				Title:    "api",
+				Category: onepassword.Login,
+				Fields: []*onepassword.ItemField{
+					{Label: "username", Value: "service"},
+					{Label: "password", Value: "initial"},
+				},
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			// The requests go through the Connect client over HTTP, like against a real Connect server.
+			connectClient, err := connect.NewClient(connect.Config{
+				ConnectHost:  server.URL,
+				ConnectToken: server.Token,
+			})
+			Expect(err).ToNot(HaveOccurred())
+			connectReconciler := &OnePasswordItemReconciler{
+				Client:   k8sClient,
+				Scheme:   k8sClient.Scheme(),
+				OpClient: connectClient,
+			}
+
+			key := types.NamespacedName{
+				Name:      "connect-item",
+				Namespace: namespace,
+			}
+			toCreate := &onepasswordv1.OnePasswordItem{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      key.Name,
+					Namespace: key.Namespace,
+					// The reconcilers of the manager would otherwise sync the item with the mocked client too.
+					Labels: map[string]string{unmanagedLabel: "true"},
+				},
+				Spec: onepasswordv1.OnePasswordItemSpec{
+					ItemPath: "vaults/Staging/items/api",
+				},
+			}
+
+			By("Creating a new OnePasswordItem successfully")
+			Expect(k8sClient.Create(ctx, toCreate)).Should(Succeed())
+			DeferCleanup(func() {
+				// The manager doesn't remove the finalizer of the item, so its deletion is reconciled here.
+				Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, toCreate))).Should(Succeed())
+				Eventually(func() bool {
+					_, err := connectReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+					Expect(err).ToNot(HaveOccurred())
+					return apierrors.IsNotFound(k8sClient.Get(ctx, key, &onepasswordv1.OnePasswordItem{}))
+				}, timeout, interval).Should(BeTrue())
+			})
+
+			By("Reconciling with the items of the fake Connect server")
+			reconcileSecret := func() (map[string][]byte, error) {
+				if _, err := connectReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key}); err != nil {
+					return nil, err
+				}
+				secret := &v1.Secret{}
+				if err := k8sClient.Get(ctx, key, secret); err != nil {
+					return nil, err
+				}
+				return secret.Data, nil
+			}
+			Eventually(reconcileSecret, timeout, interval).Should(Equal(map[string][]byte{
+				"username": []byte("service"),
+				"password": []byte("initial"),
+			}))
+
+			By("Failing the reconciliation while Connect fails")
+			server.FailRequests(1, http.StatusInternalServerError)
+			_, err = connectReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).To(HaveOccurred())
+
+			By("Updating the K8s secret once the item changes")
+			Expect(server.EditField(vault.ID, stored.ID, "password", "rotated")).Should(Succeed())
+			Eventually(reconcileSecret, timeout, interval).Should(HaveKeyWithValue("password", []byte("rotated")))
+		})
 	})
 
 	Context("Unhappy path", func() {
@@ -36,9 +36,11 @@ import (
 	. "github.com/onsi/gomega"
 	"github.com/stretchr/testify/mock"
 
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/rest"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/envtest"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
@@ -46,6 +48,7 @@ import (
 
 	onepasswordcomv1 "github.com/1Password/onepassword-operator/api/v1"
 	"github.com/1Password/onepassword-operator/pkg/mocks"
+	"github.com/1Password/onepassword-operator/pkg/onepassword/client/testing/chaos"
 	"github.com/1Password/onepassword-operator/pkg/onepassword/model"
 	// +kubebuilder:scaffold:imports
 )
@@ -61,6 +64,10 @@ const (
 	password2 = "4zotzqDqXKasLFT2jzTs"
 
 	annotationRegExpString = "^operator\\.1password\\.io\\/[a-zA-Z\\.]+"
+
+	// unmanagedLabel marks the OnePasswordItems a test reconciles with its own reconciler. The manager
+	// doesn't cache them, so its reconcilers don't race with the test.
+	unmanagedLabel = "test.onepassword.com/unmanaged"
 )
 
 // Define utility constants for object names and testing timeouts/durations and intervals.
@@ -79,8 +86,11 @@ var (
 	ctx                       context.Context
 	cancel                    context.CancelFunc
 	onePasswordItemReconciler *OnePasswordItemReconciler
+	clusterItemReconciler     *ClusterOnePasswordItemReconciler
 	deploymentReconciler      *DeploymentReconciler
 	mockGetItemByIDFunc       *mock.Call
+	// chaosOpClient injects faults into the calls of the reconcilers to the mocked 1Password client.
+	chaosOpClient *chaos.Client
 
 	item1 = &TestItem{
 		ItemID:  "nwrhuano7bcwddcviubpp4mhfq",
@@ -176,8 +186,15 @@ var _ = BeforeSuite(func() {
 	Expect(err).NotTo(HaveOccurred())
 	Expect(k8sClient).NotTo(BeNil())
 
+	managedItems, err := labels.Parse("!" + unmanagedLabel)
+	Expect(err).ToNot(HaveOccurred())
 	k8sManager, err := ctrl.NewManager(cfg, ctrl.Options{
 		Scheme: scheme.Scheme,
+		Cache: cache.Options{
+			ByObject: map[client.Object]cache.ByObject{
+				&onepasswordcomv1.OnePasswordItem{}: {Label: managedItems},
+			},
+		},
 	})
 	Expect(err).ToNot(HaveOccurred())
 
@@ -186,20 +203,29 @@ var _ = BeforeSuite(func() {
 
 	// Mock GetVaultsByTitle to return empty slice for any call so UUID fallback works
 	mockOpClient.On("GetVaultsByTitle", mock.Anything).Return([]model.Vault{}, nil)
+	chaosOpClient = chaos.New(mockOpClient)
 
 	onePasswordItemReconciler = &OnePasswordItemReconciler{
 		Client:   k8sManager.GetClient(),
 		Scheme:   k8sManager.GetScheme(),
-		OpClient: mockOpClient,
+		OpClient: chaosOpClient,
 	}
 	err = (onePasswordItemReconciler).SetupWithManager(k8sManager)
 	Expect(err).ToNot(HaveOccurred())
 
+	clusterItemReconciler = &ClusterOnePasswordItemReconciler{
+		Client:   k8sManager.GetClient(),
+		Scheme:   k8sManager.GetScheme(),
+		OpClient: chaosOpClient,
+	}
+	err = (clusterItemReconciler).SetupWithManager(k8sManager)
+	Expect(err).ToNot(HaveOccurred())
+
 	r, _ := regexp.Compile(annotationRegExpString)
 	deploymentReconciler = &DeploymentReconciler{
 		Client:             k8sManager.GetClient(),
 		Scheme:             k8sManager.GetScheme(),
-		OpClient:           mockOpClient,
+		OpClient:           chaosOpClient,
 		OpAnnotationRegExp: r,
 		Recorder:           k8sManager.GetEventRecorderFor("onepassword-operator-deployment"),
 	}
@@ -0,0 +1,47 @@
+/*
+MIT License
+
+Copyright (c) 2020-2024 1Password
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package controller
+
+import (
+	onepasswordv1 "github.com/1Password/onepassword-operator/api/v1"
+)
+
+// templateRefIndex indexes OnePasswordItems and ClusterOnePasswordItems by the template they
+// reference, so a template change only enqueues its consumers.
+const templateRefIndex = "spec.template.ref"
+
+// templateRefIndexValues returns the index values of the template referenced by the spec.
+func templateRefIndexValues(spec *onepasswordv1.OnePasswordItemSpec) []string {
+	if spec.Template == nil || spec.Template.Ref == nil {
+		return nil
+	}
+	return []string{spec.Template.Ref.String()}
+}
+
+// templateRefIndexValue returns the index value of the template with the given kind and name.
+func templateRefIndexValue(kind, name string) string {
+	ref := onepasswordv1.TemplateReference{Kind: kind, Name: name}
+	return ref.String()
+}
@@ -0,0 +1,204 @@
+package kubernetessecrets
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	onepasswordv1 "github.com/1Password/onepassword-operator/api/v1"
+	"github.com/1Password/onepassword-operator/pkg/onepassword/model"
+	"github.com/1Password/onepassword-operator/pkg/template"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// SSHAuthPublicKey is the key of the public key in a kubernetes.io/ssh-auth Secret.
+	SSHAuthPublicKey = "ssh-publickey"
+	// SSHAuthFingerprint is the key of the key fingerprint in a kubernetes.io/ssh-auth Secret.
+	SSHAuthFingerprint = "ssh-fingerprint"
+	// HtpasswdKey is the key ingress-nginx reads the htpasswd file of basic auth from.
+	HtpasswdKey = "auth"
+)
+
+// buildSSHAuthSecretData converts the SSH key of the item to OpenSSH format for a kubernetes.io/ssh-auth Secret.
+func buildSSHAuthSecretData(item model.Item, config *onepasswordv1.SSHAuthConfig) (map[string][]byte, error) {
+	field, ok := findField(item, config.PrivateKeyField, func(f model.ItemField) bool {
+		return f.Type() == model.FieldTypeSSHKey
+	})
+	if !ok {
+		if config.PrivateKeyField != "" {
+			return nil, fmt.Errorf("field %q not found", config.PrivateKeyField)
+		}
+		return nil, fmt.Errorf("no ssh key field found in item")
+	}
+
+	key, err := template.BuildSSHKey([]byte(field.Value))
+	if err != nil {
+		return nil, err
+	}
+
+	secretData := map[string][]byte{
+		corev1.SSHAuthPrivateKey: key.PrivateKey,
+	}
+	if config.IncludePublicKey {
+		secretData[SSHAuthPublicKey] = key.PublicKey
+	}
+	if config.IncludeFingerprint {
+		secretData[SSHAuthFingerprint] = []byte(key.Fingerprint)
+	}
+	return secretData, nil
+}
+
+// buildBasicAuthSecretData reads the username and password of the item for a kubernetes.io/basic-auth Secret.
+func buildBasicAuthSecretData(item model.Item, config *onepasswordv1.BasicAuthConfig) (map[string][]byte, error) {
+	username, password, err := loginCredentials(item, config.UsernameField, config.PasswordField)
+	if err != nil {
+		return nil, err
+	}
+	return map[string][]byte{
+		corev1.BasicAuthUsernameKey: []byte(username),
+		corev1.BasicAuthPasswordKey: []byte(password),
+	}, nil
+}
+
+// buildHtpasswdSecretData hashes the credentials of each configured user into an htpasswd file.
+// Hashes are salted, so the data of an existing Secret is only replaced when what it is rendered from
+// changes, see sourceAnnotations.
+func buildHtpasswdSecretData(
+	item model.Item,
+	itemPath string,
+	linkedItems map[string]*model.Item,
+	config *onepasswordv1.HtpasswdConfig,
+) (map[string][]byte, error) {
+	users, err := htpasswdUsers(item, itemPath, linkedItems, config)
+	if err != nil {
+		return nil, err
+	}
+	htpasswd, err := template.BuildHtpasswd(users, config.Cost)
+	if err != nil {
+		return nil, err
+	}
+	return map[string][]byte{htpasswdKey(config): htpasswd}, nil
+}
+
+// buildHtpasswdInputs returns the credentials of the users instead of the salted htpasswd file.
+func buildHtpasswdInputs(
+	item model.Item,
+	itemPath string,
+	linkedItems map[string]*model.Item,
+	config *onepasswordv1.HtpasswdConfig,
+) (map[string][]byte, error) {
+	users, err := htpasswdUsers(item, itemPath, linkedItems, config)
+	if err != nil {
+		return nil, err
+	}
+	var credentials bytes.Buffer
+	for _, user := range users {
+		fmt.Fprintf(&credentials, "%s:%s\n", user.Username, user.Password)
+	}
+	return map[string][]byte{htpasswdKey(config): credentials.Bytes()}, nil
+}
+
+// htpasswdUsers returns the credentials of each configured user.
+func htpasswdUsers(
+	item model.Item,
+	itemPath string,
+	linkedItems map[string]*model.Item,
+	config *onepasswordv1.HtpasswdConfig,
+) ([]template.HtpasswdUser, error) {
+	userConfigs := config.Users
+	if len(userConfigs) == 0 {
+		userConfigs = []onepasswordv1.HtpasswdUser{{}}
+	}
+
+	users := make([]template.HtpasswdUser, 0, len(userConfigs))
+	for i, userConfig := range userConfigs {
+		userItem, err := resolveItem(item, itemPath, linkedItems, userConfig.ItemPath)
+		if err != nil {
+			return nil, fmt.Errorf("user %d: %w", i, err)
+		}
+		username, password, err := loginCredentials(userItem, userConfig.UsernameField, userConfig.PasswordField)
+		if err != nil {
+			return nil, fmt.Errorf("user %d: %w", i, err)
+		}
+		users = append(users, template.HtpasswdUser{Username: username, Password: password})
+	}
+	return users, nil
+}
+
+func htpasswdKey(config *onepasswordv1.HtpasswdConfig) string {
+	if config.Key == "" {
+		return formatSecretDataName(HtpasswdKey)
+	}
+	return formatSecretDataName(config.Key)
+}
+
+// loginCredentials returns the username and password of the item. Empty labels select the built-in
+// fields of a Login item, with the first concealed field as fallback for the password.
+func loginCredentials(item model.Item, usernameLabel, passwordLabel string) (string, string, error) {
+	username, ok := findField(item, usernameLabel, isBuiltInField(model.FieldPurposeUsername))
+	if !ok {
+		return "", "", fmt.Errorf("no username field found in item")
+	}
+
+	password, ok := findField(item, passwordLabel, isBuiltInField(model.FieldPurposePassword))
+	if !ok && passwordLabel == "" {
+		// Fall back to the first concealed field, e.g. for API Credential or Password items.
+		password, ok = findField(item, "", func(f model.ItemField) bool {
+			return f.Type() == model.FieldTypeConcealed
+		})
+	}
+	if !ok {
+		return "", "", fmt.Errorf("no password field found in item")
+	}
+	return username.Value, password.Value, nil
+}
+
+// findField returns the field with the given label. When label is empty, it returns the first
+// field matching detect instead.
+func findField(item model.Item, label string, detect func(model.ItemField) bool) (model.ItemField, bool) {
+	for _, field := range item.Fields {
+		if (label != "" && field.Label == label) || (label == "" && detect(field)) {
+			return field, true
+		}
+	}
+	return model.ItemField{}, false
+}
+
+// fieldValueByNames returns the value of the first field whose ID or label matches one of names,
+// trying the names in order. The value is returned as is, so secrets with surrounding whitespace are kept.
+func fieldValueByNames(item model.Item, names []string) string {
+	for _, name := range names {
+		for _, field := range item.Fields {
+			if field.ID == name || strings.EqualFold(field.Label, name) {
+				return field.Value
+			}
+		}
+	}
+	return ""
+}
+
+// lookupFieldValue returns the value of the field with the given label, or an error if the item has
+// no such field. When label is empty, it falls back to fieldValueByNames with the default names.
+func lookupFieldValue(item model.Item, label string, defaults []string) (string, error) {
+	if label == "" {
+		return fieldValueByNames(item, defaults), nil
+	}
+	field, ok := findField(item, label, nil)
+	if !ok {
+		return "", fmt.Errorf("field %q not found", label)
+	}
+	return field.Value, nil
+}
+
+// isBuiltInField detects the built-in field of a Login item with the given purpose. The SDK backend
+// does not report field purposes, so the built-in field ID and the label are checked as well.
+func isBuiltInField(purpose string) func(model.ItemField) bool {
+	name := strings.ToLower(purpose)
+	return func(f model.ItemField) bool {
+		if f.Purpose != "" {
+			return f.Purpose == purpose
+		}
+		return f.ID == name || strings.EqualFold(f.Label, name)
+	}
+}
@@ -0,0 +1,188 @@
+package kubernetessecrets
+
+import (
+	"fmt"
+	"strings"
+
+	onepasswordv1 "github.com/1Password/onepassword-operator/api/v1"
+	"github.com/1Password/onepassword-operator/pkg/onepassword/model"
+	"github.com/1Password/onepassword-operator/pkg/template"
+)
+
+// Default keys of the files in Secrets generated with spec.aws, spec.gcp and spec.kubeconfig.
+const (
+	AWSCredentialsKey    = "credentials"
+	AWSConfigKey         = "config"
+	GCPServiceAccountKey = "key.json"
+	KubeconfigKey        = "kubeconfig"
+)
+
+// gcpKeyFileSuffix identifies the attached service account key when spec.gcp does not name one.
+const gcpKeyFileSuffix = ".json"
+
+// Default field names, matched against the field ID and label, for values not mapped in the spec.
+var (
+	awsAccessKeyIDFields     = []string{"access key id", "aws_access_key_id", "access_key_id", "username"}
+	awsSecretAccessKeyFields = []string{
+		"secret access key", "aws_secret_access_key", "secret_access_key", "credential", "password",
+	}
+	awsSessionTokenFields  = []string{"session token", "aws_session_token"}
+	awsRegionFields        = []string{"region", "aws_region", "default region"}
+	kubeconfigServerFields = []string{"server", "api server", "hostname"}
+	kubeconfigCAFields     = []string{"certificate authority", "certificate-authority", "ca", "ca.crt"}
+	kubeconfigTokenFields  = []string{"token", "bearer token", "credential"}
+)
+
+// buildAWSSecretData writes the AWS shared credentials file and, when a profile has a region,
+// the AWS config file.
+func buildAWSSecretData(
+	item model.Item,
+	itemPath string,
+	linkedItems map[string]*model.Item,
+	config *onepasswordv1.AWSConfig,
+) (map[string][]byte, error) {
+	profileConfigs := config.Profiles
+	if len(profileConfigs) == 0 {
+		profileConfigs = []onepasswordv1.AWSProfileConfig{{}}
+	}
+
+	profiles := make([]template.AWSProfile, 0, len(profileConfigs))
+	for _, profileConfig := range profileConfigs {
+		profile, err := awsProfileFromItem(item, itemPath, linkedItems, profileConfig)
+		if err != nil {
+			name := profileConfig.Name
+			if name == "" {
+				name = "default"
+			}
+			return nil, fmt.Errorf("profile %q: %w", name, err)
+		}
+		profiles = append(profiles, profile)
+	}
+
+	credentials, err := template.BuildAWSCredentials(profiles)
+	if err != nil {
+		return nil, err
+	}
+	awsConfig, err := template.BuildAWSConfig(profiles)
+	if err != nil {
+		return nil, err
+	}
+
+	secretData := map[string][]byte{AWSCredentialsKey: credentials}
+	if awsConfig != nil {
+		secretData[AWSConfigKey] = awsConfig
+	}
+	return secretData, nil
+}
+
+func awsProfileFromItem(
+	item model.Item,
+	itemPath string,
+	linkedItems map[string]*model.Item,
+	config onepasswordv1.AWSProfileConfig,
+) (template.AWSProfile, error) {
+	profileItem, err := resolveItem(item, itemPath, linkedItems, config.ItemPath)
+	if err != nil {
+		return template.AWSProfile{}, err
+	}
+
+	profile := template.AWSProfile{Name: config.Name, Region: config.Region}
+	profile.AccessKeyID, err = lookupFieldValue(profileItem, config.AccessKeyIDField, awsAccessKeyIDFields)
+	if err != nil {
+		return template.AWSProfile{}, err
+	}
+	profile.SecretAccessKey, err = lookupFieldValue(profileItem, config.SecretAccessKeyField, awsSecretAccessKeyFields)
+	if err != nil {
+		return template.AWSProfile{}, err
+	}
+	profile.SessionToken, err = lookupFieldValue(profileItem, config.SessionTokenField, awsSessionTokenFields)
+	if err != nil {
+		return template.AWSProfile{}, err
+	}
+	if profile.Region == "" {
+		if profile.Region, err = lookupFieldValue(profileItem, config.RegionField, awsRegionFields); err != nil {
+			return template.AWSProfile{}, err
+		}
+	}
+	return profile, nil
+}
+
+// buildGCPSecretData writes the validated service account key of the item.
+func buildGCPSecretData(item model.Item, config *onepasswordv1.GCPConfig) (map[string][]byte, error) {
+	keyFile := config.KeyFile
+	if config.KeyField == "" && keyFile == "" {
+		for _, file := range item.Files {
+			if strings.HasSuffix(strings.ToLower(file.Name), gcpKeyFileSuffix) {
+				keyFile = file.Name
+				break
+			}
+		}
+		if keyFile == "" {
+			return nil, fmt.Errorf("no .json file attached to item, set keyField or keyFile")
+		}
+	}
+
+	data, err := itemValue(item, config.KeyField, keyFile)
+	if err != nil {
+		return nil, err
+	}
+	key, err := template.BuildGCPServiceAccountKey(data)
+	if err != nil {
+		return nil, err
+	}
+
+	secretKey := config.Key
+	if secretKey == "" {
+		secretKey = GCPServiceAccountKey
+	}
+	return map[string][]byte{formatSecretDataName(secretKey): key}, nil
+}
+
+// buildKubeconfigSecretData assembles a kubeconfig from the server, CA and token of the item.
+func buildKubeconfigSecretData(item model.Item, config *onepasswordv1.KubeconfigConfig) (map[string][]byte, error) {
+	server, err := lookupFieldValue(item, config.ServerField, kubeconfigServerFields)
+	if err != nil {
+		return nil, err
+	}
+	if server == "" {
+		for _, url := range item.URLs {
+			if url.Primary {
+				server = url.URL
+				break
+			}
+		}
+	}
+	if server == "" {
+		return nil, fmt.Errorf("no server field found in item")
+	}
+
+	token, err := lookupFieldValue(item, config.TokenField, kubeconfigTokenFields)
+	if err != nil {
+		return nil, err
+	}
+
+	ca, err := itemValue(item, config.CAField, config.CAFile)
+	if err != nil {
+		return nil, fmt.Errorf("CA: %w", err)
+	}
+	if ca == nil {
+		ca = []byte(fieldValueByNames(item, kubeconfigCAFields))
+	}
+
+	kubeconfig, err := template.BuildKubeconfig(template.KubeconfigOptions{
+		Name:      config.Name,
+		Server:    server,
+		CA:        ca,
+		Token:     token,
+		Namespace: config.Namespace,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	secretKey := config.Key
+	if secretKey == "" {
+		secretKey = KubeconfigKey
+	}
+	return map[string][]byte{formatSecretDataName(secretKey): kubeconfig}, nil
+}
@@ -0,0 +1,109 @@
+package kubernetessecrets
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	onepasswordv1 "github.com/1Password/onepassword-operator/api/v1"
+	"github.com/1Password/onepassword-operator/pkg/onepassword/model"
+	"github.com/1Password/onepassword-operator/pkg/template"
+)
+
+// Keys of a Secret generated from a Database item.
+const (
+	DatabaseTypeKey     = "type"
+	DatabaseHostKey     = "host"
+	DatabasePortKey     = "port"
+	DatabaseNameKey     = "database"
+	DatabaseUsernameKey = "username"
+	DatabasePasswordKey = "password"
+	DatabaseURIKey      = "uri"
+	DatabaseJDBCURLKey  = "jdbc-url"
+)
+
+// databaseFieldType, databaseFieldServer, ... identify the built-in fields of a Database item
+// by their field ID first and their default label second.
+var (
+	databaseFieldType     = []string{"database_type", "type"}
+	databaseFieldServer   = []string{"hostname", "server"}
+	databaseFieldPort     = []string{"port"}
+	databaseFieldDatabase = []string{"database"}
+	databaseFieldUsername = []string{"username"}
+	databaseFieldPassword = []string{"password"}
+	databaseFieldOptions  = []string{"options", "connection options"}
+)
+
+// buildDatabaseSecretData emits normalized connection keys and URIs for a Database item.
+// Database types without a URI format, e.g. oracle, only get the normalized keys.
+func buildDatabaseSecretData(item model.Item, config *onepasswordv1.DatabaseConfig) (map[string][]byte, error) {
+	connection := template.DatabaseConnection{
+		Type:     config.Type,
+		Host:     strings.TrimSpace(fieldValueByNames(item, databaseFieldServer)),
+		Port:     strings.TrimSpace(fieldValueByNames(item, databaseFieldPort)),
+		Database: strings.TrimSpace(fieldValueByNames(item, databaseFieldDatabase)),
+		Username: strings.TrimSpace(fieldValueByNames(item, databaseFieldUsername)),
+		// The password is kept as is: surrounding whitespace may be part of it.
+		Password: fieldValueByNames(item, databaseFieldPassword),
+	}
+	if connection.Type == "" {
+		connection.Type = strings.TrimSpace(fieldValueByNames(item, databaseFieldType))
+	}
+	if connection.Type == "" {
+		return nil, fmt.Errorf("no database type found in item, set spec.database.type")
+	}
+	if connection.Host == "" {
+		return nil, fmt.Errorf("no server field found in item")
+	}
+
+	params, err := databaseParams(strings.TrimSpace(fieldValueByNames(item, databaseFieldOptions)), config.Params)
+	if err != nil {
+		return nil, err
+	}
+	connection.Params = params
+
+	secretData := map[string][]byte{
+		DatabaseTypeKey: []byte(template.NormalizeDatabaseType(connection.Type)),
+		DatabaseHostKey: []byte(connection.Host),
+	}
+	for key, value := range map[string]string{
+		DatabasePortKey:     connection.DatabasePort(),
+		DatabaseNameKey:     connection.Database,
+		DatabaseUsernameKey: connection.Username,
+		DatabasePasswordKey: connection.Password,
+	} {
+		if value != "" {
+			secretData[key] = []byte(value)
+		}
+	}
+
+	uri, err := template.BuildDatabaseURI(connection)
+	if err != nil {
+		log.Info(fmt.Sprintf("Skipping connection URI for item %q: %s", item.ID, err))
+		return secretData, nil
+	}
+	secretData[DatabaseURIKey] = []byte(uri)
+	if jdbcURL, ok := template.BuildJDBCURL(connection); ok {
+		secretData[DatabaseJDBCURLKey] = []byte(jdbcURL)
+	}
+	return secretData, nil
+}
+
+// databaseParams parses the connection options of the item as a query string, e.g.
+// "sslmode=require&connect_timeout=10", and applies the params of the spec on top.
+func databaseParams(options string, overrides map[string]string) (map[string]string, error) {
+	params := map[string]string{}
+	if options = strings.TrimPrefix(options, "?"); options != "" {
+		values, err := url.ParseQuery(options)
+		if err != nil {
+			return nil, fmt.Errorf("invalid connection options %q: %w", options, err)
+		}
+		for key := range values {
+			params[key] = values.Get(key)
+		}
+	}
+	for key, value := range overrides {
+		params[key] = value
+	}
+	return params, nil
+}
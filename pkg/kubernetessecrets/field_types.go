@@ -0,0 +1,42 @@
+package kubernetessecrets
+
+import (
+	"slices"
+
+	onepasswordv1 "github.com/1Password/onepassword-operator/api/v1"
+	"github.com/1Password/onepassword-operator/pkg/onepassword/model"
+	"github.com/1Password/onepassword-operator/pkg/template"
+)
+
+// fieldTypeSelected reports whether the rules export the field. A notes field matches both
+// NOTES and its STRING type.
+func fieldTypeSelected(field model.ItemField, rules *onepasswordv1.FieldTypeRules) bool {
+	if rules == nil {
+		return true
+	}
+	types := []string{field.Type()}
+	if field.Purpose == model.FieldPurposeNotes {
+		types = append(types, onepasswordv1.FieldTypeNotes)
+	}
+	matches := func(selected []string) bool {
+		for _, t := range types {
+			if slices.Contains(selected, t) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if len(rules.Include) > 0 && !matches(rules.Include) {
+		return false
+	}
+	return !matches(rules.Exclude)
+}
+
+// fieldValue returns the value of the field, rendered according to its type when the rules ask for it.
+func fieldValue(field model.ItemField, rules *onepasswordv1.FieldTypeRules) string {
+	if rules != nil && rules.FormatValues {
+		return template.FormatFieldValue(field)
+	}
+	return field.Value
+}
@@ -0,0 +1,91 @@
+package kubernetessecrets
+
+import (
+	"reflect"
+	"testing"
+
+	onepasswordv1 "github.com/1Password/onepassword-operator/api/v1"
+	"github.com/1Password/onepassword-operator/pkg/onepassword/model"
+)
+
+func typedItem() model.Item {
+	return model.Item{Fields: []model.ItemField{
+		{Label: "username", Value: "app", FieldType: "STRING", Purpose: model.FieldPurposeUsername},
+		{Label: "password", Value: "secret", FieldType: "CONCEALED", Purpose: model.FieldPurposePassword},
+		{Label: "notesPlain", Value: "do not share", FieldType: "STRING", Purpose: model.FieldPurposeNotes},
+		{Label: "one-time password", Value: "otpauth://totp/app?secret=JBSWY3DPEHPK3PXP", FieldType: "OTP"},
+		{Label: "expires", Value: "1704067200", FieldType: "DATE"},
+		{Label: "api key", Value: "key", FieldType: "Concealed"},
+	}}
+}
+
+func TestBuildKubernetesSecretDataFieldTypes(t *testing.T) {
+	tests := map[string]struct {
+		rules *onepasswordv1.FieldTypeRules
+		want  map[string]string
+	}{
+		"no rules": {
+			want: map[string]string{
+				"username":          "app",
+				"password":          "secret",
+				"notesPlain":        "do not share",
+				"one-time-password": "otpauth://totp/app?secret=JBSWY3DPEHPK3PXP",
+				"expires":           "1704067200",
+				"api-key":           "key",
+			},
+		},
+		"only concealed": {
+			rules: &onepasswordv1.FieldTypeRules{Include: []string{"CONCEALED"}},
+			want: map[string]string{
+				"password": "secret",
+				"api-key":  "key",
+			},
+		},
+		"skip otp and notes": {
+			rules: &onepasswordv1.FieldTypeRules{Exclude: []string{"OTP", onepasswordv1.FieldTypeNotes}},
+			want: map[string]string{
+				"username": "app",
+				"password": "secret",
+				"expires":  "1704067200",
+				"api-key":  "key",
+			},
+		},
+		"include strings but not notes": {
+			rules: &onepasswordv1.FieldTypeRules{
+				Include: []string{"STRING"},
+				Exclude: []string{onepasswordv1.FieldTypeNotes},
+			},
+			want: map[string]string{
+				"username": "app",
+			},
+		},
+		"format values": {
+			rules: &onepasswordv1.FieldTypeRules{
+				Include:      []string{"OTP", "DATE"},
+				FormatValues: true,
+			},
+			want: map[string]string{
+				"one-time-password": "JBSWY3DPEHPK3PXP",
+				"expires":           "2024-01-01T00:00:00Z",
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			spec := &onepasswordv1.OnePasswordItemSpec{FieldTypes: tc.rules}
+			secretData, err := BuildKubernetesSecretData(typedItem(), false, spec, nil)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			got := map[string]string{}
+			for key, value := range secretData {
+				got[key] = string(value)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("Expected secret data %v, got %v", tc.want, got)
+			}
+		})
+	}
+}
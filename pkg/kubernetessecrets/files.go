@@ -0,0 +1,107 @@
+package kubernetessecrets
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	onepasswordv1 "github.com/1Password/onepassword-operator/api/v1"
+	"github.com/1Password/onepassword-operator/pkg/onepassword/model"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// selectFiles returns the item with only the file attachments selected by the config, renamed
+// as configured. Files are not downloaded, so the size limit is checked against their metadata.
+func selectFiles(item model.Item, config *onepasswordv1.FilesConfig) (model.Item, error) {
+	if config == nil {
+		return item, nil
+	}
+
+	files := item.Files
+	if len(config.Select) > 0 {
+		files = nil
+		selected := make(map[int]bool, len(item.Files))
+		for _, selector := range config.Select {
+			matched, err := matchFiles(item.Files, selector.Name)
+			if err != nil {
+				return model.Item{}, err
+			}
+			if len(matched) == 0 && !isGlob(selector.Name) {
+				return model.Item{}, fmt.Errorf("file %q not found", selector.Name)
+			}
+			if selector.Key != "" && len(matched) > 1 {
+				return model.Item{}, fmt.Errorf("file pattern %q with key %q matches %d files, it must match one",
+					selector.Name, selector.Key, len(matched))
+			}
+			for _, i := range matched {
+				if selected[i] {
+					continue
+				}
+				selected[i] = true
+				file := item.Files[i]
+				if selector.Key != "" {
+					file.Name = selector.Key
+				}
+				files = append(files, file)
+			}
+		}
+	}
+
+	if config.MaxSize != nil {
+		maxSize := config.MaxSize.Value()
+		for _, file := range files {
+			if int64(file.Size) > maxSize {
+				return model.Item{}, fmt.Errorf("file %q is %d bytes, which exceeds the maximum size of %s",
+					file.Name, file.Size, config.MaxSize.String())
+			}
+		}
+	}
+
+	item.Files = files
+	return item, nil
+}
+
+// matchFiles returns the indexes of the files whose name matches the name or glob pattern.
+func matchFiles(files []model.File, pattern string) ([]int, error) {
+	var matched []int
+	for i, file := range files {
+		ok, err := path.Match(pattern, file.Name)
+		if err != nil {
+			return nil, fmt.Errorf("invalid file pattern %q: %w", pattern, err)
+		}
+		if ok {
+			matched = append(matched, i)
+		}
+	}
+	return matched, nil
+}
+
+func isGlob(pattern string) bool {
+	return strings.ContainsAny(pattern, `*?[\`)
+}
+
+// loadFileContents downloads the content of every file of the item, so a failed download fails the
+// sync instead of silently leaving the file out.
+func loadFileContents(item model.Item) error {
+	for _, file := range item.Files {
+		if _, err := file.Content(); err != nil && !errors.Is(err, model.ErrFileContentNotLoaded) {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkSecretSize returns an error if the secret data exceeds the maximum size of a Secret,
+// which the API server would reject.
+func checkSecretSize(secretData map[string][]byte) error {
+	size := 0
+	for key, value := range secretData {
+		size += len(key) + len(value)
+	}
+	if size > corev1.MaxSecretSize {
+		return fmt.Errorf("secret data is %d bytes, which exceeds the maximum secret size of %d bytes; "+
+			"use spec.files to select fewer file attachments", size, corev1.MaxSecretSize)
+	}
+	return nil
+}
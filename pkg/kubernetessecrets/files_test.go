@@ -0,0 +1,194 @@
+package kubernetessecrets
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	onepasswordv1 "github.com/1Password/onepassword-operator/api/v1"
+	"github.com/1Password/onepassword-operator/pkg/onepassword/model"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// fileItem returns an item with lazily loaded files and the number of downloads per file name.
+func fileItem(files map[string]string, order ...string) (model.Item, map[string]int) {
+	downloads := map[string]int{}
+	item := model.Item{}
+	for _, name := range order {
+		content := files[name]
+		file := model.File{ID: name, Name: name, Size: len(content)}
+		file.SetContentLoader(func() ([]byte, error) {
+			downloads[name]++
+			return []byte(content), nil
+		})
+		item.Files = append(item.Files, file)
+	}
+	return item, downloads
+}
+
+func TestBuildKubernetesSecretDataFiles(t *testing.T) {
+	files := map[string]string{
+		"config.yaml": "config",
+		"ca.pem":      "ca",
+		"client.pem":  "client",
+		"notes.txt":   "notes",
+	}
+	order := []string{"config.yaml", "ca.pem", "client.pem", "notes.txt"}
+
+	tests := map[string]struct {
+		config *onepasswordv1.FilesConfig
+		want   map[string]string
+	}{
+		"all files": {
+			want: files,
+		},
+		"exact name": {
+			config: &onepasswordv1.FilesConfig{Select: []onepasswordv1.FileSelector{{Name: "config.yaml"}}},
+			want:   map[string]string{"config.yaml": "config"},
+		},
+		"glob": {
+			config: &onepasswordv1.FilesConfig{Select: []onepasswordv1.FileSelector{{Name: "*.pem"}}},
+			want:   map[string]string{"ca.pem": "ca", "client.pem": "client"},
+		},
+		"glob without matches": {
+			config: &onepasswordv1.FilesConfig{Select: []onepasswordv1.FileSelector{{Name: "*.key"}}},
+			want:   map[string]string{},
+		},
+		"rename": {
+			config: &onepasswordv1.FilesConfig{Select: []onepasswordv1.FileSelector{
+				{Name: "config.yaml", Key: "app.yaml"},
+				{Name: "ca.pem", Key: "ca.crt"},
+			}},
+			want: map[string]string{"app.yaml": "config", "ca.crt": "ca"},
+		},
+		"file selected twice": {
+			config: &onepasswordv1.FilesConfig{Select: []onepasswordv1.FileSelector{
+				{Name: "ca.pem", Key: "ca.crt"},
+				{Name: "*.pem"},
+			}},
+			want: map[string]string{"ca.crt": "ca", "client.pem": "client"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			item, downloads := fileItem(files, order...)
+			spec := &onepasswordv1.OnePasswordItemSpec{Files: tt.config}
+			secretData, err := BuildKubernetesSecretData(item, false, spec, nil)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			got := map[string]string{}
+			for key, value := range secretData {
+				got[key] = string(value)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Expected secret data %v, got %v", tt.want, got)
+			}
+
+			// Only the selected files are downloaded, once each.
+			for _, file := range order {
+				selected := false
+				for _, value := range tt.want {
+					if value == files[file] {
+						selected = true
+					}
+				}
+				expected := 0
+				if selected {
+					expected = 1
+				}
+				if downloads[file] != expected {
+					t.Errorf("Expected file %q to be downloaded %d times, got %d", file, expected, downloads[file])
+				}
+			}
+		})
+	}
+}
+
+func TestBuildKubernetesSecretDataFilesErrors(t *testing.T) {
+	files := map[string]string{"ca.pem": "ca", "client.pem": "client", "big.bin": "0123456789"}
+	order := []string{"ca.pem", "client.pem", "big.bin"}
+	maxSize := resource.MustParse("8")
+
+	tests := map[string]struct {
+		config *onepasswordv1.FilesConfig
+		want   string
+	}{
+		"missing file": {
+			config: &onepasswordv1.FilesConfig{Select: []onepasswordv1.FileSelector{{Name: "missing.pem"}}},
+			want:   `file "missing.pem" not found`,
+		},
+		"rename of several files": {
+			config: &onepasswordv1.FilesConfig{Select: []onepasswordv1.FileSelector{{Name: "*.pem", Key: "cert.pem"}}},
+			want:   `matches 2 files`,
+		},
+		"invalid pattern": {
+			config: &onepasswordv1.FilesConfig{Select: []onepasswordv1.FileSelector{{Name: "[.pem"}}},
+			want:   `invalid file pattern "[.pem"`,
+		},
+		"file too large": {
+			config: &onepasswordv1.FilesConfig{MaxSize: &maxSize},
+			want:   `file "big.bin" is 10 bytes, which exceeds the maximum size of 8`,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			item, downloads := fileItem(files, order...)
+			spec := &onepasswordv1.OnePasswordItemSpec{Files: tt.config}
+			_, err := BuildKubernetesSecretData(item, false, spec, nil)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("Expected error containing %q, got %v", tt.want, err)
+			}
+			if len(downloads) != 0 {
+				t.Errorf("Expected no file to be downloaded, got %v", downloads)
+			}
+		})
+	}
+}
+
+func TestBuildKubernetesSecretDataMaxSecretSize(t *testing.T) {
+	item := model.Item{}
+	for _, name := range []string{"first.bin", "second.bin"} {
+		file := model.File{Name: name, Size: corev1.MaxSecretSize / 2}
+		file.SetContent(make([]byte, corev1.MaxSecretSize/2))
+		item.Files = append(item.Files, file)
+	}
+
+	_, err := BuildKubernetesSecretData(item, false, nil, nil)
+	if err == nil || !strings.Contains(err.Error(), "exceeds the maximum secret size") {
+		t.Fatalf("Expected maximum secret size error, got %v", err)
+	}
+
+	spec := &onepasswordv1.OnePasswordItemSpec{
+		Files: &onepasswordv1.FilesConfig{Select: []onepasswordv1.FileSelector{{Name: "first.bin"}}},
+	}
+	if _, err := BuildKubernetesSecretData(item, false, spec, nil); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestBuildKubernetesSecretDataFileDownloadError(t *testing.T) {
+	file := model.File{Name: "config.yaml"}
+	file.SetContentLoader(func() ([]byte, error) {
+		return nil, errors.New("download failed")
+	})
+	item := model.Item{Files: []model.File{file}}
+
+	if _, err := BuildKubernetesSecretData(item, false, nil, nil); err == nil ||
+		!strings.Contains(err.Error(), "download failed") {
+		t.Errorf("Expected download error, got %v", err)
+	}
+
+	spec := &onepasswordv1.OnePasswordItemSpec{
+		Template: &onepasswordv1.SecretTemplate{Data: map[string]string{"config": `{{ index .Files "config.yaml" }}`}},
+	}
+	if _, err := BuildKubernetesSecretData(item, false, spec, nil); err == nil ||
+		!strings.Contains(err.Error(), "download failed") {
+		t.Errorf("Expected download error for template, got %v", err)
+	}
+}
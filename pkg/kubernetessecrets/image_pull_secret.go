@@ -0,0 +1,101 @@
+package kubernetessecrets
+
+import (
+	"fmt"
+
+	onepasswordv1 "github.com/1Password/onepassword-operator/api/v1"
+	"github.com/1Password/onepassword-operator/pkg/onepassword/model"
+	"github.com/1Password/onepassword-operator/pkg/template"
+)
+
+// buildDockerConfigJSON resolves every registry entry of the config against the item, or the linked
+// item an entry points to, and merges them into a single .dockerconfigjson.
+func buildDockerConfigJSON(
+	item model.Item,
+	itemPath string,
+	linkedItems map[string]*model.Item,
+	config *onepasswordv1.ImagePullSecretConfig,
+) ([]byte, error) {
+	var entries []onepasswordv1.RegistryCredentials
+	if config.HasDefaultEntry() || len(config.Registries) == 0 {
+		entries = append(entries, onepasswordv1.RegistryCredentials{
+			RegistryField: config.RegistryField,
+			UsernameField: config.UsernameField,
+			PasswordField: config.PasswordField,
+			EmailField:    config.EmailField,
+		})
+	}
+	entries = append(entries, config.Registries...)
+
+	credentials := make([]template.RegistryCredentials, 0, len(entries))
+	for i, entry := range entries {
+		source, err := resolveItem(item, itemPath, linkedItems, entry.ItemPath)
+		if err != nil {
+			return nil, fmt.Errorf("registry entry %d: %w", i, err)
+		}
+
+		fields, err := fieldValuesByLabel(source, entry.Section)
+		if err != nil {
+			return nil, fmt.Errorf("registry entry %d: %w", i, err)
+		}
+
+		registry := entry.Registry
+		if registry == "" && entry.RegistryField != "" {
+			registry = fields[entry.RegistryField]
+		}
+		if registry == "" {
+			registry = registryFromItemURLs(source)
+		}
+
+		credentials = append(credentials, template.RegistryCredentials{
+			Registry: registry,
+			Username: fields[entry.UsernameField],
+			Password: fields[entry.PasswordField],
+			Email:    fields[entry.EmailField],
+		})
+	}
+
+	return template.BuildMultiRegistryDockerConfigJSON(credentials)
+}
+
+// fieldValuesByLabel maps the labels of the item's fields to their values. Fields without a label are
+// skipped. When section is set, only the fields of the section with that title are included.
+func fieldValuesByLabel(item model.Item, section string) (map[string]string, error) {
+	sectionID := ""
+	if section != "" {
+		for _, s := range item.Sections {
+			if s.Title == section {
+				sectionID = s.ID
+				break
+			}
+		}
+		if sectionID == "" {
+			return nil, fmt.Errorf("section %q not found in item %q", section, item.ID)
+		}
+	}
+
+	values := make(map[string]string, len(item.Fields))
+	for _, field := range item.Fields {
+		if field.Label == "" || section != "" && field.SectionID != sectionID {
+			continue
+		}
+		values[field.Label] = field.Value
+	}
+	return values, nil
+}
+
+// registryFromItemURLs derives the registry from the item's primary URL, or its first URL
+// when none is marked primary.
+func registryFromItemURLs(item model.Item) string {
+	if len(item.URLs) == 0 {
+		return ""
+	}
+	url := item.URLs[0]
+	for _, u := range item.URLs {
+		if u.Primary {
+			url = u
+			break
+		}
+	}
+	return template.RegistryFromURL(url.URL)
+}
@@ -0,0 +1,224 @@
+package kubernetessecrets
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	onepasswordv1 "github.com/1Password/onepassword-operator/api/v1"
+	"github.com/1Password/onepassword-operator/pkg/onepassword/model"
+	"github.com/1Password/onepassword-operator/pkg/template"
+)
+
+const harborItemPath = "vaults/registries/items/harbor"
+
+func parseDockerConfigJSON(t *testing.T, secretData map[string][]byte) template.DockerConfigJSON {
+	t.Helper()
+	var config template.DockerConfigJSON
+	if err := json.Unmarshal(secretData[corev1.DockerConfigJsonKey], &config); err != nil {
+		t.Fatalf("Invalid .dockerconfigjson: %v", err)
+	}
+	return config
+}
+
+func TestBuildKubernetesSecretDataWithMultipleRegistries(t *testing.T) {
+	item := model.Item{
+		Sections: []model.ItemSection{
+			{ID: "section-hub", Title: "Docker Hub"},
+		},
+		Fields: []model.ItemField{
+			{Label: "registry", Value: "ghcr.io"},
+			{Label: "login", Value: "ghuser"},
+			{Label: "token", Value: "ghpass"},
+			{Label: "username", Value: "hubuser", SectionID: "section-hub"},
+			{Label: "password", Value: "hubpass", SectionID: "section-hub"},
+		},
+	}
+	linkedItems := map[string]*model.Item{
+		harborItemPath: {
+			Version: 7,
+			URLs:    []model.ItemURL{{URL: "https://harbor.example.com/harbor/projects", Primary: true}},
+			Fields: []model.ItemField{
+				{Label: "username", Value: "robot$ci"},
+				{Label: "credential", Value: "harborpass"},
+			},
+		},
+	}
+	spec := &onepasswordv1.OnePasswordItemSpec{
+		ImagePullSecret: &onepasswordv1.ImagePullSecretConfig{
+			RegistryField: "registry",
+			UsernameField: "login",
+			PasswordField: "token",
+			Registries: []onepasswordv1.RegistryCredentials{
+				{
+					ItemPath:      harborItemPath,
+					UsernameField: "username",
+					PasswordField: "credential",
+				},
+				{
+					Section:       "Docker Hub",
+					Registry:      "docker.io",
+					UsernameField: "username",
+					PasswordField: "password",
+				},
+			},
+		},
+	}
+
+	secretData, err := BuildKubernetesSecretData(item, false, spec, linkedItems)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	config := parseDockerConfigJSON(t, secretData)
+	expected := map[string]string{
+		"ghcr.io":            "ghuser",
+		"harbor.example.com": "robot$ci",
+		"docker.io":          "hubuser",
+	}
+	if len(config.Auths) != len(expected) {
+		t.Fatalf("Expected %d registries, got %v", len(expected), config.Auths)
+	}
+	for registry, username := range expected {
+		if config.Auths[registry].Username != username {
+			t.Errorf("Expected username %q for registry %q, got %q", username, registry, config.Auths[registry].Username)
+		}
+	}
+}
+
+func TestBuildKubernetesSecretDataWithRegistryFromURL(t *testing.T) {
+	item := model.Item{
+		URLs: []model.ItemURL{
+			{URL: "https://example.com/login"},
+			{URL: "https://quay.io/repository/org/app", Primary: true},
+		},
+		Fields: []model.ItemField{
+			// A field without a label must not be taken for the unset registry field.
+			{Label: "", Value: "notes"},
+			{Label: "username", Value: "quayuser"},
+			{Label: "password", Value: "quaypass"},
+		},
+	}
+	spec := &onepasswordv1.OnePasswordItemSpec{
+		ImagePullSecret: &onepasswordv1.ImagePullSecretConfig{
+			UsernameField: "username",
+			PasswordField: "password",
+		},
+	}
+
+	secretData, err := BuildKubernetesSecretData(item, false, spec, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	config := parseDockerConfigJSON(t, secretData)
+	if _, ok := config.Auths["quay.io"]; !ok || len(config.Auths) != 1 {
+		t.Errorf("Expected registry derived from the primary URL, got %v", config.Auths)
+	}
+}
+
+func TestBuildKubernetesSecretDataWithDuplicateRegistries(t *testing.T) {
+	item := model.Item{
+		Fields: []model.ItemField{
+			{Label: "registry", Value: "ghcr.io"},
+			{Label: "username", Value: "user"},
+			{Label: "password", Value: "pass"},
+		},
+	}
+	spec := &onepasswordv1.OnePasswordItemSpec{
+		ImagePullSecret: &onepasswordv1.ImagePullSecretConfig{
+			RegistryField: "registry",
+			UsernameField: "username",
+			PasswordField: "password",
+			Registries: []onepasswordv1.RegistryCredentials{
+				{Registry: "https://ghcr.io", UsernameField: "username", PasswordField: "password"},
+			},
+		},
+	}
+
+	if _, err := BuildKubernetesSecretData(item, false, spec, nil); err == nil {
+		t.Error("Expected an error for duplicate registries")
+	}
+}
+
+func TestBuildKubernetesSecretDataWithMissingLinkedItem(t *testing.T) {
+	spec := &onepasswordv1.OnePasswordItemSpec{
+		ImagePullSecret: &onepasswordv1.ImagePullSecretConfig{
+			Registries: []onepasswordv1.RegistryCredentials{
+				{ItemPath: harborItemPath, UsernameField: "username", PasswordField: "password"},
+			},
+		},
+	}
+
+	if _, err := BuildKubernetesSecretData(model.Item{}, false, spec, nil); err == nil {
+		t.Error("Expected an error when the linked item was not loaded")
+	}
+}
+
+func TestCreateKubernetesSecretFromItemRecordsLinkedItemVersions(t *testing.T) {
+	ctx := context.Background()
+	secretName := "multi-registry"
+
+	item := model.Item{
+		Version: 1,
+		VaultID: testVaultUUID,
+		ID:      testItemUUID,
+	}
+	linkedItem := &model.Item{
+		Version: 3,
+		Fields: []model.ItemField{
+			{Label: "registry", Value: "harbor.example.com"},
+			{Label: "username", Value: "robot"},
+			{Label: "password", Value: "secret"},
+		},
+	}
+	linkedItems := map[string]*model.Item{harborItemPath: linkedItem}
+	spec := &onepasswordv1.OnePasswordItemSpec{
+		ImagePullSecret: &onepasswordv1.ImagePullSecretConfig{
+			Registries: []onepasswordv1.RegistryCredentials{
+				{ItemPath: harborItemPath, RegistryField: "registry", UsernameField: "username", PasswordField: "password"},
+			},
+		},
+	}
+
+	kubeClient := fake.NewClientBuilder().Build()
+	err := CreateKubernetesSecretFromItem(ctx, kubeClient, secretName, testNamespace, &item,
+		restartDeploymentAnnotation, map[string]string{}, map[string]string{},
+		string(corev1.SecretTypeDockerConfigJson), nil, false, spec, linkedItems)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	createdSecret := &corev1.Secret{}
+	err = kubeClient.Get(ctx, types.NamespacedName{Name: secretName, Namespace: testNamespace}, createdSecret)
+	if err != nil {
+		t.Fatalf("Secret was not created: %v", err)
+	}
+	if got := createdSecret.Annotations[LinkedItemVersionsAnnotation]; got != harborItemPath+"=3" {
+		t.Errorf("Expected linked item versions %q, got %q", harborItemPath+"=3", got)
+	}
+
+	// A new version of the linked item alone updates the secret.
+	linkedItem.Version = 4
+	linkedItem.Fields[2].Value = "rotated"
+	err = CreateKubernetesSecretFromItem(ctx, kubeClient, secretName, testNamespace, &item,
+		restartDeploymentAnnotation, map[string]string{}, map[string]string{},
+		string(corev1.SecretTypeDockerConfigJson), nil, false, spec, linkedItems)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	updatedSecret := &corev1.Secret{}
+	err = kubeClient.Get(ctx, types.NamespacedName{Name: secretName, Namespace: testNamespace}, updatedSecret)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	config := parseDockerConfigJSON(t, updatedSecret.Data)
+	if config.Auths["harbor.example.com"].Password != "rotated" {
+		t.Errorf("Expected the secret to be rebuilt from the new linked item version, got %v", config.Auths)
+	}
+}
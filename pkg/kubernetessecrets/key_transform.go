@@ -0,0 +1,76 @@
+package kubernetessecrets
+
+import (
+	"strings"
+	"unicode"
+
+	onepasswordv1 "github.com/1Password/onepassword-operator/api/v1"
+)
+
+// transformKey applies the case, prefix and suffix of the transform to a key. Keys without any
+// letter or digit are returned as an empty string so they are skipped like other invalid keys.
+func transformKey(key string, transform *onepasswordv1.KeyTransform) string {
+	if transform == nil {
+		return key
+	}
+
+	words := splitKeyWords(key)
+	if len(words) == 0 {
+		return ""
+	}
+
+	switch transform.Case {
+	case onepasswordv1.KeyCaseUpperSnake:
+		key = strings.ToUpper(strings.Join(words, "_"))
+	case onepasswordv1.KeyCaseLowerSnake:
+		key = strings.ToLower(strings.Join(words, "_"))
+	case onepasswordv1.KeyCaseKebab:
+		key = strings.ToLower(strings.Join(words, "-"))
+	case onepasswordv1.KeyCaseCamel:
+		var b strings.Builder
+		for i, word := range words {
+			word = strings.ToLower(word)
+			if i > 0 {
+				runes := []rune(word)
+				runes[0] = unicode.ToUpper(runes[0])
+				word = string(runes)
+			}
+			b.WriteString(word)
+		}
+		key = b.String()
+	}
+	return transform.Prefix + key + transform.Suffix
+}
+
+// splitKeyWords splits a key into words at non-alphanumeric characters and case changes,
+// e.g. "API Key", "api-key" and "apiKey" all become "api"/"API" and "Key"/"key".
+// An upper case run followed by a lower case letter starts a new word at its last letter,
+// so "HTTPServer" becomes "HTTP" and "Server".
+func splitKeyWords(key string) []string {
+	var words []string
+	var current []rune
+	flush := func() {
+		if len(current) > 0 {
+			words = append(words, string(current))
+			current = nil
+		}
+	}
+
+	runes := []rune(key)
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			flush()
+			continue
+		}
+		if unicode.IsUpper(r) && len(current) > 0 {
+			prev := current[len(current)-1]
+			nextIsLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextIsLower) {
+				flush()
+			}
+		}
+		current = append(current, r)
+	}
+	flush()
+	return words
+}
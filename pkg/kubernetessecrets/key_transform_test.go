@@ -0,0 +1,95 @@
+package kubernetessecrets
+
+import (
+	"reflect"
+	"testing"
+
+	onepasswordv1 "github.com/1Password/onepassword-operator/api/v1"
+	"github.com/1Password/onepassword-operator/pkg/onepassword/model"
+)
+
+func TestTransformKey(t *testing.T) {
+	upperSnake := &onepasswordv1.KeyTransform{Case: onepasswordv1.KeyCaseUpperSnake}
+	lowerSnake := &onepasswordv1.KeyTransform{Case: onepasswordv1.KeyCaseLowerSnake}
+	kebab := &onepasswordv1.KeyTransform{Case: onepasswordv1.KeyCaseKebab}
+	camel := &onepasswordv1.KeyTransform{Case: onepasswordv1.KeyCaseCamel}
+
+	tests := []struct {
+		key       string
+		transform *onepasswordv1.KeyTransform
+		want      string
+	}{
+		{key: "API Key", transform: nil, want: "API Key"},
+		{key: "API Key", transform: upperSnake, want: "API_KEY"},
+		{key: "api-key", transform: upperSnake, want: "API_KEY"},
+		{key: "apiKey", transform: lowerSnake, want: "api_key"},
+		{key: "HTTPServer", transform: kebab, want: "http-server"},
+		{key: "db2Host", transform: kebab, want: "db2-host"},
+		{key: "Client Secret", transform: camel, want: "clientSecret"},
+		{key: "config.json", transform: upperSnake, want: "CONFIG_JSON"},
+		{
+			key:       "password",
+			transform: &onepasswordv1.KeyTransform{Case: onepasswordv1.KeyCaseUpperSnake, Prefix: "DB_", Suffix: "_V1"},
+			want:      "DB_PASSWORD_V1",
+		},
+		{key: "password", transform: &onepasswordv1.KeyTransform{Prefix: "app."}, want: "app.password"},
+		{key: "###", transform: upperSnake, want: ""},
+	}
+
+	for _, tc := range tests {
+		if got := transformKey(tc.key, tc.transform); got != tc.want {
+			t.Errorf("transformKey(%q, %+v): expected %q, got %q", tc.key, tc.transform, tc.want, got)
+		}
+	}
+}
+
+func TestBuildKubernetesSecretDataKeyTransform(t *testing.T) {
+	file := model.File{Name: "ca.crt"}
+	file.SetContent([]byte("ca"))
+	item := model.Item{
+		Fields: []model.ItemField{
+			{Label: "API Key", Value: "key"},
+			{Label: "username", Value: "app"},
+		},
+		URLs:  []model.ItemURL{{URL: "https://example.com", Label: "website", Primary: true}},
+		Files: []model.File{file},
+	}
+	spec := &onepasswordv1.OnePasswordItemSpec{
+		KeyTransform: &onepasswordv1.KeyTransform{Case: onepasswordv1.KeyCaseUpperSnake, Prefix: "APP_"},
+	}
+
+	secretData, err := BuildKubernetesSecretData(item, false, spec, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	want := map[string][]byte{
+		"APP_API_KEY":  []byte("key"),
+		"APP_USERNAME": []byte("app"),
+		"APP_WEBSITE":  []byte("https://example.com"),
+		"APP_CA_CRT":   []byte("ca"),
+	}
+	if !reflect.DeepEqual(secretData, want) {
+		t.Errorf("Expected secret data %v, got %v", want, secretData)
+	}
+}
+
+func TestKeyTransformCollisions(t *testing.T) {
+	item := model.Item{Fields: []model.ItemField{
+		{Label: "api-key", Value: "a"},
+		{Label: "API Key", Value: "b"},
+	}}
+	spec := &onepasswordv1.OnePasswordItemSpec{
+		KeyTransform: &onepasswordv1.KeyTransform{Case: onepasswordv1.KeyCaseUpperSnake},
+		KeyStrategy:  &onepasswordv1.KeyStrategy{OnCollision: onepasswordv1.KeyCollisionError},
+	}
+
+	want := []onepasswordv1.KeyCollision{
+		{Key: "API_KEY", Sources: []string{`field "api-key"`, `field "API Key"`}},
+	}
+	if collisions := KeyCollisions(item, false, spec); !reflect.DeepEqual(collisions, want) {
+		t.Errorf("Expected collisions %v, got %v", want, collisions)
+	}
+	if _, err := BuildKubernetesSecretData(item, false, spec, nil); err == nil {
+		t.Errorf("Expected an error for keys colliding after the transform")
+	}
+}
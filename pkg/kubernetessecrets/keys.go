@@ -0,0 +1,290 @@
+package kubernetessecrets
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	onepasswordv1 "github.com/1Password/onepassword-operator/api/v1"
+	"github.com/1Password/onepassword-operator/pkg/logs"
+	"github.com/1Password/onepassword-operator/pkg/onepassword/model"
+)
+
+// collisionSuffixSeparator separates the key from the counter added by the suffix collision policy.
+const collisionSuffixSeparator = "_"
+
+type secretDataSourceKind int
+
+const (
+	fieldSource secretDataSourceKind = iota
+	urlSource
+	fileSource
+)
+
+// secretDataEntry is a value of the item the default mapping adds to the Secret.
+type secretDataEntry struct {
+	key    string
+	kind   secretDataSourceKind
+	source string
+	value  []byte
+}
+
+// KeyCollisions returns the values of the item that map to the same Secret data key with the
+// default mapping, or nil when the spec uses another output format.
+func KeyCollisions(
+	item model.Item,
+	allowEmptyValues bool,
+	spec *onepasswordv1.OnePasswordItemSpec,
+) []onepasswordv1.KeyCollision {
+	if !usesDefaultMapping(spec) {
+		return nil
+	}
+	if spec == nil {
+		spec = &onepasswordv1.OnePasswordItemSpec{}
+	}
+	item, err := selectFiles(item, spec.Files)
+	if err != nil {
+		return nil
+	}
+	entries, err := secretDataEntries(item, allowEmptyValues, spec)
+	if err != nil {
+		return nil
+	}
+	return findKeyCollisions(entries)
+}
+
+// usesDefaultMapping reports whether the secret data of the spec is built by the default mapping.
+func usesDefaultMapping(spec *onepasswordv1.OnePasswordItemSpec) bool {
+	if spec == nil {
+		return true
+	}
+	return spec.ImagePullSecret == nil && spec.TLS == nil && spec.Keystore == nil &&
+		spec.SSHAuth == nil && spec.BasicAuth == nil && spec.Htpasswd == nil &&
+		spec.Database == nil && spec.AWS == nil && spec.GCP == nil && spec.Kubeconfig == nil &&
+		(spec.Template == nil || (spec.Template.Ref == nil && spec.Template.Data == nil))
+}
+
+// buildDefaultSecretData maps the fields, URLs and files of the item to secret data, resolving
+// keys several values map to with the collision policy of the key strategy.
+func buildDefaultSecretData(
+	item model.Item,
+	allowEmptyValues bool,
+	spec *onepasswordv1.OnePasswordItemSpec,
+) (map[string][]byte, error) {
+	entries, err := secretDataEntries(item, allowEmptyValues, spec)
+	if err != nil {
+		return nil, err
+	}
+
+	var onCollision string
+	if spec.KeyStrategy != nil {
+		onCollision = spec.KeyStrategy.OnCollision
+	}
+
+	if onCollision == onepasswordv1.KeyCollisionError {
+		if collisions := findKeyCollisions(entries); len(collisions) > 0 {
+			descriptions := make([]string, 0, len(collisions))
+			for _, collision := range collisions {
+				descriptions = append(descriptions,
+					fmt.Sprintf("%q (%s)", collision.Key, strings.Join(collision.Sources, ", ")))
+			}
+			return nil, fmt.Errorf("several values map to the same secret key: %s", strings.Join(descriptions, "; "))
+		}
+	}
+
+	secretData := map[string][]byte{}
+	winners := map[string]secretDataEntry{}
+	var suffixed []secretDataEntry
+	for _, entry := range entries {
+		existing, exists := winners[entry.key]
+		if !exists {
+			winners[entry.key] = entry
+			secretData[entry.key] = entry.value
+			continue
+		}
+
+		switch onCollision {
+		case onepasswordv1.KeyCollisionFirst:
+			log.Info(fmt.Sprintf("Ignoring %s because %s maps to the same key %q",
+				entry.source, existing.source, entry.key))
+			continue
+		case onepasswordv1.KeyCollisionLast:
+		case onepasswordv1.KeyCollisionSuffix:
+			suffixed = append(suffixed, entry)
+			continue
+		default:
+			// Only fields overwrite earlier fields; URLs and files never overwrite anything.
+			if entry.kind != fieldSource {
+				log.Info(fmt.Sprintf("Ignoring %s because %s maps to the same key %q",
+					entry.source, existing.source, entry.key))
+				continue
+			}
+		}
+		log.Info(fmt.Sprintf("Overwriting %s with %s because they map to the same key %q",
+			existing.source, entry.source, entry.key))
+		winners[entry.key] = entry
+		secretData[entry.key] = entry.value
+	}
+
+	// Suffixes are added once every key is known, so a suffixed key never replaces another value.
+	counters := map[string]int{}
+	for _, entry := range suffixed {
+		key := nextSuffixedKey(entry.key, secretData, counters)
+		log.Info(fmt.Sprintf("Adding %s as %q because %s maps to the same key %q",
+			entry.source, key, winners[entry.key].source, entry.key))
+		secretData[key] = entry.value
+	}
+	return secretData, nil
+}
+
+// nextSuffixedKey returns the first key with a "_<n>" suffix, starting at 2, that is not yet used.
+func nextSuffixedKey(key string, secretData map[string][]byte, counters map[string]int) string {
+	n := counters[key]
+	if n == 0 {
+		n = 1
+	}
+	for {
+		n++
+		candidate := key + collisionSuffixSeparator + strconv.Itoa(n)
+		if _, used := secretData[candidate]; !used {
+			counters[key] = n
+			return candidate
+		}
+	}
+}
+
+// findKeyCollisions returns the keys several entries map to, sorted by key.
+func findKeyCollisions(entries []secretDataEntry) []onepasswordv1.KeyCollision {
+	sources := map[string][]string{}
+	for _, entry := range entries {
+		sources[entry.key] = append(sources[entry.key], entry.source)
+	}
+
+	var collisions []onepasswordv1.KeyCollision
+	for key, keySources := range sources {
+		if len(keySources) > 1 {
+			collisions = append(collisions, onepasswordv1.KeyCollision{Key: key, Sources: keySources})
+		}
+	}
+	sort.Slice(collisions, func(i, j int) bool {
+		return collisions[i].Key < collisions[j].Key
+	})
+	return collisions
+}
+
+// secretDataEntries returns the values of the item the default mapping adds to the Secret,
+// in the order fields, URLs, files. Values with an invalid key or an empty value are skipped.
+func secretDataEntries(
+	item model.Item,
+	allowEmptyValues bool,
+	spec *onepasswordv1.OnePasswordItemSpec,
+) ([]secretDataEntry, error) {
+	var entries []secretDataEntry
+
+	sectionTitles := make(map[string]string, len(item.Sections))
+	for _, section := range item.Sections {
+		sectionTitles[section.ID] = section.Title
+	}
+
+	naming := spec.KeyStrategy.NamingOrDefault()
+	for _, field := range item.Fields {
+		if !fieldTypeSelected(field, spec.FieldTypes) {
+			log.V(logs.DebugLevel).Info(fmt.Sprintf("Skipping field %q of type %s", field.Label, field.Type()))
+			continue
+		}
+		value := fieldValue(field, spec.FieldTypes)
+
+		sectionTitle := sectionTitles[field.SectionID]
+		if sectionTitle == "" {
+			sectionTitle = field.SectionID
+		}
+
+		name := field.Label
+		switch naming {
+		case onepasswordv1.KeyNamingID:
+			name = field.ID
+		case onepasswordv1.KeyNamingSectionLabel:
+			if sectionTitle != "" {
+				name = sectionTitle + "." + field.Label
+			}
+		}
+
+		key := formatSecretDataName(transformKey(name, spec.KeyTransform))
+		if key == "" {
+			log.Info(fmt.Sprintf("Skipping field with invalid label %q because it must match [-._a-zA-Z0-9]+", name))
+			continue
+		}
+		if emptyValueIsNotAllowed(allowEmptyValues, value) {
+			log.Info(fmt.Sprintf(
+				"Skipping field with empty value for label %q (use --allow-empty-values flag to include)",
+				field.Label,
+			))
+			continue
+		}
+
+		source := fmt.Sprintf("field %q", field.Label)
+		if sectionTitle != "" {
+			source = fmt.Sprintf("field %q (section %q)", field.Label, sectionTitle)
+		}
+		entries = append(entries, secretDataEntry{key: key, kind: fieldSource, source: source, value: []byte(value)})
+	}
+
+	urlsByLabel := processURLsByLabel(item.URLs)
+	addedURLLabels := make(map[string]bool, len(urlsByLabel))
+	for _, url := range item.URLs {
+		// Only the URL chosen for each label is added.
+		if urlsByLabel[url.Label] != url || addedURLLabels[url.Label] {
+			continue
+		}
+		addedURLLabels[url.Label] = true
+		key := formatSecretDataName(transformKey(url.Label, spec.KeyTransform))
+		if key == "" {
+			log.Info(fmt.Sprintf("Skipping URL with invalid label %q because it must match [-._a-zA-Z0-9]+", url.Label))
+			continue
+		}
+		if emptyValueIsNotAllowed(allowEmptyValues, url.URL) {
+			log.Info(fmt.Sprintf(
+				"Skipping URL with empty value for label %q (use --allow-empty-values flag to include)",
+				url.Label,
+			))
+			continue
+		}
+		entries = append(entries, secretDataEntry{
+			key: key, kind: urlSource, source: fmt.Sprintf("url %q", url.Label), value: []byte(url.URL),
+		})
+	}
+
+	for _, file := range item.Files {
+		key := formatSecretDataName(transformKey(file.Name, spec.KeyTransform))
+		if key == "" {
+			log.Info(fmt.Sprintf("Skipping file with invalid name %q because it must match [-._a-zA-Z0-9]+", file.Name))
+			continue
+		}
+
+		content, err := file.Content()
+		if errors.Is(err, model.ErrFileContentNotLoaded) {
+			log.Error(err, fmt.Sprintf("Could not load contents of file %s", file.Name))
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		if emptyValueIsNotAllowed(allowEmptyValues, content) {
+			log.Info(
+				fmt.Sprintf(
+					"Skipping file with empty content for name %q (use --allow-empty-values flag to include)",
+					file.Name,
+				),
+			)
+			continue
+		}
+		if content == nil {
+			continue
+		}
+		entries = append(entries, secretDataEntry{
+			key: key, kind: fileSource, source: fmt.Sprintf("file %q", file.Name), value: content,
+		})
+	}
+	return entries, nil
+}
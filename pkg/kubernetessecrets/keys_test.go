@@ -0,0 +1,180 @@
+package kubernetessecrets
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	onepasswordv1 "github.com/1Password/onepassword-operator/api/v1"
+	"github.com/1Password/onepassword-operator/pkg/onepassword/model"
+)
+
+func collidingItem() model.Item {
+	file := model.File{Name: "password"}
+	file.SetContent([]byte("file-content"))
+	return model.Item{
+		Sections: []model.ItemSection{
+			{ID: "primary-id", Title: "primary"},
+			{ID: "replica-id", Title: "replica"},
+		},
+		Fields: []model.ItemField{
+			{ID: "f1", Label: "password", Value: "primary-password", SectionID: "primary-id"},
+			{ID: "f2", Label: "password", Value: "replica-password", SectionID: "replica-id"},
+			{ID: "f3", Label: "host", Value: "db"},
+		},
+		URLs:  []model.ItemURL{{URL: "https://db.example.com", Label: "host", Primary: true}},
+		Files: []model.File{file},
+	}
+}
+
+func TestBuildKubernetesSecretDataKeyStrategy(t *testing.T) {
+	tests := map[string]struct {
+		strategy *onepasswordv1.KeyStrategy
+		want     map[string]string
+		wantErr  string
+	}{
+		"default": {
+			want: map[string]string{
+				"password": "replica-password",
+				"host":     "db",
+			},
+		},
+		"section.label": {
+			strategy: &onepasswordv1.KeyStrategy{Naming: onepasswordv1.KeyNamingSectionLabel},
+			want: map[string]string{
+				"primary.password": "primary-password",
+				"replica.password": "replica-password",
+				"host":             "db",
+				"password":         "file-content",
+			},
+		},
+		"id": {
+			strategy: &onepasswordv1.KeyStrategy{Naming: onepasswordv1.KeyNamingID},
+			want: map[string]string{
+				"f1":       "primary-password",
+				"f2":       "replica-password",
+				"f3":       "db",
+				"host":     "https://db.example.com",
+				"password": "file-content",
+			},
+		},
+		"first": {
+			strategy: &onepasswordv1.KeyStrategy{OnCollision: onepasswordv1.KeyCollisionFirst},
+			want: map[string]string{
+				"password": "primary-password",
+				"host":     "db",
+			},
+		},
+		"last": {
+			strategy: &onepasswordv1.KeyStrategy{OnCollision: onepasswordv1.KeyCollisionLast},
+			want: map[string]string{
+				"password": "file-content",
+				"host":     "https://db.example.com",
+			},
+		},
+		"suffix": {
+			strategy: &onepasswordv1.KeyStrategy{OnCollision: onepasswordv1.KeyCollisionSuffix},
+			want: map[string]string{
+				"password":   "primary-password",
+				"password_2": "replica-password",
+				"password_3": "file-content",
+				"host":       "db",
+				"host_2":     "https://db.example.com",
+			},
+		},
+		"error": {
+			strategy: &onepasswordv1.KeyStrategy{OnCollision: onepasswordv1.KeyCollisionError},
+			wantErr:  `"password" (field "password" (section "primary"), field "password" (section "replica"), file "password")`,
+		},
+		"error without collisions": {
+			strategy: &onepasswordv1.KeyStrategy{
+				Naming:      onepasswordv1.KeyNamingID,
+				OnCollision: onepasswordv1.KeyCollisionError,
+			},
+			want: map[string]string{
+				"f1":       "primary-password",
+				"f2":       "replica-password",
+				"f3":       "db",
+				"host":     "https://db.example.com",
+				"password": "file-content",
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			spec := &onepasswordv1.OnePasswordItemSpec{KeyStrategy: tc.strategy}
+			secretData, err := BuildKubernetesSecretData(collidingItem(), false, spec, nil)
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("Expected error containing %q, got %v", tc.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			got := map[string]string{}
+			for key, value := range secretData {
+				got[key] = string(value)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("Expected secret data %v, got %v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestBuildKubernetesSecretDataSuffixSkipsUsedKeys(t *testing.T) {
+	item := model.Item{Fields: []model.ItemField{
+		{Label: "token", Value: "a"},
+		{Label: "token", Value: "b"},
+		{Label: "token_2", Value: "c"},
+	}}
+	spec := &onepasswordv1.OnePasswordItemSpec{
+		KeyStrategy: &onepasswordv1.KeyStrategy{OnCollision: onepasswordv1.KeyCollisionSuffix},
+	}
+
+	secretData, err := BuildKubernetesSecretData(item, false, spec, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	want := map[string][]byte{
+		"token":   []byte("a"),
+		"token_2": []byte("c"),
+		"token_3": []byte("b"),
+	}
+	if !reflect.DeepEqual(secretData, want) {
+		t.Errorf("Expected secret data %v, got %v", want, secretData)
+	}
+}
+
+func TestKeyCollisions(t *testing.T) {
+	collisions := KeyCollisions(collidingItem(), false, nil)
+	want := []onepasswordv1.KeyCollision{
+		{Key: "host", Sources: []string{`field "host"`, `url "host"`}},
+		{Key: "password", Sources: []string{
+			`field "password" (section "primary")`,
+			`field "password" (section "replica")`,
+			`file "password"`,
+		}},
+	}
+	if !reflect.DeepEqual(collisions, want) {
+		t.Errorf("Expected collisions %v, got %v", want, collisions)
+	}
+
+	spec := &onepasswordv1.OnePasswordItemSpec{
+		KeyStrategy: &onepasswordv1.KeyStrategy{Naming: onepasswordv1.KeyNamingID},
+	}
+	if collisions := KeyCollisions(collidingItem(), false, spec); collisions != nil {
+		t.Errorf("Expected no collisions when keying by ID, got %v", collisions)
+	}
+
+	spec = &onepasswordv1.OnePasswordItemSpec{
+		Template: &onepasswordv1.SecretTemplate{Data: map[string]string{"dsn": "{{ .Fields.host }}"}},
+	}
+	if collisions := KeyCollisions(collidingItem(), false, spec); collisions != nil {
+		t.Errorf("Expected no collisions for a template, got %v", collisions)
+	}
+}
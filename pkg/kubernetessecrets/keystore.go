@@ -0,0 +1,102 @@
+package kubernetessecrets
+
+import (
+	"fmt"
+
+	onepasswordv1 "github.com/1Password/onepassword-operator/api/v1"
+	"github.com/1Password/onepassword-operator/pkg/onepassword/model"
+	"github.com/1Password/onepassword-operator/pkg/template"
+)
+
+// Keys of the stores in a Secret generated with spec.keystore.
+const (
+	PKCS12KeystoreKey   = "keystore.p12"
+	PKCS12TruststoreKey = "truststore.p12"
+	JKSKeystoreKey      = "keystore.jks"
+	JKSTruststoreKey    = "truststore.jks"
+)
+
+// buildKeystoreSecretData assembles the requested key and trust stores from the PEM data of the item.
+// PKCS#12 and JKS encoding is salted, so the output differs on every call. The data of an existing Secret
+// is therefore only replaced when what it is rendered from changes, see sourceAnnotations.
+func buildKeystoreSecretData(item model.Item, config *onepasswordv1.KeystoreConfig) (map[string][]byte, error) {
+	cert, key, ca, password, err := keystoreInputs(item, config)
+	if err != nil {
+		return nil, err
+	}
+
+	formats := config.Formats
+	if len(formats) == 0 {
+		formats = []string{template.KeystoreFormatPKCS12, template.KeystoreFormatJKS}
+	}
+
+	secretData := map[string][]byte{}
+	for _, format := range formats {
+		stores, err := template.BuildKeystores(cert, key, ca, template.KeystoreOptions{
+			Format:   format,
+			Password: string(password),
+			Alias:    config.Alias,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		keystoreKey, truststoreKey := PKCS12KeystoreKey, PKCS12TruststoreKey
+		if format == template.KeystoreFormatJKS {
+			keystoreKey, truststoreKey = JKSKeystoreKey, JKSTruststoreKey
+		}
+		if stores.Keystore != nil {
+			secretData[keystoreKey] = stores.Keystore
+		}
+		if stores.Truststore != nil {
+			secretData[truststoreKey] = stores.Truststore
+		}
+	}
+
+	if config.PasswordKey != "" {
+		secretData[formatSecretDataName(config.PasswordKey)] = password
+	}
+	return secretData, nil
+}
+
+// buildKeystoreInputs returns the inputs of the stores instead of the salted stores themselves.
+func buildKeystoreInputs(item model.Item, config *onepasswordv1.KeystoreConfig) (map[string][]byte, error) {
+	cert, key, ca, password, err := keystoreInputs(item, config)
+	if err != nil {
+		return nil, err
+	}
+	secretData := map[string][]byte{
+		"certificate": cert,
+		"private-key": key,
+		"ca":          ca,
+		"password":    password,
+	}
+	if config.PasswordKey != "" {
+		secretData[formatSecretDataName(config.PasswordKey)] = password
+	}
+	return secretData, nil
+}
+
+// keystoreInputs returns the PEM data and the password the stores are built from.
+func keystoreInputs(item model.Item, config *onepasswordv1.KeystoreConfig) (cert, key, ca, password []byte, err error) {
+	cert, err = itemValue(item, config.CertificateField, config.CertificateFile)
+	if err != nil {
+		return nil, nil, nil, nil, fmt.Errorf("certificate: %w", err)
+	}
+	key, err = itemValue(item, config.PrivateKeyField, config.PrivateKeyFile)
+	if err != nil {
+		return nil, nil, nil, nil, fmt.Errorf("private key: %w", err)
+	}
+	ca, err = itemValue(item, config.CAField, config.CAFile)
+	if err != nil {
+		return nil, nil, nil, nil, fmt.Errorf("CA: %w", err)
+	}
+	if config.PasswordField == "" {
+		return nil, nil, nil, nil, fmt.Errorf("passwordField is required")
+	}
+	password, err = itemValue(item, config.PasswordField, "")
+	if err != nil {
+		return nil, nil, nil, nil, fmt.Errorf("password: %w", err)
+	}
+	return cert, key, ca, password, nil
+}
@@ -0,0 +1,180 @@
+package kubernetessecrets
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	onepasswordv1 "github.com/1Password/onepassword-operator/api/v1"
+	"github.com/1Password/onepassword-operator/pkg/onepassword/model"
+)
+
+func testKeystoreItem(t *testing.T) model.Item {
+	t.Helper()
+	cert, key := generateTestCertificate(t)
+	return model.Item{
+		ID:      testItemUUID,
+		VaultID: testVaultUUID,
+		Version: 1,
+		Fields: []model.ItemField{
+			{Label: "certificate", Value: cert},
+			{Label: "private key", Value: key},
+			{Label: "ca", Value: cert},
+			{Label: "store password", Value: "changeit"},
+		},
+	}
+}
+
+func testKeystoreConfig() *onepasswordv1.KeystoreConfig {
+	return &onepasswordv1.KeystoreConfig{
+		CertificateField: "certificate",
+		PrivateKeyField:  "private key",
+		CAField:          "ca",
+		PasswordField:    "store password",
+	}
+}
+
+func TestBuildKubernetesSecretDataWithKeystore(t *testing.T) {
+	item := testKeystoreItem(t)
+
+	tests := map[string]struct {
+		config   func(*onepasswordv1.KeystoreConfig)
+		wantKeys []string
+	}{
+		"both formats by default": {
+			config:   func(*onepasswordv1.KeystoreConfig) {},
+			wantKeys: []string{PKCS12KeystoreKey, PKCS12TruststoreKey, JKSKeystoreKey, JKSTruststoreKey},
+		},
+		"JKS only with password key": {
+			config: func(c *onepasswordv1.KeystoreConfig) {
+				c.Formats = []string{"JKS"}
+				c.PasswordKey = "keystore-password"
+			},
+			wantKeys: []string{JKSKeystoreKey, JKSTruststoreKey, "keystore-password"},
+		},
+		"PKCS12 truststore only": {
+			config: func(c *onepasswordv1.KeystoreConfig) {
+				c.CertificateField = ""
+				c.PrivateKeyField = ""
+				c.Formats = []string{"PKCS12"}
+			},
+			wantKeys: []string{PKCS12TruststoreKey},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			config := testKeystoreConfig()
+			tt.config(config)
+			spec := &onepasswordv1.OnePasswordItemSpec{Keystore: config}
+			secretData, err := BuildKubernetesSecretData(item, false, spec, nil)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if len(secretData) != len(tt.wantKeys) {
+				t.Errorf("Expected keys %v, got %d keys", tt.wantKeys, len(secretData))
+			}
+			for _, key := range tt.wantKeys {
+				if len(secretData[key]) == 0 {
+					t.Errorf("Expected key %q to be set", key)
+				}
+			}
+			if config.PasswordKey != "" && string(secretData[config.PasswordKey]) != "changeit" {
+				t.Errorf("Expected password key to contain the store password")
+			}
+		})
+	}
+}
+
+func TestBuildKubernetesSecretDataWithInvalidKeystore(t *testing.T) {
+	item := testKeystoreItem(t)
+
+	tests := map[string]func(*onepasswordv1.KeystoreConfig){
+		"missing password field": func(c *onepasswordv1.KeystoreConfig) { c.PasswordField = "missing" },
+		"missing certificate file": func(c *onepasswordv1.KeystoreConfig) {
+			c.CertificateField = ""
+			c.CertificateFile = "tls.crt"
+		},
+		"key without certificate": func(c *onepasswordv1.KeystoreConfig) {
+			c.CertificateField = ""
+		},
+	}
+
+	for name, mutate := range tests {
+		t.Run(name, func(t *testing.T) {
+			config := testKeystoreConfig()
+			mutate(config)
+			spec := &onepasswordv1.OnePasswordItemSpec{Keystore: config}
+			if _, err := BuildKubernetesSecretData(item, false, spec, nil); err == nil {
+				t.Error("Expected an error")
+			}
+		})
+	}
+}
+
+func TestCreateKubernetesKeystoreSecretOnlyRegeneratesOnNewVersion(t *testing.T) {
+	ctx := context.Background()
+	kubeClient := fake.NewClientBuilder().Build()
+	item := testKeystoreItem(t)
+	spec := &onepasswordv1.OnePasswordItemSpec{Keystore: testKeystoreConfig()}
+	key := types.NamespacedName{Name: "keystore", Namespace: testNamespace}
+
+	var labels map[string]string
+	sync := func() []byte {
+		t.Helper()
+		err := CreateKubernetesSecretFromItem(ctx, kubeClient, key.Name, key.Namespace, &item, "",
+			labels, nil, "", nil, false, spec, nil)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		secret := &corev1.Secret{}
+		if err := kubeClient.Get(ctx, key, secret); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		return secret.Data[PKCS12KeystoreKey]
+	}
+
+	first := sync()
+	if second := sync(); !bytes.Equal(first, second) {
+		t.Error("Expected keystore to be kept for the same item version")
+	}
+
+	labels = map[string]string{"team": "payments"}
+	if relabeled := sync(); !bytes.Equal(first, relabeled) {
+		t.Error("Expected keystore to be kept when only the labels change")
+	}
+
+	item.Version = 2
+	if third := sync(); bytes.Equal(first, third) {
+		t.Error("Expected keystore to be regenerated for a new item version")
+	}
+}
+
+func TestBuildComparableSecretDataWithKeystore(t *testing.T) {
+	item := testKeystoreItem(t)
+	spec := &onepasswordv1.OnePasswordItemSpec{Keystore: testKeystoreConfig()}
+
+	first, err := BuildComparableSecretData(item, false, spec, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	second, err := BuildComparableSecretData(item, false, spec, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, ok := first[PKCS12KeystoreKey]; ok {
+		t.Error("Expected the salted keystore to be replaced by its inputs")
+	}
+	for key, value := range first {
+		if !bytes.Equal(value, second[key]) {
+			t.Errorf("Expected key %q to be the same for the same item", key)
+		}
+	}
+	if string(first["password"]) != "changeit" {
+		t.Errorf("Expected the store password in the inputs, got %q", first["password"])
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"reflect"
 	"regexp"
+	"sort"
 	"strings"
 
 	onepasswordv1 "github.com/1Password/onepassword-operator/api/v1"
@@ -28,6 +29,14 @@ const VersionAnnotation = OnepasswordPrefix + "/item-version"
 const ItemPathAnnotation = OnepasswordPrefix + "/item-path"
 const RestartDeploymentsAnnotation = OnepasswordPrefix + "/auto-restart"
 
+// LinkedItemVersionsAnnotation records the versions of the additional items a Secret is built from,
+// so a change to any of them triggers an update just like a change to the main item.
+const LinkedItemVersionsAnnotation = OnepasswordPrefix + "/linked-item-versions"
+
+// connectionAnnotation records the connection a Secret is built with. It mirrors the ConnectionAnnotation
+// of the onepassword package, which can't be imported from here.
+const connectionAnnotation = OnepasswordPrefix + "/connection"
+
 var ErrCannotUpdateSecretType = errors.New("cannot change secret type: secret type is immutable")
 
 var log = logf.Log
@@ -43,8 +52,8 @@ func CreateKubernetesSecretFromItem(
 	secretType string,
 	ownerRef *metav1.OwnerReference,
 	allowEmptyValues bool,
-	secretTemplate *onepasswordv1.SecretTemplate,
-	imagePullSecret *onepasswordv1.ImagePullSecretConfig,
+	spec *onepasswordv1.OnePasswordItemSpec,
+	linkedItems map[string]*model.Item,
 ) error {
 	itemVersion := fmt.Sprint(item.Version)
 	if secretAnnotations == nil {
@@ -52,6 +61,17 @@ func CreateKubernetesSecretFromItem(
 	}
 	secretAnnotations[VersionAnnotation] = itemVersion
 	secretAnnotations[ItemPathAnnotation] = fmt.Sprintf("vaults/%v/items/%v", item.VaultID, item.ID)
+	if linkedVersions := LinkedItemVersions(linkedItems); linkedVersions != "" {
+		secretAnnotations[LinkedItemVersionsAnnotation] = linkedVersions
+	}
+	if spec != nil {
+		if templateHash := TemplateHash(spec.Template); templateHash != "" {
+			secretAnnotations[TemplateHashAnnotation] = templateHash
+		}
+		if mappingHash := MappingHash(spec); mappingHash != "" {
+			secretAnnotations[MappingHashAnnotation] = mappingHash
+		}
+	}
 
 	if autoRestart != "" {
 		_, err := utils.StringToBool(autoRestart)
@@ -64,11 +84,14 @@ func CreateKubernetesSecretFromItem(
 	}
 
 	// "Opaque" and "" secret types are treated the same by Kubernetes.
-	secret := BuildKubernetesSecretFromOnePasswordItem(secretName, namespace, secretAnnotations, labels,
-		secretType, *item, ownerRef, allowEmptyValues, secretTemplate, imagePullSecret)
+	secret, err := BuildKubernetesSecretFromOnePasswordItem(secretName, namespace, secretAnnotations, labels,
+		secretType, *item, ownerRef, allowEmptyValues, spec, linkedItems)
+	if err != nil {
+		return err
+	}
 
 	currentSecret := &corev1.Secret{}
-	err := kubeClient.Get(ctx, types.NamespacedName{Name: secret.Name, Namespace: secret.Namespace}, currentSecret)
+	err = kubeClient.Get(ctx, types.NamespacedName{Name: secret.Name, Namespace: secret.Namespace}, currentSecret)
 	if err != nil && apierrors.IsNotFound(err) {
 		log.Info(fmt.Sprintf("Creating Secret %v at namespace '%v'", secret.Name, secret.Namespace))
 		return kubeClient.Create(ctx, secret)
@@ -94,9 +117,13 @@ func CreateKubernetesSecretFromItem(
 	currentLabels := currentSecret.Labels
 	if !reflect.DeepEqual(currentAnnotations, secretAnnotations) || !reflect.DeepEqual(currentLabels, labels) {
 		log.Info(fmt.Sprintf("Updating Secret %v at namespace '%v'", secret.Name, secret.Namespace))
+		// Data rendered from the same sources is kept, so metadata changes don't re-encode salted data
+		// such as keystores and htpasswd files.
+		if sourcesChanged(currentAnnotations, secretAnnotations) {
+			currentSecret.Data = secret.Data
+		}
 		currentSecret.Annotations = secretAnnotations
 		currentSecret.Labels = labels
-		currentSecret.Data = secret.Data
 		if err := kubeClient.Update(ctx, currentSecret); err != nil {
 			return fmt.Errorf("kubernetes secret update failed: %w", err)
 		}
@@ -109,6 +136,46 @@ func CreateKubernetesSecretFromItem(
 	return nil
 }
 
+// sourceAnnotations record what the data of a Secret is rendered from.
+var sourceAnnotations = []string{
+	VersionAnnotation,
+	ItemPathAnnotation,
+	LinkedItemVersionsAnnotation,
+	TemplateHashAnnotation,
+	MappingHashAnnotation,
+	connectionAnnotation,
+}
+
+// sourcesChanged reports whether the data of a Secret with the current annotations must be rendered again
+// for the wanted annotations.
+func sourcesChanged(current, wanted map[string]string) bool {
+	for _, key := range sourceAnnotations {
+		if current[key] != wanted[key] {
+			return true
+		}
+	}
+	return false
+}
+
+// SecretTypeForSpec returns the secret type to use for the spec. An explicitly set secret type
+// always wins, otherwise the type required by the configured output format is used.
+func SecretTypeForSpec(secretType string, spec *onepasswordv1.OnePasswordItemSpec) string {
+	if secretType != "" || spec == nil {
+		return secretType
+	}
+	switch {
+	case spec.ImagePullSecret != nil:
+		return string(corev1.SecretTypeDockerConfigJson)
+	case spec.TLS != nil:
+		return string(corev1.SecretTypeTLS)
+	case spec.SSHAuth != nil:
+		return string(corev1.SecretTypeSSHAuth)
+	case spec.BasicAuth != nil:
+		return string(corev1.SecretTypeBasicAuth)
+	}
+	return secretType
+}
+
 func BuildKubernetesSecretFromOnePasswordItem(
 	name, namespace string,
 	annotations map[string]string,
@@ -117,14 +184,19 @@ func BuildKubernetesSecretFromOnePasswordItem(
 	item model.Item,
 	ownerRef *metav1.OwnerReference,
 	allowEmptyValues bool,
-	secretTemplate *onepasswordv1.SecretTemplate,
-	imagePullSecret *onepasswordv1.ImagePullSecretConfig,
-) *corev1.Secret {
+	spec *onepasswordv1.OnePasswordItemSpec,
+	linkedItems map[string]*model.Item,
+) (*corev1.Secret, error) {
 	var ownerRefs []metav1.OwnerReference
 	if ownerRef != nil {
 		ownerRefs = []metav1.OwnerReference{*ownerRef}
 	}
 
+	data, err := BuildKubernetesSecretData(item, allowEmptyValues, spec, linkedItems)
+	if err != nil {
+		return nil, err
+	}
+
 	return &corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:            formatSecretName(name),
@@ -133,128 +205,185 @@ func BuildKubernetesSecretFromOnePasswordItem(
 			Labels:          labels,
 			OwnerReferences: ownerRefs,
 		},
-		Data: BuildKubernetesSecretData(item, allowEmptyValues, secretTemplate, imagePullSecret),
+		Data: data,
 		Type: corev1.SecretType(secretType),
-	}
+	}, nil
 }
 
 func BuildKubernetesSecretData(
 	item model.Item,
 	allowEmptyValues bool,
-	secretTemplate *onepasswordv1.SecretTemplate,
-	imagePullSecret *onepasswordv1.ImagePullSecretConfig,
-) map[string][]byte {
-	// Priority 1: Image pull secret handling.
-	if imagePullSecret != nil {
-		// Build field lookup map
-		fieldMap := make(map[string]string)
-		for _, field := range item.Fields {
-			fieldMap[field.Label] = field.Value
-		}
+	spec *onepasswordv1.OnePasswordItemSpec,
+	linkedItems map[string]*model.Item,
+) (map[string][]byte, error) {
+	return buildKubernetesSecretData(item, allowEmptyValues, spec, linkedItems, false)
+}
 
-		// Extract values from fields using configured labels
-		registry := fieldMap[imagePullSecret.RegistryField]
-		username := fieldMap[imagePullSecret.UsernameField]
-		password := fieldMap[imagePullSecret.PasswordField]
-		email := fieldMap[imagePullSecret.EmailField]
+// BuildComparableSecretData builds the secret data like BuildKubernetesSecretData, except that salted
+// encodings, i.e. keystores and htpasswd files, are replaced by their inputs. The data built from the
+// same items is therefore always equal, and can be compared between backends.
+func BuildComparableSecretData(
+	item model.Item,
+	allowEmptyValues bool,
+	spec *onepasswordv1.OnePasswordItemSpec,
+	linkedItems map[string]*model.Item,
+) (map[string][]byte, error) {
+	return buildKubernetesSecretData(item, allowEmptyValues, spec, linkedItems, true)
+}
 
-		// Build dockerconfigjson
-		dockerConfigJSON, err := template.BuildDockerConfigJSON(registry, username, password, email)
-		if err != nil {
-			log.Error(err, "Failed to build docker config json, falling back to default behavior")
-			// Fall through to default behavior
-		} else {
+func buildKubernetesSecretData(
+	item model.Item,
+	allowEmptyValues bool,
+	spec *onepasswordv1.OnePasswordItemSpec,
+	linkedItems map[string]*model.Item,
+	inputsOnly bool,
+) (map[string][]byte, error) {
+	if spec == nil {
+		spec = &onepasswordv1.OnePasswordItemSpec{}
+	}
+
+	item, err := selectFiles(item, spec.Files)
+	if err != nil {
+		return nil, fmt.Errorf("failed to select files: %w", err)
+	}
+
+	secretData, err := buildSecretData(item, allowEmptyValues, spec, linkedItems, inputsOnly)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkSecretSize(secretData); err != nil {
+		return nil, err
+	}
+	return secretData, nil
+}
+
+// buildSecretData builds the secret data with the output format configured by the spec. When inputsOnly
+// is set, salted encodings are replaced by their inputs.
+func buildSecretData(
+	item model.Item,
+	allowEmptyValues bool,
+	spec *onepasswordv1.OnePasswordItemSpec,
+	linkedItems map[string]*model.Item,
+	inputsOnly bool,
+) (map[string][]byte, error) {
+
+	// Priority 1: Image pull secret handling.
+	if imagePullSecret := spec.ImagePullSecret; imagePullSecret != nil {
+		dockerConfigJSON, err := buildDockerConfigJSON(item, spec.ItemPath, linkedItems, imagePullSecret)
+		if err == nil {
 			return map[string][]byte{
-				".dockerconfigjson": dockerConfigJSON,
-			}
+				corev1.DockerConfigJsonKey: dockerConfigJSON,
+			}, nil
+		}
+		// A list of registries is explicit enough that a partial result would only hide the error.
+		if len(imagePullSecret.Registries) > 0 {
+			return nil, fmt.Errorf("failed to build docker config json: %w", err)
 		}
+		log.Error(err, "Failed to build docker config json, falling back to default behavior")
 	}
 
-	// Priority 2: Template processing.
-	if secretTemplate != nil && secretTemplate.Data != nil {
-		secretData := map[string][]byte{}
-		ctx := template.BuildTemplateContext(&item)
-		for key, tmplStr := range secretTemplate.Data {
-			processed, err := template.ProcessTemplate(tmplStr, ctx)
-			if err != nil {
-				log.Error(err, fmt.Sprintf("Failed to process template for key %q, skipping", key))
-				continue
-			}
-			secretData[formatSecretDataName(key)] = processed
+	// Priority 2: TLS certificate and keystore handling.
+	if spec.TLS != nil {
+		secretData, err := buildTLSSecretData(item, spec.TLS)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build tls secret: %w", err)
 		}
-		return secretData
+		return secretData, nil
 	}
-
-	// Priority 3: Default behavior — map fields, URLs, and files to secret data.
-	secretData := map[string][]byte{}
-
-	urlsByLabel := processURLsByLabel(item.URLs)
-	for key, url := range urlsByLabel {
-		formattedKey := formatSecretDataName(key)
-		if formattedKey == "" {
-			log.Info(fmt.Sprintf("Skipping URL with invalid label %q because it must match [-._a-zA-Z0-9]+", url.Label))
-			continue
+	if spec.Keystore != nil {
+		buildKeystore := buildKeystoreSecretData
+		if inputsOnly {
+			buildKeystore = buildKeystoreInputs
 		}
-		if emptyValueIsNotAllowed(allowEmptyValues, url.URL) {
-			log.Info(fmt.Sprintf(
-				"Skipping URL with empty value for label %q (use --allow-empty-values flag to include)",
-				url.Label,
-			))
-			continue
+		secretData, err := buildKeystore(item, spec.Keystore)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build keystore secret: %w", err)
 		}
-		secretData[formattedKey] = []byte(url.URL)
+		return secretData, nil
 	}
 
-	for i := 0; i < len(item.Fields); i++ {
-		key := formatSecretDataName(item.Fields[i].Label)
-		if key == "" {
-			log.Info(fmt.Sprintf(
-				"Skipping field with invalid label %q because it must match [-._a-zA-Z0-9]+",
-				item.Fields[i].Label,
-			))
-			continue
+	// Priority 3: SSH and basic authentication handling.
+	if spec.SSHAuth != nil {
+		secretData, err := buildSSHAuthSecretData(item, spec.SSHAuth)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build ssh-auth secret: %w", err)
+		}
+		return secretData, nil
+	}
+	if spec.BasicAuth != nil {
+		secretData, err := buildBasicAuthSecretData(item, spec.BasicAuth)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build basic-auth secret: %w", err)
+		}
+		return secretData, nil
+	}
+	if spec.Htpasswd != nil {
+		buildHtpasswd := buildHtpasswdSecretData
+		if inputsOnly {
+			buildHtpasswd = buildHtpasswdInputs
 		}
-		if emptyValueIsNotAllowed(allowEmptyValues, item.Fields[i].Value) {
-			log.Info(fmt.Sprintf(
-				"Skipping field with empty value for label %q (use --allow-empty-values flag to include)",
-				item.Fields[i].Label,
-			))
-			continue
+		secretData, err := buildHtpasswd(item, spec.ItemPath, linkedItems, spec.Htpasswd)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build htpasswd secret: %w", err)
 		}
-		secretData[key] = []byte(item.Fields[i].Value)
+		return secretData, nil
 	}
 
-	// populate unpopulated fields from files
-	for _, file := range item.Files {
-		key := formatSecretDataName(file.Name)
-		if key == "" {
-			log.Info(fmt.Sprintf("Skipping file with invalid name %q because it must match [-._a-zA-Z0-9]+", file.Name))
-			continue
+	// Priority 4: Database connection handling.
+	if spec.Database != nil {
+		secretData, err := buildDatabaseSecretData(item, spec.Database)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build database secret: %w", err)
 		}
+		return secretData, nil
+	}
 
-		content, err := file.Content()
+	// Priority 5: Cloud credential files.
+	if spec.AWS != nil {
+		secretData, err := buildAWSSecretData(item, spec.ItemPath, linkedItems, spec.AWS)
 		if err != nil {
-			log.Error(err, fmt.Sprintf("Could not load contents of file %s", file.Name))
-			continue
+			return nil, fmt.Errorf("failed to build aws secret: %w", err)
 		}
-		if emptyValueIsNotAllowed(allowEmptyValues, content) {
-			log.Info(
-				fmt.Sprintf(
-					"Skipping file with empty content for name %q (use --allow-empty-values flag to include)",
-					file.Name,
-				),
-			)
-			continue
+		return secretData, nil
+	}
+	if spec.GCP != nil {
+		secretData, err := buildGCPSecretData(item, spec.GCP)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build gcp secret: %w", err)
+		}
+		return secretData, nil
+	}
+	if spec.Kubeconfig != nil {
+		secretData, err := buildKubeconfigSecretData(item, spec.Kubeconfig)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build kubeconfig secret: %w", err)
 		}
-		if content != nil {
-			if secretData[key] == nil {
-				secretData[key] = content
-			} else {
-				log.Info(fmt.Sprintf("File '%s' ignored because of a field with the same name", file.Name))
+		return secretData, nil
+	}
+
+	// Priority 6: Template processing.
+	if secretTemplate := spec.Template; secretTemplate != nil && secretTemplate.Ref != nil {
+		return nil, fmt.Errorf("failed to process template %s: %w", secretTemplate.Ref, errUnresolvedTemplateRef)
+	}
+	if secretTemplate := spec.Template; secretTemplate != nil && secretTemplate.Data != nil {
+		if err := loadFileContents(item); err != nil {
+			return nil, fmt.Errorf("failed to process template: %w", err)
+		}
+		secretData := map[string][]byte{}
+		ctx := template.BuildTemplateContext(&item)
+		for key, tmplStr := range secretTemplate.Data {
+			processed, err := template.ProcessTemplateWithPartials(tmplStr, secretTemplate.Partials, ctx)
+			if err != nil {
+				log.Error(err, fmt.Sprintf("Failed to process template for key %q, skipping", key))
+				continue
 			}
+			secretData[formatSecretDataName(key)] = processed
 		}
+		return secretData, nil
 	}
-	return secretData
+
+	// Priority 7: Default behavior — map fields, URLs, and files to secret data.
+	return buildDefaultSecretData(item, allowEmptyValues, spec)
 }
 
 // emptyValueIsNotAllowed checks if the value is empty and empty values are not allowed.
@@ -330,3 +459,39 @@ func processURLsByLabel(urls []model.ItemURL) map[string]model.ItemURL {
 	}
 	return urlsByLabel
 }
+
+// LinkedItemVersions formats the versions of the linked items for the LinkedItemVersionsAnnotation.
+// Items are sorted by path so the value only changes when a version does.
+func LinkedItemVersions(linkedItems map[string]*model.Item) string {
+	if len(linkedItems) == 0 {
+		return ""
+	}
+	paths := make([]string, 0, len(linkedItems))
+	for path := range linkedItems {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	versions := make([]string, 0, len(paths))
+	for _, path := range paths {
+		versions = append(versions, fmt.Sprintf("%s=%d", path, linkedItems[path].Version))
+	}
+	return strings.Join(versions, ",")
+}
+
+// resolveItem returns the item at path, which is either the main item or one of the linked items.
+func resolveItem(
+	item model.Item,
+	itemPath string,
+	linkedItems map[string]*model.Item,
+	path string,
+) (model.Item, error) {
+	if path == "" || path == itemPath {
+		return item, nil
+	}
+	linked, ok := linkedItems[path]
+	if !ok || linked == nil {
+		return model.Item{}, fmt.Errorf("item %q was not loaded", path)
+	}
+	return *linked, nil
+}
@@ -156,7 +156,10 @@ func TestUpdateKubernetesSecretFromOnePasswordItem(t *testing.T) {
 func TestBuildKubernetesSecretData(t *testing.T) {
 	fields := generateFields(5)
 
-	secretData := BuildKubernetesSecretData(model.Item{Fields: fields}, false, nil, nil)
+	secretData, err := BuildKubernetesSecretData(model.Item{Fields: fields}, false, nil, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
 	if len(secretData) != len(fields) {
 		t.Errorf("Unexpected number of secret fields returned. Expected 5, got %v", len(secretData))
 	}
@@ -171,7 +174,10 @@ func TestBuildKubernetesSecretDataWithEmptyValues_Allowed(t *testing.T) {
 		{Label: "empty-field-2", Value: ""},
 	}
 
-	secretData := BuildKubernetesSecretData(model.Item{Fields: fields}, true, nil, nil)
+	secretData, err := BuildKubernetesSecretData(model.Item{Fields: fields}, true, nil, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
 
 	// Verify all fields are present, including empty ones
 	if len(secretData) != len(fields) {
@@ -204,7 +210,10 @@ func TestBuildKubernetesSecretDataWithEmptyValues_Skipped(t *testing.T) {
 	}
 
 	// Test with allowEmptyValues = false (should skip empty fields)
-	secretData := BuildKubernetesSecretData(model.Item{Fields: fields}, false, nil, nil)
+	secretData, err := BuildKubernetesSecretData(model.Item{Fields: fields}, false, nil, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
 
 	// Verify only non-empty fields are present
 	expectedNonEmptyFields := 2
@@ -226,9 +235,12 @@ func TestBuildKubernetesSecretFromOnePasswordItem(t *testing.T) {
 	labels := map[string]string{}
 	secretType := ""
 
-	kubeSecret := BuildKubernetesSecretFromOnePasswordItem(
+	kubeSecret, err := BuildKubernetesSecretFromOnePasswordItem(
 		name, namespace, annotations, labels, secretType, item, nil, false, nil, nil,
 	)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
 	if kubeSecret.Name != strings.ToLower(name) {
 		t.Errorf("Expected name value: %v but got: %v", name, kubeSecret.Name)
 	}
@@ -249,7 +261,10 @@ func TestBuildKubernetesSecretDataWithURLs(t *testing.T) {
 		{URL: "https://another.example.com", Label: "website", Primary: false},
 	}
 
-	secretData := BuildKubernetesSecretData(model.Item{Fields: fields, URLs: urls}, false, nil, nil)
+	secretData, err := BuildKubernetesSecretData(model.Item{Fields: fields, URLs: urls}, false, nil, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
 
 	// Should have fields + all URLs (both have different labels)
 	if len(secretData) != 4 {
@@ -279,7 +294,10 @@ func TestBuildKubernetesSecretDataWithFieldURLConflict(t *testing.T) {
 		{URL: "https://support.example.com", Label: "support", Primary: false},
 	}
 
-	secretData := BuildKubernetesSecretData(model.Item{Fields: fields, URLs: urls}, false, nil, nil)
+	secretData, err := BuildKubernetesSecretData(model.Item{Fields: fields, URLs: urls}, false, nil, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
 
 	// Should have 2 fields + 1 url
 	if len(secretData) != 3 {
@@ -324,7 +342,10 @@ func TestBuildKubernetesSecretData_InvalidLabels(t *testing.T) {
 	files[1].SetContent([]byte("content2"))
 	files[2].SetContent([]byte("content3"))
 
-	secretData := BuildKubernetesSecretData(model.Item{Fields: fields, URLs: urls, Files: files}, false, nil, nil)
+	secretData, err := BuildKubernetesSecretData(model.Item{Fields: fields, URLs: urls, Files: files}, false, nil, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
 
 	if len(secretData) != 0 {
 		t.Errorf("Expected 0 keys, got %d: %v", len(secretData), secretData)
@@ -353,9 +374,12 @@ func TestBuildKubernetesSecretFixesInvalidLabels(t *testing.T) {
 		},
 	}
 
-	kubeSecret := BuildKubernetesSecretFromOnePasswordItem(
+	kubeSecret, err := BuildKubernetesSecretFromOnePasswordItem(
 		name, namespace, annotations, labels, secretType, item, nil, false, nil, nil,
 	)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
 
 	// Assert Secret's meta.name was fixed
 	if kubeSecret.Name != expectedName {
@@ -421,7 +445,10 @@ func TestBuildKubernetesSecretDataWithTemplate(t *testing.T) {
 		},
 	}
 
-	secretData := BuildKubernetesSecretData(item, false, tmpl, nil)
+	secretData, err := BuildKubernetesSecretData(item, false, &onepasswordv1.OnePasswordItemSpec{Template: tmpl}, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
 
 	if len(secretData) != 1 {
 		t.Fatalf("Expected 1 key, got %d", len(secretData))
@@ -448,7 +475,10 @@ func TestBuildKubernetesSecretDataWithTemplateMultipleKeys(t *testing.T) {
 		},
 	}
 
-	secretData := BuildKubernetesSecretData(item, false, tmpl, nil)
+	secretData, err := BuildKubernetesSecretData(item, false, &onepasswordv1.OnePasswordItemSpec{Template: tmpl}, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
 
 	if len(secretData) != 2 {
 		t.Fatalf("Expected 2 keys, got %d", len(secretData))
@@ -475,7 +505,10 @@ func TestBuildKubernetesSecretDataWithTemplateInvalidTemplate(t *testing.T) {
 		},
 	}
 
-	secretData := BuildKubernetesSecretData(item, false, tmpl, nil)
+	secretData, err := BuildKubernetesSecretData(item, false, &onepasswordv1.OnePasswordItemSpec{Template: tmpl}, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
 
 	// The valid key should still be rendered; the invalid key should be skipped
 	if string(secretData["good-key"]) != "admin" {
@@ -495,7 +528,10 @@ func TestBuildKubernetesSecretDataWithTemplateNilData(t *testing.T) {
 	// Template with nil Data should fall through to default behavior
 	tmpl := &onepasswordv1.SecretTemplate{}
 
-	secretData := BuildKubernetesSecretData(item, false, tmpl, nil)
+	secretData, err := BuildKubernetesSecretData(item, false, &onepasswordv1.OnePasswordItemSpec{Template: tmpl}, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
 
 	if len(secretData) != 1 {
 		t.Fatalf("Expected 1 key (default behavior), got %d", len(secretData))
@@ -523,7 +559,10 @@ func TestBuildKubernetesSecretDataWithTemplateSections(t *testing.T) {
 		},
 	}
 
-	secretData := BuildKubernetesSecretData(item, false, tmpl, nil)
+	secretData, err := BuildKubernetesSecretData(item, false, &onepasswordv1.OnePasswordItemSpec{Template: tmpl}, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
 
 	if len(secretData) != 2 {
 		t.Fatalf("Expected 2 keys, got %d", len(secretData))
@@ -550,7 +589,10 @@ func TestBuildKubernetesSecretDataWithTemplateHyphenatedKeys(t *testing.T) {
 		},
 	}
 
-	secretData := BuildKubernetesSecretData(item, false, tmpl, nil)
+	secretData, err := BuildKubernetesSecretData(item, false, &onepasswordv1.OnePasswordItemSpec{Template: tmpl}, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
 
 	expected := "key=abc123,host=localhost"
 	if string(secretData["config"]) != expected {
@@ -581,7 +623,8 @@ func TestCreateKubernetesSecretFromItemWithTemplate(t *testing.T) {
 	}
 
 	err := CreateKubernetesSecretFromItem(ctx, kubeClient, secretName, namespace, &item,
-		restartDeploymentAnnotation, map[string]string{}, map[string]string{}, "", nil, false, tmpl, nil)
+		restartDeploymentAnnotation, map[string]string{}, map[string]string{}, "", nil, false,
+		&onepasswordv1.OnePasswordItemSpec{Template: tmpl}, nil)
 	if err != nil {
 		t.Fatalf("Unexpected error: %v", err)
 	}
@@ -622,7 +665,11 @@ func TestBuildKubernetesSecretDataWithImagePullSecret(t *testing.T) {
 		EmailField:    "email",
 	}
 
-	secretData := BuildKubernetesSecretData(item, false, nil, ips)
+	spec := &onepasswordv1.OnePasswordItemSpec{ImagePullSecret: ips}
+	secretData, err := BuildKubernetesSecretData(item, false, spec, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
 
 	if len(secretData) != 1 {
 		t.Fatalf("Expected 1 key (.dockerconfigjson), got %d", len(secretData))
@@ -653,7 +700,11 @@ func TestBuildKubernetesSecretDataWithImagePullSecretFallback(t *testing.T) {
 		PasswordField: "password",
 	}
 
-	secretData := BuildKubernetesSecretData(item, false, nil, ips)
+	spec := &onepasswordv1.OnePasswordItemSpec{ImagePullSecret: ips}
+	secretData, err := BuildKubernetesSecretData(item, false, spec, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
 
 	// Should fall back to default behavior (fields as keys)
 	if _, exists := secretData[".dockerconfigjson"]; exists {
@@ -689,7 +740,7 @@ func TestCreateKubernetesDockerConfigJsonSecretFromItem(t *testing.T) {
 
 	err := CreateKubernetesSecretFromItem(ctx, kubeClient, secretName, namespace, &item,
 		restartDeploymentAnnotation, map[string]string{}, map[string]string{},
-		string(corev1.SecretTypeDockerConfigJson), nil, false, nil, ips)
+		string(corev1.SecretTypeDockerConfigJson), nil, false, &onepasswordv1.OnePasswordItemSpec{ImagePullSecret: ips}, nil)
 	if err != nil {
 		t.Fatalf("Unexpected error: %v", err)
 	}
@@ -0,0 +1,33 @@
+package kubernetessecrets
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+
+	onepasswordv1 "github.com/1Password/onepassword-operator/api/v1"
+)
+
+// MappingHashAnnotation records a hash of the settings of the default mapping a Secret was rendered with,
+// so changing keyStrategy, keyTransform, fieldTypes or files triggers an update just like a new item version.
+const MappingHashAnnotation = OnepasswordPrefix + "/mapping-hash"
+
+// MappingHash returns a hash of the key strategy, key transform, field type rules and file selection of
+// the spec, or an empty string when none of them is set.
+func MappingHash(spec *onepasswordv1.OnePasswordItemSpec) string {
+	if spec == nil || (spec.KeyStrategy == nil && spec.KeyTransform == nil && spec.FieldTypes == nil &&
+		spec.Files == nil) {
+		return ""
+	}
+	encoded, err := json.Marshal(struct {
+		KeyStrategy  *onepasswordv1.KeyStrategy    `json:"keyStrategy,omitempty"`
+		KeyTransform *onepasswordv1.KeyTransform   `json:"keyTransform,omitempty"`
+		FieldTypes   *onepasswordv1.FieldTypeRules `json:"fieldTypes,omitempty"`
+		Files        *onepasswordv1.FilesConfig    `json:"files,omitempty"`
+	}{spec.KeyStrategy, spec.KeyTransform, spec.FieldTypes, spec.Files})
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(encoded)
+	return hex.EncodeToString(sum[:])
+}
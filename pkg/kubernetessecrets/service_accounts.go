@@ -0,0 +1,161 @@
+package kubernetessecrets
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	onepasswordv1 "github.com/1Password/onepassword-operator/api/v1"
+	"github.com/1Password/onepassword-operator/pkg/utils"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+
+	kubernetesClient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ImagePullSecretsAnnotation lists, comma separated, the image pull secrets the operator
+// appended to a ServiceAccount. References added by anyone else are never removed.
+const ImagePullSecretsAnnotation = OnepasswordPrefix + "/image-pull-secrets"
+
+// SyncServiceAccountImagePullSecret appends a reference to the image pull secret to every
+// ServiceAccount in the namespace selected by the config, and removes the reference from
+// ServiceAccounts the operator previously attached it to that are no longer selected.
+// A nil config removes the reference from every ServiceAccount the operator attached it to.
+func SyncServiceAccountImagePullSecret(
+	ctx context.Context,
+	kubeClient kubernetesClient.Client,
+	secretName, namespace string,
+	imagePullSecret *onepasswordv1.ImagePullSecretConfig,
+) error {
+	secretName = formatSecretName(secretName)
+
+	selected, err := selectServiceAccounts(ctx, kubeClient, namespace, imagePullSecret)
+	if err != nil {
+		return err
+	}
+
+	serviceAccounts := &corev1.ServiceAccountList{}
+	if err := kubeClient.List(ctx, serviceAccounts, kubernetesClient.InNamespace(namespace)); err != nil {
+		return fmt.Errorf("failed to list service accounts: %w", err)
+	}
+
+	for _, sa := range serviceAccounts.Items {
+		attach := selected[sa.Name]
+		if attach == isManagedImagePullSecret(&sa, secretName) {
+			continue
+		}
+		key := kubernetesClient.ObjectKeyFromObject(&sa)
+		err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+			current := &corev1.ServiceAccount{}
+			if err := kubeClient.Get(ctx, key, current); err != nil {
+				return err
+			}
+			var changed bool
+			if attach {
+				changed = attachImagePullSecret(current, secretName)
+			} else {
+				changed = detachImagePullSecret(current, secretName)
+			}
+			if !changed {
+				return nil
+			}
+			return kubeClient.Update(ctx, current)
+		})
+		if err != nil {
+			return fmt.Errorf("failed to update image pull secrets of service account %v: %w", sa.Name, err)
+		}
+	}
+	return nil
+}
+
+// selectServiceAccounts returns the names of the ServiceAccounts the config selects,
+// either by name or by label selector.
+func selectServiceAccounts(
+	ctx context.Context,
+	kubeClient kubernetesClient.Client,
+	namespace string,
+	imagePullSecret *onepasswordv1.ImagePullSecretConfig,
+) (map[string]bool, error) {
+	selected := map[string]bool{}
+	if imagePullSecret == nil {
+		return selected, nil
+	}
+
+	for _, name := range imagePullSecret.ServiceAccounts {
+		selected[name] = true
+	}
+
+	if imagePullSecret.ServiceAccountSelector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(imagePullSecret.ServiceAccountSelector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid serviceAccountSelector: %w", err)
+		}
+		serviceAccounts := &corev1.ServiceAccountList{}
+		err = kubeClient.List(ctx, serviceAccounts, kubernetesClient.InNamespace(namespace),
+			kubernetesClient.MatchingLabelsSelector{Selector: selector})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list service accounts: %w", err)
+		}
+		for _, sa := range serviceAccounts.Items {
+			selected[sa.Name] = true
+		}
+	}
+	return selected, nil
+}
+
+func isManagedImagePullSecret(sa *corev1.ServiceAccount, secretName string) bool {
+	return utils.ContainsString(managedImagePullSecrets(sa), secretName)
+}
+
+func managedImagePullSecrets(sa *corev1.ServiceAccount) []string {
+	value := sa.Annotations[ImagePullSecretsAnnotation]
+	if value == "" {
+		return nil
+	}
+	return strings.Split(value, ",")
+}
+
+func setManagedImagePullSecrets(sa *corev1.ServiceAccount, secretNames []string) {
+	if len(secretNames) == 0 {
+		delete(sa.Annotations, ImagePullSecretsAnnotation)
+		return
+	}
+	if sa.Annotations == nil {
+		sa.Annotations = map[string]string{}
+	}
+	sort.Strings(secretNames)
+	sa.Annotations[ImagePullSecretsAnnotation] = strings.Join(secretNames, ",")
+}
+
+// attachImagePullSecret adds the secret reference to the ServiceAccount and records it as managed.
+// A reference that already exists but was not added by the operator is left unmanaged.
+func attachImagePullSecret(sa *corev1.ServiceAccount, secretName string) bool {
+	for _, ref := range sa.ImagePullSecrets {
+		if ref.Name == secretName {
+			return false
+		}
+	}
+	sa.ImagePullSecrets = append(sa.ImagePullSecrets, corev1.LocalObjectReference{Name: secretName})
+	setManagedImagePullSecrets(sa, append(managedImagePullSecrets(sa), secretName))
+	return true
+}
+
+// detachImagePullSecret removes a secret reference previously added by the operator.
+func detachImagePullSecret(sa *corev1.ServiceAccount, secretName string) bool {
+	managed := managedImagePullSecrets(sa)
+	if !utils.ContainsString(managed, secretName) {
+		return false
+	}
+
+	refs := make([]corev1.LocalObjectReference, 0, len(sa.ImagePullSecrets))
+	for _, ref := range sa.ImagePullSecrets {
+		if ref.Name != secretName {
+			refs = append(refs, ref)
+		}
+	}
+	sa.ImagePullSecrets = refs
+	setManagedImagePullSecrets(sa, utils.RemoveString(managed, secretName))
+	return true
+}
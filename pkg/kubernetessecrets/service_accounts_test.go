@@ -0,0 +1,138 @@
+package kubernetessecrets
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	onepasswordv1 "github.com/1Password/onepassword-operator/api/v1"
+)
+
+func newServiceAccount(name string, labels map[string]string, pullSecrets ...string) *corev1.ServiceAccount {
+	sa := &corev1.ServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: testNamespace,
+			Labels:    labels,
+		},
+	}
+	for _, secret := range pullSecrets {
+		sa.ImagePullSecrets = append(sa.ImagePullSecrets, corev1.LocalObjectReference{Name: secret})
+	}
+	return sa
+}
+
+func getPullSecretNames(ctx context.Context, t *testing.T, kubeClient client.Client, name string) []string {
+	sa := &corev1.ServiceAccount{}
+	if err := kubeClient.Get(ctx, types.NamespacedName{Name: name, Namespace: testNamespace}, sa); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var names []string
+	for _, ref := range sa.ImagePullSecrets {
+		names = append(names, ref.Name)
+	}
+	return names
+}
+
+func TestSyncServiceAccountImagePullSecret(t *testing.T) {
+	ctx := context.Background()
+	secretName := "registry-credentials"
+
+	kubeClient := fake.NewClientBuilder().WithObjects(
+		newServiceAccount("default", nil, "existing"),
+		newServiceAccount("builder", map[string]string{"ci": "true"}),
+		newServiceAccount("other", nil),
+	).Build()
+
+	config := &onepasswordv1.ImagePullSecretConfig{
+		ServiceAccounts: []string{"default", "missing"},
+		ServiceAccountSelector: &metav1.LabelSelector{
+			MatchLabels: map[string]string{"ci": "true"},
+		},
+	}
+	err := SyncServiceAccountImagePullSecret(ctx, kubeClient, secretName, testNamespace, config)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := map[string][]string{
+		"default": {"existing", secretName},
+		"builder": {secretName},
+		"other":   nil,
+	}
+	for name, want := range expected {
+		got := getPullSecretNames(ctx, t, kubeClient, name)
+		if len(got) != len(want) {
+			t.Errorf("Expected image pull secrets %v on %v but got %v", want, name, got)
+			continue
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Errorf("Expected image pull secrets %v on %v but got %v", want, name, got)
+			}
+		}
+	}
+
+	// Syncing again must not add the reference twice.
+	err = SyncServiceAccountImagePullSecret(ctx, kubeClient, secretName, testNamespace, config)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got := getPullSecretNames(ctx, t, kubeClient, "builder"); len(got) != 1 {
+		t.Errorf("Expected a single image pull secret on builder but got %v", got)
+	}
+
+	// Dropping a ServiceAccount from the config detaches it.
+	config.ServiceAccountSelector = nil
+	err = SyncServiceAccountImagePullSecret(ctx, kubeClient, secretName, testNamespace, config)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got := getPullSecretNames(ctx, t, kubeClient, "builder"); len(got) != 0 {
+		t.Errorf("Expected no image pull secrets on builder but got %v", got)
+	}
+
+	// A nil config detaches every managed reference but keeps the others.
+	err = SyncServiceAccountImagePullSecret(ctx, kubeClient, secretName, testNamespace, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	got := getPullSecretNames(ctx, t, kubeClient, "default")
+	if len(got) != 1 || got[0] != "existing" {
+		t.Errorf("Expected only the existing image pull secret on default but got %v", got)
+	}
+	sa := &corev1.ServiceAccount{}
+	if err := kubeClient.Get(ctx, types.NamespacedName{Name: "default", Namespace: testNamespace}, sa); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, ok := sa.Annotations[ImagePullSecretsAnnotation]; ok {
+		t.Errorf("Expected %v annotation to be removed", ImagePullSecretsAnnotation)
+	}
+}
+
+func TestSyncServiceAccountImagePullSecretKeepsUnmanagedReference(t *testing.T) {
+	ctx := context.Background()
+	secretName := "registry-credentials"
+
+	kubeClient := fake.NewClientBuilder().WithObjects(
+		newServiceAccount("default", nil, secretName),
+	).Build()
+
+	config := &onepasswordv1.ImagePullSecretConfig{ServiceAccounts: []string{"default"}}
+	if err := SyncServiceAccountImagePullSecret(ctx, kubeClient, secretName, testNamespace, config); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := SyncServiceAccountImagePullSecret(ctx, kubeClient, secretName, testNamespace, nil); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	got := getPullSecretNames(ctx, t, kubeClient, "default")
+	if len(got) != 1 || got[0] != secretName {
+		t.Errorf("Expected the reference added outside the operator to be kept but got %v", got)
+	}
+}
@@ -0,0 +1,102 @@
+package kubernetessecrets
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	onepasswordv1 "github.com/1Password/onepassword-operator/api/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	kubernetesClient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// TemplateHashAnnotation records a hash of the templates a Secret was rendered from,
+// so a change to a referenced OnePasswordTemplate triggers an update just like a new item version.
+const TemplateHashAnnotation = OnepasswordPrefix + "/template-hash"
+
+var errUnresolvedTemplateRef = errors.New("template reference has not been resolved")
+
+// ResolveSecretTemplate returns the spec with spec.template.ref replaced by the data and partials
+// of the referenced OnePasswordTemplate or ClusterOnePasswordTemplate. Inline data and partials
+// take precedence over the referenced ones. The given spec is not modified.
+//
+// Namespace is the namespace of the referencing resource; cluster-scoped resources pass an empty
+// namespace and can only reference a ClusterOnePasswordTemplate.
+func ResolveSecretTemplate(
+	ctx context.Context,
+	kubeClient kubernetesClient.Client,
+	namespace string,
+	spec *onepasswordv1.OnePasswordItemSpec,
+) (*onepasswordv1.OnePasswordItemSpec, error) {
+	if spec == nil || spec.Template == nil || spec.Template.Ref == nil {
+		return spec, nil
+	}
+	ref := spec.Template.Ref
+
+	var shared onepasswordv1.OnePasswordTemplateSpec
+	switch ref.KindOrDefault() {
+	case onepasswordv1.OnePasswordTemplateKind:
+		if namespace == "" {
+			return nil, fmt.Errorf("template %s: cluster-scoped resources can only reference a %s",
+				ref, onepasswordv1.ClusterOnePasswordTemplateKind)
+		}
+		tmpl := &onepasswordv1.OnePasswordTemplate{}
+		key := types.NamespacedName{Name: ref.Name, Namespace: namespace}
+		if err := kubeClient.Get(ctx, key, tmpl); err != nil {
+			return nil, fmt.Errorf("failed to get template %s: %w", ref, err)
+		}
+		shared = tmpl.Spec
+	case onepasswordv1.ClusterOnePasswordTemplateKind:
+		tmpl := &onepasswordv1.ClusterOnePasswordTemplate{}
+		if err := kubeClient.Get(ctx, types.NamespacedName{Name: ref.Name}, tmpl); err != nil {
+			return nil, fmt.Errorf("failed to get template %s: %w", ref, err)
+		}
+		shared = tmpl.Spec
+	default:
+		return nil, fmt.Errorf("template %s: unsupported kind %q", ref, ref.Kind)
+	}
+
+	resolved := *spec
+	resolved.Template = &onepasswordv1.SecretTemplate{
+		Data:     mergeTemplates(shared.Data, spec.Template.Data),
+		Partials: mergeTemplates(shared.Partials, spec.Template.Partials),
+	}
+	return &resolved, nil
+}
+
+// mergeTemplates returns the shared templates overridden by the inline ones.
+func mergeTemplates(shared, inline map[string]string) map[string]string {
+	if len(shared) == 0 && len(inline) == 0 {
+		return nil
+	}
+	merged := make(map[string]string, len(shared)+len(inline))
+	for name, tmpl := range shared {
+		merged[name] = tmpl
+	}
+	for name, tmpl := range inline {
+		merged[name] = tmpl
+	}
+	return merged
+}
+
+// TemplateHash returns a hash of the data and partials of a resolved template, or an empty string
+// when there is no template.
+func TemplateHash(secretTemplate *onepasswordv1.SecretTemplate) string {
+	if secretTemplate == nil {
+		return ""
+	}
+	// Maps are marshaled with sorted keys, so the hash is stable.
+	encoded, err := json.Marshal(struct {
+		Data     map[string]string `json:"data,omitempty"`
+		Partials map[string]string `json:"partials,omitempty"`
+	}{secretTemplate.Data, secretTemplate.Partials})
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(encoded)
+	return hex.EncodeToString(sum[:])
+}
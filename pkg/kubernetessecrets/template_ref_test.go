@@ -0,0 +1,256 @@
+package kubernetessecrets
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	onepasswordv1 "github.com/1Password/onepassword-operator/api/v1"
+	"github.com/1Password/onepassword-operator/pkg/onepassword/model"
+)
+
+func newTemplateTestClient(t *testing.T) *fake.ClientBuilder {
+	t.Helper()
+	s := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	if err := onepasswordv1.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	return fake.NewClientBuilder().WithScheme(s).WithObjects(
+		&onepasswordv1.OnePasswordTemplate{
+			ObjectMeta: metav1.ObjectMeta{Name: "postgres", Namespace: "default"},
+			Spec: onepasswordv1.OnePasswordTemplateSpec{
+				Data: map[string]string{
+					"DATABASE_URL": `{{ template "dsn" . }}`,
+					"DB_USER":      `{{ .Fields.username }}`,
+				},
+				Partials: map[string]string{
+					"dsn": `postgresql://{{ .Fields.username }}@{{ .Fields.host }}`,
+				},
+			},
+		},
+		&onepasswordv1.ClusterOnePasswordTemplate{
+			ObjectMeta: metav1.ObjectMeta{Name: "dotenv"},
+			Spec: onepasswordv1.OnePasswordTemplateSpec{
+				Data: map[string]string{
+					".env": `USER={{ .Fields.username }}`,
+				},
+			},
+		},
+	)
+}
+
+func TestResolveSecretTemplate(t *testing.T) {
+	kubeClient := newTemplateTestClient(t).Build()
+	spec := &onepasswordv1.OnePasswordItemSpec{
+		ItemPath: "vaults/vault/items/item",
+		Template: &onepasswordv1.SecretTemplate{
+			Ref:  &onepasswordv1.TemplateReference{Name: "postgres"},
+			Data: map[string]string{"DB_USER": `inline-{{ .Fields.username }}`},
+		},
+	}
+
+	resolved, err := ResolveSecretTemplate(context.Background(), kubeClient, "default", spec)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resolved.Template.Ref != nil {
+		t.Errorf("expected the reference to be resolved")
+	}
+	if spec.Template.Ref == nil || len(spec.Template.Data) != 1 {
+		t.Errorf("expected the original spec to be left untouched")
+	}
+
+	item := model.Item{Fields: []model.ItemField{
+		{Label: "username", Value: "app"},
+		{Label: "host", Value: "db"},
+	}}
+	data, err := BuildKubernetesSecretData(item, false, resolved, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := string(data["DATABASE_URL"]); got != "postgresql://app@db" {
+		t.Errorf("expected DATABASE_URL from the shared template and partial, got %q", got)
+	}
+	if got := string(data["DB_USER"]); got != "inline-app" {
+		t.Errorf("expected the inline DB_USER template to win, got %q", got)
+	}
+}
+
+func TestResolveSecretTemplateClusterTemplate(t *testing.T) {
+	kubeClient := newTemplateTestClient(t).Build()
+	spec := &onepasswordv1.OnePasswordItemSpec{
+		Template: &onepasswordv1.SecretTemplate{
+			Ref: &onepasswordv1.TemplateReference{
+				Kind: onepasswordv1.ClusterOnePasswordTemplateKind,
+				Name: "dotenv",
+			},
+		},
+	}
+
+	for _, namespace := range []string{"default", ""} {
+		resolved, err := ResolveSecretTemplate(context.Background(), kubeClient, namespace, spec)
+		if err != nil {
+			t.Fatalf("unexpected error for namespace %q: %v", namespace, err)
+		}
+		if resolved.Template.Data[".env"] == "" {
+			t.Errorf("expected .env from the cluster template for namespace %q", namespace)
+		}
+	}
+}
+
+func TestResolveSecretTemplateErrors(t *testing.T) {
+	kubeClient := newTemplateTestClient(t).Build()
+	tests := map[string]struct {
+		namespace string
+		ref       onepasswordv1.TemplateReference
+		wantErr   string
+	}{
+		"missing template": {
+			namespace: "default",
+			ref:       onepasswordv1.TemplateReference{Name: "missing"},
+			wantErr:   "failed to get template OnePasswordTemplate/missing",
+		},
+		"template in another namespace": {
+			namespace: "other",
+			ref:       onepasswordv1.TemplateReference{Name: "postgres"},
+			wantErr:   "not found",
+		},
+		"namespaced template from cluster resource": {
+			namespace: "",
+			ref:       onepasswordv1.TemplateReference{Name: "postgres"},
+			wantErr:   "can only reference a ClusterOnePasswordTemplate",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			spec := &onepasswordv1.OnePasswordItemSpec{
+				Template: &onepasswordv1.SecretTemplate{Ref: &tc.ref},
+			}
+			_, err := ResolveSecretTemplate(context.Background(), kubeClient, tc.namespace, spec)
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tc.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestBuildKubernetesSecretDataUnresolvedTemplateRef(t *testing.T) {
+	spec := &onepasswordv1.OnePasswordItemSpec{
+		Template: &onepasswordv1.SecretTemplate{
+			Ref: &onepasswordv1.TemplateReference{Name: "postgres"},
+		},
+	}
+	if _, err := BuildKubernetesSecretData(model.Item{}, false, spec, nil); err == nil {
+		t.Errorf("expected an error for an unresolved template reference")
+	}
+}
+
+func TestTemplateHash(t *testing.T) {
+	if hash := TemplateHash(nil); hash != "" {
+		t.Errorf("expected no hash without a template, got %q", hash)
+	}
+
+	a := &onepasswordv1.SecretTemplate{Data: map[string]string{"a": "1", "b": "2"}}
+	b := &onepasswordv1.SecretTemplate{Data: map[string]string{"b": "2", "a": "1"}}
+	if TemplateHash(a) != TemplateHash(b) {
+		t.Errorf("expected the hash to be independent of map order")
+	}
+
+	withPartial := &onepasswordv1.SecretTemplate{
+		Data:     a.Data,
+		Partials: map[string]string{"p": "x"},
+	}
+	if TemplateHash(a) == TemplateHash(withPartial) {
+		t.Errorf("expected partials to change the hash")
+	}
+}
+
+func TestCreateKubernetesSecretFromItemTemplateChange(t *testing.T) {
+	ctx := context.Background()
+	kubeClient := newTemplateTestClient(t).Build()
+	item := &model.Item{
+		ID:      "item",
+		VaultID: "vault",
+		Version: 1,
+		Fields:  []model.ItemField{{Label: "username", Value: "app"}},
+	}
+	create := func(tmpl string) *corev1.Secret {
+		t.Helper()
+		spec := &onepasswordv1.OnePasswordItemSpec{
+			Template: &onepasswordv1.SecretTemplate{Data: map[string]string{"user": tmpl}},
+		}
+		err := CreateKubernetesSecretFromItem(ctx, kubeClient, "app", "default", item, "", nil, nil, "", nil,
+			false, spec, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		secret := &corev1.Secret{}
+		if err := kubeClient.Get(ctx, types.NamespacedName{Name: "app", Namespace: "default"}, secret); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return secret
+	}
+
+	create(`{{ .Fields.username }}`)
+	secret := create(`user={{ .Fields.username }}`)
+	if got := string(secret.Data["user"]); got != "user=app" {
+		t.Errorf("expected the secret to be rendered again after a template change, got %q", got)
+	}
+	if secret.Annotations[TemplateHashAnnotation] == "" {
+		t.Errorf("expected the %s annotation to be set", TemplateHashAnnotation)
+	}
+}
+
+func TestCreateKubernetesSecretFromItemMappingChange(t *testing.T) {
+	ctx := context.Background()
+	kubeClient := newTemplateTestClient(t).Build()
+	item := &model.Item{
+		ID:      "item",
+		VaultID: "vault",
+		Version: 1,
+		Fields:  []model.ItemField{{Label: "api key", Value: "secret"}},
+	}
+	create := func(spec *onepasswordv1.OnePasswordItemSpec) *corev1.Secret {
+		t.Helper()
+		err := CreateKubernetesSecretFromItem(ctx, kubeClient, "app", "default", item, "", nil, nil, "", nil,
+			false, spec, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		secret := &corev1.Secret{}
+		if err := kubeClient.Get(ctx, types.NamespacedName{Name: "app", Namespace: "default"}, secret); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return secret
+	}
+
+	create(&onepasswordv1.OnePasswordItemSpec{})
+	secret := create(&onepasswordv1.OnePasswordItemSpec{
+		KeyTransform: &onepasswordv1.KeyTransform{Case: onepasswordv1.KeyCaseUpperSnake},
+	})
+	if got := string(secret.Data["API_KEY"]); got != "secret" {
+		t.Errorf("expected the secret to be rendered again after a spec change, got %v", secret.Data)
+	}
+	if secret.Annotations[MappingHashAnnotation] == "" {
+		t.Errorf("expected the %s annotation to be set", MappingHashAnnotation)
+	}
+
+	secret = create(&onepasswordv1.OnePasswordItemSpec{})
+	if _, ok := secret.Annotations[MappingHashAnnotation]; ok {
+		t.Errorf("expected the %s annotation to be removed", MappingHashAnnotation)
+	}
+	if got := string(secret.Data["api-key"]); got != "secret" {
+		t.Errorf("expected the default keys again, got %v", secret.Data)
+	}
+}
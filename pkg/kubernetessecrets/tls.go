@@ -0,0 +1,80 @@
+package kubernetessecrets
+
+import (
+	"fmt"
+
+	onepasswordv1 "github.com/1Password/onepassword-operator/api/v1"
+	"github.com/1Password/onepassword-operator/pkg/onepassword/model"
+	"github.com/1Password/onepassword-operator/pkg/template"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// tlsCAKey is the key cert-manager and most ingress controllers read the CA bundle of a TLS Secret from.
+const tlsCAKey = "ca.crt"
+
+// buildTLSSecretData reads the certificate, private key and CA from the item and returns
+// the validated and normalized tls.crt, tls.key and, if configured, ca.crt keys.
+func buildTLSSecretData(item model.Item, config *onepasswordv1.TLSConfig) (map[string][]byte, error) {
+	cert, err := itemValue(item, config.CertificateField, config.CertificateFile)
+	if err != nil {
+		return nil, fmt.Errorf("certificate: %w", err)
+	}
+	if cert == nil {
+		return nil, fmt.Errorf("certificateField or certificateFile is required")
+	}
+
+	key, err := itemValue(item, config.PrivateKeyField, config.PrivateKeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("private key: %w", err)
+	}
+	if key == nil {
+		return nil, fmt.Errorf("privateKeyField or privateKeyFile is required")
+	}
+
+	ca, err := itemValue(item, config.CAField, config.CAFile)
+	if err != nil {
+		return nil, fmt.Errorf("CA: %w", err)
+	}
+
+	bundle, err := template.BuildTLSBundle(cert, key, ca, config.PrivateKeyFormat)
+	if err != nil {
+		return nil, err
+	}
+
+	secretData := map[string][]byte{
+		corev1.TLSCertKey:       bundle.Certificate,
+		corev1.TLSPrivateKeyKey: bundle.PrivateKey,
+	}
+	if bundle.CA != nil {
+		secretData[tlsCAKey] = bundle.CA
+	}
+	return secretData, nil
+}
+
+// itemValue returns the value of the field with the given label or, when fieldLabel is empty,
+// the content of the file with the given name. It returns nil when neither is set.
+func itemValue(item model.Item, fieldLabel, fileName string) ([]byte, error) {
+	if fieldLabel != "" {
+		for _, field := range item.Fields {
+			if field.Label == fieldLabel {
+				return []byte(field.Value), nil
+			}
+		}
+		return nil, fmt.Errorf("field %q not found", fieldLabel)
+	}
+
+	if fileName != "" {
+		for _, file := range item.Files {
+			if file.Name == fileName {
+				content, err := file.Content()
+				if err != nil {
+					return nil, fmt.Errorf("could not load contents of file %q: %w", fileName, err)
+				}
+				return content, nil
+			}
+		}
+		return nil, fmt.Errorf("file %q not found", fileName)
+	}
+
+	return nil, nil
+}
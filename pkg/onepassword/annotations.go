@@ -14,6 +14,11 @@ const (
 	VersionAnnotation             = OnepasswordPrefix + "/item-version"
 	RestartAnnotation             = OnepasswordPrefix + "/last-restarted"
 	AutoRestartWorkloadAnnotation = OnepasswordPrefix + "/auto-restart"
+	// ClusterItemLabel marks a Secret as managed by the ClusterOnePasswordItem named in its value.
+	ClusterItemLabel = OnepasswordPrefix + "/cluster-item"
+	// ConnectionAnnotation selects the OnePasswordConnection, or "ClusterOnePasswordConnection/<name>",
+	// the item of a Deployment or OnePasswordItem is read with. Secrets record the connection they were built with.
+	ConnectionAnnotation = OnepasswordPrefix + "/connection"
 )
 
 func GetAnnotationsForDeployment(deployment *appsv1.Deployment, regex *regexp.Regexp) (map[string]string, bool) {
@@ -2,13 +2,15 @@ package client
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"os"
+	"strings"
 
 	"github.com/go-logr/logr"
 
 	"github.com/1Password/onepassword-operator/pkg/onepassword/client/connect"
-	"github.com/1Password/onepassword-operator/pkg/onepassword/client/sdk"
 	"github.com/1Password/onepassword-operator/pkg/onepassword/model"
 )
 
@@ -23,6 +25,33 @@ type Client interface {
 type Config struct {
 	Logger  logr.Logger
 	Version string
+	// Failover configures the circuit breakers when several backends are set.
+	Failover FailoverConfig
+	// Backend is the name of a registered backend. When empty, the backend is selected from the credentials.
+	Backend string
+}
+
+// ErrNoCredentials is returned when neither Connect nor Service Account credentials are set.
+var ErrNoCredentials = errors.New("invalid configuration. Connect or Service Account credentials should be set")
+
+// Credentials holds the credentials of either 1Password Connect or a Service Account.
+type Credentials struct {
+	ConnectHost         string
+	ConnectToken        string
+	ServiceAccountToken string
+	// ConnectTransport configures how the Connect server is reached.
+	ConnectTransport connect.TransportConfig
+}
+
+// Hash identifies the credentials, so a client is only rebuilt when they change.
+func (c Credentials) Hash() string {
+	transport := c.ConnectTransport
+	sum := sha256.Sum256([]byte(strings.Join([]string{
+		c.ConnectHost, c.ConnectToken, c.ServiceAccountToken,
+		string(transport.CABundle), string(transport.ClientCertificate), string(transport.ClientKey),
+		transport.ProxyURL, transport.Timeout.String(), transport.OperationTimeout.String(), transport.UserAgent,
+	}, "\x00")))
+	return hex.EncodeToString(sum[:])
 }
 
 // NewFromEnvironment creates a new 1Password client based on the provided configuration.
@@ -30,27 +59,42 @@ func NewFromEnvironment(ctx context.Context, cfg Config) (Client, error) {
 	connectHost, _ := os.LookupEnv("OP_CONNECT_HOST")
 	connectToken, _ := os.LookupEnv("OP_CONNECT_TOKEN")
 	serviceAccountToken, _ := os.LookupEnv("OP_SERVICE_ACCOUNT_TOKEN")
-
-	if connectHost != "" && connectToken != "" && serviceAccountToken != "" {
-		return nil, errors.New("invalid configuration. Either Connect or Service Account credentials should be set, not both")
+	if cfg.Backend == "" {
+		cfg.Backend = os.Getenv(BackendEnv)
 	}
 
-	if serviceAccountToken != "" {
-		cfg.Logger.Info("Using Service Account Token")
-		return sdk.NewClient(ctx, sdk.Config{
-			ServiceAccountToken: serviceAccountToken,
-			IntegrationName:     "1password-operator",
-			IntegrationVersion:  cfg.Version,
-		})
-	}
+	return New(ctx, cfg, Credentials{
+		ConnectHost:         connectHost,
+		ConnectToken:        connectToken,
+		ServiceAccountToken: serviceAccountToken,
+	})
+}
 
-	if connectHost != "" && connectToken != "" {
-		cfg.Logger.Info("Using 1Password Connect")
-		return connect.NewClient(connect.Config{
-			ConnectHost:  connectHost,
-			ConnectToken: connectToken,
-		}), nil
+// New creates a new 1Password client for the given credentials, using the backend named by cfg.Backend.
+//
+// Without a backend name, the backend is selected from the credentials. ConnectHost may list several
+// Connect servers separated by commas. When several Connect servers, or both Connect and Service Account
+// credentials, are set, the client fails over between them in that order: the Connect servers first,
+// then the Service Account.
+func New(ctx context.Context, cfg Config, credentials Credentials) (Client, error) {
+	if cfg.Backend != "" {
+		return NewBackend(ctx, cfg.Backend, cfg, credentials)
 	}
 
-	return nil, errors.New("invalid configuration. Connect or Service Account credentials should be set")
+	var backends []Backend
+	if credentials.ConnectHost != "" && credentials.ConnectToken != "" {
+		connectBackends, err := newConnectBackends(cfg, credentials)
+		if err != nil {
+			return nil, err
+		}
+		backends = append(backends, connectBackends...)
+	}
+	if credentials.ServiceAccountToken != "" {
+		sdkClient, err := newServiceAccountBackend(ctx, cfg, credentials)
+		if err != nil {
+			return nil, err
+		}
+		backends = append(backends, Backend{Name: BackendServiceAccount, Client: sdkClient})
+	}
+	return newFailoverBackends(cfg, backends)
 }
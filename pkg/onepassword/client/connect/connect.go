@@ -4,10 +4,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/url"
 	"strings"
 	"time"
 
-	"github.com/1Password/connect-sdk-go/connect"
 	"github.com/1Password/connect-sdk-go/onepassword"
 	"github.com/1Password/onepassword-operator/pkg/onepassword/model"
 )
@@ -16,22 +16,51 @@ import (
 type Config struct {
 	ConnectHost  string
 	ConnectToken string
+	Transport    TransportConfig
 }
 
+// fileRetryDelay is the delay between attempts to download a file Connect has not synchronized yet.
+const fileRetryDelay = 1 * time.Second
+
 // Connect is a client for interacting with 1Password using the Connect API.
 type Connect struct {
-	client connect.Client
+	client api
+	// operationTimeout bounds every operation, including retries. Zero means no timeout.
+	operationTimeout time.Duration
+	// retryDelay overrides fileRetryDelay when set.
+	retryDelay time.Duration
 }
 
 // NewClient creates a new Connect client using provided configuration.
-func NewClient(config Config) *Connect {
+func NewClient(config Config) (*Connect, error) {
+	httpClient, err := config.Transport.HTTPClient()
+	if err != nil {
+		return nil, fmt.Errorf("invalid 1Password Connect transport: %w", err)
+	}
 	return &Connect{
-		client: connect.NewClient(config.ConnectHost, config.ConnectToken),
+		client: &restClient{
+			host:       config.ConnectHost,
+			token:      config.ConnectToken,
+			userAgent:  config.Transport.userAgent(),
+			httpClient: httpClient,
+		},
+		operationTimeout: config.Transport.OperationTimeout,
+	}, nil
+}
+
+// withTimeout bounds the operation by the operation timeout, in addition to the deadline of ctx.
+func (c *Connect) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if c.operationTimeout <= 0 {
+		return ctx, func() {}
 	}
+	return context.WithTimeout(ctx, c.operationTimeout)
 }
 
 func (c *Connect) GetItemByID(ctx context.Context, vaultID, itemID string) (*model.Item, error) {
-	connectItem, err := c.client.GetItemByUUID(itemID, vaultID)
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	connectItem, err := c.client.GetItemByUUID(ctx, itemID, vaultID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to GetItemByID using 1Password Connect: %w", err)
 	}
@@ -42,8 +71,11 @@ func (c *Connect) GetItemByID(ctx context.Context, vaultID, itemID string) (*mod
 }
 
 func (c *Connect) GetItemsByTitle(ctx context.Context, vaultID, itemTitle string) ([]model.Item, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
 	// Get all items in the vault with the specified title
-	connectItems, err := c.client.GetItemsByTitle(itemTitle, vaultID)
+	connectItems, err := c.client.GetItemsByTitle(ctx, itemTitle, vaultID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to GetItemsByTitle using 1Password Connect: %w", err)
 	}
@@ -60,15 +92,22 @@ func (c *Connect) GetItemsByTitle(ctx context.Context, vaultID, itemTitle string
 
 // GetFileContent retrieves the content of a file from a 1Password item.
 // As the Connect has a delay when synchronizing files and returns a 500 error in this case,
-// this function implements a retry mechanism.
+// this function implements a retry mechanism. Waiting between retries stops when ctx is done.
 func (c *Connect) GetFileContent(ctx context.Context, vaultID, itemID, fileID string) ([]byte, error) {
 	const maxRetries = 5
-	const delay = 1 * time.Second
+	delay := fileRetryDelay
+	if c.retryDelay > 0 {
+		delay = c.retryDelay
+	}
+
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
 
 	var lastErr error
 	for i := 0; i < maxRetries; i++ {
-		bytes, err := c.client.GetFileContent(&onepassword.File{
-			ContentPath: fmt.Sprintf("/v1/vaults/%s/items/%s/files/%s/content", vaultID, itemID, fileID),
+		bytes, err := c.client.GetFileContent(ctx, &onepassword.File{
+			ContentPath: fmt.Sprintf("/v1/vaults/%s/items/%s/files/%s/content",
+				url.PathEscape(vaultID), url.PathEscape(itemID), url.PathEscape(fileID)),
 		})
 		if err == nil {
 			return bytes, nil
@@ -77,7 +116,9 @@ func (c *Connect) GetFileContent(ctx context.Context, vaultID, itemID, fileID st
 		var connectErr *onepassword.Error
 		if errors.As(err, &connectErr) && connectErr.StatusCode == 500 {
 			lastErr = err
-			time.Sleep(delay)
+			if err := sleep(ctx, delay); err != nil {
+				return nil, fmt.Errorf("failed to GetFileContent using 1Password Connect: %w, last error: %w", err, lastErr)
+			}
 			continue
 		}
 
@@ -88,7 +129,10 @@ func (c *Connect) GetFileContent(ctx context.Context, vaultID, itemID, fileID st
 }
 
 func (c *Connect) GetVaultsByTitle(ctx context.Context, vaultQuery string) ([]model.Vault, error) {
-	connectVaults, err := c.client.GetVaultsByTitle(vaultQuery)
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	connectVaults, err := c.client.GetVaultsByTitle(ctx, vaultQuery)
 	if err != nil {
 		return nil, fmt.Errorf("failed to GetVaultsByTitle using 1Password Connect: %w", err)
 	}
@@ -103,3 +147,15 @@ func (c *Connect) GetVaultsByTitle(ctx context.Context, vaultQuery string) ([]mo
 	}
 	return vaults, nil
 }
+
+// sleep waits for the delay, or returns the error of ctx when it is done first.
+func sleep(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -26,7 +26,7 @@ func TestConnect_GetItemByID(t *testing.T) {
 		"should return an item": {
 			mockClient: func() *mock.ConnectClientMock {
 				mockConnectClient := &mock.ConnectClientMock{}
-				mockConnectClient.On("GetItemByUUID", "item-id", "vault-id").Return(connectItem, nil)
+				mockConnectClient.On("GetItemByUUID", context.Background(), "item-id", "vault-id").Return(connectItem, nil)
 				return mockConnectClient
 			},
 			check: func(t *testing.T, item *model.Item, err error) {
@@ -37,7 +37,8 @@ func TestConnect_GetItemByID(t *testing.T) {
 		"should return an error": {
 			mockClient: func() *mock.ConnectClientMock {
 				mockConnectClient := &mock.ConnectClientMock{}
-				mockConnectClient.On("GetItemByUUID", "item-id", "vault-id").Return((*onepassword.Item)(nil), errors.New("error"))
+				mockConnectClient.On("GetItemByUUID", context.Background(), "item-id", "vault-id").
+					Return((*onepassword.Item)(nil), errors.New("error"))
 				return mockConnectClient
 			},
 			check: func(t *testing.T, item *model.Item, err error) {
@@ -67,7 +68,7 @@ func TestConnect_GetItemsByTitle(t *testing.T) {
 		"should return a single item": {
 			mockClient: func() *mock.ConnectClientMock {
 				mockConnectClient := &mock.ConnectClientMock{}
-				mockConnectClient.On("GetItemsByTitle", "item-title", "vault-id").Return(
+				mockConnectClient.On("GetItemsByTitle", context.Background(), "item-title", "vault-id").Return(
 					[]onepassword.Item{
 						*connectItem1,
 					}, nil)
@@ -82,7 +83,7 @@ func TestConnect_GetItemsByTitle(t *testing.T) {
 		"should return two items": {
 			mockClient: func() *mock.ConnectClientMock {
 				mockConnectClient := &mock.ConnectClientMock{}
-				mockConnectClient.On("GetItemsByTitle", "item-title", "vault-id").Return(
+				mockConnectClient.On("GetItemsByTitle", context.Background(), "item-title", "vault-id").Return(
 					[]onepassword.Item{
 						*connectItem1,
 						*connectItem2,
@@ -99,7 +100,8 @@ func TestConnect_GetItemsByTitle(t *testing.T) {
 		"should return an error": {
 			mockClient: func() *mock.ConnectClientMock {
 				mockConnectClient := &mock.ConnectClientMock{}
-				mockConnectClient.On("GetItemsByTitle", "item-title", "vault-id").Return([]onepassword.Item{}, errors.New("error"))
+				mockConnectClient.On("GetItemsByTitle", context.Background(), "item-title", "vault-id").
+					Return([]onepassword.Item{}, errors.New("error"))
 				return mockConnectClient
 			},
 			check: func(t *testing.T, items []model.Item, err error) {
@@ -126,7 +128,7 @@ func TestConnect_GetFileContent(t *testing.T) {
 		"should return file content": {
 			mockClient: func() *mock.ConnectClientMock {
 				mockConnectClient := &mock.ConnectClientMock{}
-				mockConnectClient.On("GetFileContent", &onepassword.File{
+				mockConnectClient.On("GetFileContent", context.Background(), &onepassword.File{
 					ContentPath: "/v1/vaults/vault-id/items/item-id/files/file-id/content",
 				}).Return([]byte("file content"), nil)
 				return mockConnectClient
@@ -139,7 +141,7 @@ func TestConnect_GetFileContent(t *testing.T) {
 		"should return an error": {
 			mockClient: func() *mock.ConnectClientMock {
 				mockConnectClient := &mock.ConnectClientMock{}
-				mockConnectClient.On("GetFileContent", &onepassword.File{
+				mockConnectClient.On("GetFileContent", context.Background(), &onepassword.File{
 					ContentPath: "/v1/vaults/vault-id/items/item-id/files/file-id/content",
 				}).Return(nil, errors.New("error"))
 				return mockConnectClient
@@ -169,7 +171,7 @@ func TestConnect_GetVaultsByTitle(t *testing.T) {
 		"should return a single vault": {
 			mockClient: func() *mock.ConnectClientMock {
 				mockConnectClient := &mock.ConnectClientMock{}
-				mockConnectClient.On("GetVaultsByTitle", VaultTitleEmployee).Return([]onepassword.Vault{
+				mockConnectClient.On("GetVaultsByTitle", context.Background(), VaultTitleEmployee).Return([]onepassword.Vault{
 					{
 						ID:        "test-id",
 						Name:      VaultTitleEmployee,
@@ -193,7 +195,7 @@ func TestConnect_GetVaultsByTitle(t *testing.T) {
 		"should return a two vaults": {
 			mockClient: func() *mock.ConnectClientMock {
 				mockConnectClient := &mock.ConnectClientMock{}
-				mockConnectClient.On("GetVaultsByTitle", VaultTitleEmployee).Return([]onepassword.Vault{
+				mockConnectClient.On("GetVaultsByTitle", context.Background(), VaultTitleEmployee).Return([]onepassword.Vault{
 					{
 						ID:        "test-id",
 						Name:      VaultTitleEmployee,
@@ -221,7 +223,8 @@ func TestConnect_GetVaultsByTitle(t *testing.T) {
 		"should return an error": {
 			mockClient: func() *mock.ConnectClientMock {
 				mockConnectClient := &mock.ConnectClientMock{}
-				mockConnectClient.On("GetVaultsByTitle", VaultTitleEmployee).Return([]onepassword.Vault{}, errors.New("error"))
+				mockConnectClient.On("GetVaultsByTitle", context.Background(), VaultTitleEmployee).
+					Return([]onepassword.Vault{}, errors.New("error"))
 				return mockConnectClient
 			},
 			check: func(t *testing.T, vaults []model.Vault, err error) {
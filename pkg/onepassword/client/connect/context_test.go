@@ -0,0 +1,118 @@
+package connect
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// syncingFileServer answers every request with the 500 error Connect returns for files it has not
+// synchronized yet.
+func syncingFileServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(connectVersionHeader, "1.7.3")
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte(`{"status":500,"message":"file not synchronized"}`))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// hangingServer never answers until the test ends.
+func hangingServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	t.Cleanup(server.Close)
+	t.Cleanup(func() { close(release) })
+	return server
+}
+
+func TestConnect_GetFileContentRetryHonorsCancellation(t *testing.T) {
+	client := newTestClient(t, syncingFileServer(t).URL, TransportConfig{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	start := time.Now()
+	_, err := client.GetFileContent(ctx, "vault-id", "item-id", "file-id")
+	require.ErrorIs(t, err, context.Canceled)
+	require.Less(t, time.Since(start), fileRetryDelay, "the retry backoff should stop when the context is cancelled")
+}
+
+func TestConnect_CancelledRequestReturnsPromptly(t *testing.T) {
+	client := newTestClient(t, hangingServer(t).URL, TransportConfig{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	start := time.Now()
+	_, err := client.GetItemByID(ctx, "vault-id", "item-id")
+	require.ErrorIs(t, err, context.Canceled)
+	require.Less(t, time.Since(start), time.Second)
+}
+
+func TestConnect_OperationTimeout(t *testing.T) {
+	testCases := map[string]struct {
+		server func(t *testing.T) *httptest.Server
+		call   func(ctx context.Context, c *Connect) error
+	}{
+		"should bound a request": {
+			server: hangingServer,
+			call: func(ctx context.Context, c *Connect) error {
+				_, err := c.GetVaultsByTitle(ctx, VaultTitleEmployee)
+				return err
+			},
+		},
+		"should bound the retries of a file download": {
+			server: syncingFileServer,
+			call: func(ctx context.Context, c *Connect) error {
+				_, err := c.GetFileContent(ctx, "vault-id", "item-id", "file-id")
+				return err
+			},
+		},
+	}
+
+	for description, tc := range testCases {
+		t.Run(description, func(t *testing.T) {
+			client := newTestClient(t, tc.server(t).URL, TransportConfig{OperationTimeout: 100 * time.Millisecond})
+
+			start := time.Now()
+			err := tc.call(context.Background(), client)
+			require.True(t, errors.Is(err, context.DeadlineExceeded), "unexpected error: %v", err)
+			require.Less(t, time.Since(start), fileRetryDelay)
+		})
+	}
+}
+
+func TestConnect_GetFileContentRetriesUntilSynchronized(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.Header().Set(connectVersionHeader, "1.7.3")
+		if attempts < 3 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		_, _ = w.Write([]byte("file content"))
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server.URL, TransportConfig{})
+	client.retryDelay = time.Millisecond
+	content, err := client.GetFileContent(context.Background(), "vault-id", "item-id", "file-id")
+	require.NoError(t, err)
+	require.Equal(t, []byte("file content"), content)
+	require.Equal(t, 3, attempts)
+}
@@ -0,0 +1,124 @@
+package connect
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	sdkconnect "github.com/1Password/connect-sdk-go/connect"
+	"github.com/1Password/connect-sdk-go/onepassword"
+
+	"github.com/1Password/onepassword-operator/pkg/testhelper/fakeconnect"
+)
+
+func newFakeServer(t *testing.T) (*fakeconnect.Server, onepassword.Vault, onepassword.Item) {
+	t.Helper()
+	server := fakeconnect.NewServer("token")
+	t.Cleanup(server.Close)
+
+	vault := server.AddVault(VaultTitleEmployee)
+	item, err := server.AddItem(vault.ID, onepassword.Item{
+		Title:    "database",
+		Category: onepassword.Database,
+		Fields:   []*onepassword.ItemField{{Label: "password", Value: "secret"}},
+	})
+	require.NoError(t, err)
+	return server, vault, item
+}
+
+func TestFakeServer_Items(t *testing.T) {
+	server, vault, item := newFakeServer(t)
+	client := newTestClient(t, server.URL, TransportConfig{})
+	ctx := context.Background()
+
+	vaults, err := client.GetVaultsByTitle(ctx, VaultTitleEmployee)
+	require.NoError(t, err)
+	require.Len(t, vaults, 1)
+	require.Equal(t, vault.ID, vaults[0].ID)
+
+	items, err := client.GetItemsByTitle(ctx, vault.ID, "database")
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	require.Equal(t, "secret", items[0].Fields[0].Value)
+
+	require.NoError(t, server.EditField(vault.ID, item.ID, "password", "rotated"))
+	edited, err := client.GetItemByID(ctx, vault.ID, item.ID)
+	require.NoError(t, err)
+	require.Equal(t, item.Version+1, edited.Version)
+	require.Equal(t, "rotated", edited.Fields[0].Value)
+
+	require.NoError(t, server.DeleteItem(vault.ID, item.ID))
+	_, err = client.GetItemByID(ctx, vault.ID, item.ID)
+	var connectErr *onepassword.Error
+	require.True(t, errors.As(err, &connectErr))
+	require.Equal(t, http.StatusNotFound, connectErr.StatusCode)
+}
+
+func TestFakeServer_SDKClient(t *testing.T) {
+	server, vault, item := newFakeServer(t)
+	file, err := server.AddFile(vault.ID, item.ID, "ca.crt", []byte("certificate"))
+	require.NoError(t, err)
+
+	sdkClient := sdkconnect.NewClient(server.URL, "token")
+	sdkVault, err := sdkClient.GetVaultByTitle(VaultTitleEmployee)
+	require.NoError(t, err)
+	require.Equal(t, vault.ID, sdkVault.ID)
+
+	sdkItem, err := sdkClient.GetItemByTitle("database", vault.ID)
+	require.NoError(t, err)
+	require.Equal(t, item.ID, sdkItem.ID)
+	require.Len(t, sdkItem.Files, 1)
+
+	content, err := sdkClient.GetFileContent(sdkItem.Files[0])
+	require.NoError(t, err)
+	require.Equal(t, "certificate", string(content))
+	require.Equal(t, file.ID, sdkItem.Files[0].ID)
+
+	_, err = sdkconnect.NewClient(server.URL, "invalid").GetVaultByTitle(VaultTitleEmployee)
+	require.Error(t, err)
+}
+
+func TestFakeServer_Faults(t *testing.T) {
+	server, vault, item := newFakeServer(t)
+	file, err := server.AddFile(vault.ID, item.ID, "ca.crt", []byte("certificate"))
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	t.Run("should retry files Connect has not synchronized", func(t *testing.T) {
+		client := newTestClient(t, server.URL, TransportConfig{})
+		client.retryDelay = time.Millisecond
+		server.FailRequests(2, http.StatusInternalServerError)
+		requests := server.Requests()
+
+		content, err := client.GetFileContent(ctx, vault.ID, item.ID, file.ID)
+		require.NoError(t, err)
+		require.Equal(t, "certificate", string(content))
+		require.Equal(t, requests+3, server.Requests())
+	})
+
+	t.Run("should return rate limits", func(t *testing.T) {
+		client := newTestClient(t, server.URL, TransportConfig{})
+		server.RateLimit(1, time.Second)
+
+		_, err := client.GetItemByID(ctx, vault.ID, item.ID)
+		var connectErr *onepassword.Error
+		require.True(t, errors.As(err, &connectErr))
+		require.Equal(t, http.StatusTooManyRequests, connectErr.StatusCode)
+
+		_, err = client.GetItemByID(ctx, vault.ID, item.ID)
+		require.NoError(t, err)
+	})
+
+	t.Run("should time out on a slow server", func(t *testing.T) {
+		client := newTestClient(t, server.URL, TransportConfig{OperationTimeout: 20 * time.Millisecond})
+		server.SetLatency(time.Second)
+		defer server.ClearFaults()
+
+		_, err := client.GetItemByID(ctx, vault.ID, item.ID)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
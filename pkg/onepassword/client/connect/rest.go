@@ -0,0 +1,224 @@
+package connect
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/1Password/connect-sdk-go/onepassword"
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
+)
+
+const connectVersionHeader = "1Password-Connect-Version"
+
+// fileContentVersion is the first Connect version serving the content of files.
+var fileContentVersion = connectVersion{major: 1, minor: 3}
+
+// api is the part of the Connect API used by the operator. It matches the methods of the connect-sdk-go
+// client, whose HTTP client can't be configured and whose requests can't be cancelled.
+type api interface {
+	GetItemByUUID(ctx context.Context, uuid string, vaultQuery string) (*onepassword.Item, error)
+	GetItemsByTitle(ctx context.Context, title string, vaultQuery string) ([]onepassword.Item, error)
+	GetFileContent(ctx context.Context, file *onepassword.File) ([]byte, error)
+	GetVaultsByTitle(ctx context.Context, title string) ([]onepassword.Vault, error)
+}
+
+// restClient calls the Connect API with a configurable HTTP client. Requests, tracing spans and errors are
+// the same as the ones of the connect-sdk-go client, so errors are returned as *onepassword.Error.
+type restClient struct {
+	host       string
+	token      string
+	userAgent  string
+	httpClient *http.Client
+}
+
+var _ api = (*restClient)(nil)
+
+func (c *restClient) GetVaultsByTitle(ctx context.Context, title string) ([]onepassword.Vault, error) {
+	var vaults []onepassword.Vault
+	if err := c.get(ctx, "GetVaultsByTitle", "/v1/vaults?filter="+titleFilter(title), &vaults); err != nil {
+		return nil, err
+	}
+	return vaults, nil
+}
+
+func (c *restClient) GetItemByUUID(ctx context.Context, uuid string, vaultUUID string) (*onepassword.Item, error) {
+	var item onepassword.Item
+	path := fmt.Sprintf("/v1/vaults/%s/items/%s", url.PathEscape(vaultUUID), url.PathEscape(uuid))
+	if err := c.get(ctx, "GetItemByUUID", path, &item); err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// GetItemsByTitle returns the full items with the title. The list endpoint only returns item summaries,
+// so each item is fetched by its UUID.
+func (c *restClient) GetItemsByTitle(
+	ctx context.Context,
+	title string,
+	vaultUUID string,
+) ([]onepassword.Item, error) {
+	var summaries []onepassword.Item
+	path := fmt.Sprintf("/v1/vaults/%s/items?filter=%s", url.PathEscape(vaultUUID), titleFilter(title))
+	if err := c.get(ctx, "GetItemsByTitle", path, &summaries); err != nil {
+		return nil, err
+	}
+
+	items := make([]onepassword.Item, len(summaries))
+	for i, summary := range summaries {
+		item, err := c.GetItemByUUID(ctx, summary.ID, summary.Vault.ID)
+		if err != nil {
+			return nil, err
+		}
+		items[i] = *item
+	}
+	return items, nil
+}
+
+// GetFileContent downloads the content of the file. Connect serves it from version 1.3.0, older servers
+// are rejected with an error asking to update them.
+func (c *restClient) GetFileContent(ctx context.Context, file *onepassword.File) ([]byte, error) {
+	if content, err := file.Content(); err == nil {
+		return content, nil
+	}
+	content, err := c.do(ctx, "GetFileContent", file.ContentPath, fileContentVersion)
+	if err != nil {
+		return nil, err
+	}
+	file.SetContent(content)
+	return content, nil
+}
+
+func (c *restClient) get(ctx context.Context, operation, path string, result interface{}) error {
+	body, err := c.do(ctx, operation, path, connectVersion{})
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(body, result); err != nil {
+		return fmt.Errorf("decoding response: %w", err)
+	}
+	return nil
+}
+
+// do sends a GET request traced as operation and returns the body of a 200 response. The request is
+// cancelled with ctx. A non-zero minimumVersion rejects the responses of older Connect servers.
+func (c *restClient) do(ctx context.Context, operation, path string, minimumVersion connectVersion) ([]byte, error) {
+	tracer := opentracing.GlobalTracer()
+	span := tracer.StartSpan(operation)
+	defer span.Finish()
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(c.host, "/")+path, http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Authorization", "Bearer "+c.token)
+	request.Header.Set("User-Agent", c.userAgent)
+
+	ext.SpanKindRPCClient.Set(span)
+	ext.HTTPUrl.Set(span, path)
+	ext.HTTPMethod.Set(span, http.MethodGet)
+	if err := tracer.Inject(span.Context(), opentracing.HTTPHeaders,
+		opentracing.HTTPHeadersCarrier(request.Header)); err != nil {
+		return nil, fmt.Errorf("injecting tracing headers: %w", err)
+	}
+
+	response, err := c.httpClient.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close() //nolint:errcheck
+
+	if minimumVersion != (connectVersion{}) {
+		if err := expectConnectVersion(response, minimumVersion); err != nil {
+			return nil, err
+		}
+	}
+
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, err
+	}
+	if response.StatusCode != http.StatusOK {
+		return nil, responseError(response.StatusCode, body)
+	}
+	return body, nil
+}
+
+func responseError(statusCode int, body []byte) error {
+	connectErr := &onepassword.Error{}
+	if json.Valid(body) {
+		if err := json.Unmarshal(body, connectErr); err != nil {
+			return fmt.Errorf("decoding error response: %w", err)
+		}
+	}
+	if connectErr.StatusCode == 0 {
+		connectErr.StatusCode = statusCode
+	}
+	if connectErr.Message == "" {
+		connectErr.Message = http.StatusText(statusCode)
+	}
+	return connectErr
+}
+
+func titleFilter(title string) string {
+	return url.QueryEscape(fmt.Sprintf("title eq \"%s\"", title))
+}
+
+// connectVersion is the version of a Connect server, as sent in the 1Password-Connect-Version header.
+type connectVersion struct {
+	major, minor, patch int
+}
+
+func (v connectVersion) String() string {
+	return fmt.Sprintf("%d.%d.%d", v.major, v.minor, v.patch)
+}
+
+func (v connectVersion) before(other connectVersion) bool {
+	if v.major != other.major {
+		return v.major < other.major
+	}
+	if v.minor != other.minor {
+		return v.minor < other.minor
+	}
+	return v.patch < other.patch
+}
+
+func parseConnectVersion(header string) (connectVersion, bool) {
+	parts := strings.Split(strings.TrimSpace(header), ".")
+	if len(parts) != 3 {
+		return connectVersion{}, false
+	}
+	var numbers [3]int
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return connectVersion{}, false
+		}
+		numbers[i] = n
+	}
+	return connectVersion{major: numbers[0], minor: numbers[1], patch: numbers[2]}, true
+}
+
+// expectConnectVersion returns an error if the response comes from a Connect server older than minimum.
+// Like connect-sdk-go, a missing header means Connect 1.2.0 or earlier, which didn't send it, and an
+// unparsable header is accepted.
+func expectConnectVersion(response *http.Response, minimum connectVersion) error {
+	header := response.Header.Get(connectVersionHeader)
+	detected := "1.2.0 (or earlier)"
+	if header != "" {
+		version, ok := parseConnectVersion(header)
+		if !ok || !version.before(minimum) {
+			return nil
+		}
+		detected = header
+	}
+	return fmt.Errorf("need at least version %s of 1Password Connect for this operation, detected version %s: "+
+		"please update the Connect server", minimum, detected)
+}
@@ -0,0 +1,114 @@
+package connect
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
+	"github.com/opentracing/opentracing-go/mocktracer"
+	"github.com/stretchr/testify/require"
+
+	"github.com/1Password/connect-sdk-go/onepassword"
+)
+
+func TestRESTClient_EscapesPathSegments(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.EscapedPath())
+		w.Header().Set(connectVersionHeader, "1.7.3")
+		if r.URL.Query().Has("filter") {
+			require.NoError(t, json.NewEncoder(w).Encode([]onepassword.Item{}))
+			return
+		}
+		require.NoError(t, json.NewEncoder(w).Encode(onepassword.Item{ID: "item-id"}))
+	}))
+	defer server.Close()
+	client := newTestClient(t, server.URL, TransportConfig{})
+	ctx := context.Background()
+
+	_, err := client.GetItemByID(ctx, "vault/..", "item?id#1")
+	require.NoError(t, err)
+	_, err = client.GetItemsByTitle(ctx, "vault/..", "database")
+	require.NoError(t, err)
+	_, err = client.GetFileContent(ctx, "vault/..", "item?id", "../file")
+	require.NoError(t, err)
+
+	require.Equal(t, []string{
+		"/v1/vaults/vault%2F../items/item%3Fid%231",
+		"/v1/vaults/vault%2F../items",
+		"/v1/vaults/vault%2F../items/item%3Fid/files/..%2Ffile/content",
+	}, paths)
+}
+
+func TestRESTClient_FileContentVersion(t *testing.T) {
+	testCases := map[string]struct {
+		header string
+		err    string
+	}{
+		"supported version": {
+			header: "1.7.3",
+		},
+		"first supported version": {
+			header: "1.3.0",
+		},
+		"old version": {
+			header: "1.2.9",
+			err: "need at least version 1.3.0 of 1Password Connect for this operation, " +
+				"detected version 1.2.9: please update the Connect server",
+		},
+		"missing version": {
+			err: "need at least version 1.3.0 of 1Password Connect for this operation, " +
+				"detected version 1.2.0 (or earlier): please update the Connect server",
+		},
+		"unparsable version": {
+			header: "dev",
+		},
+	}
+
+	for description, tc := range testCases {
+		t.Run(description, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tc.header != "" {
+					w.Header().Set(connectVersionHeader, tc.header)
+				}
+				_, err := w.Write([]byte("certificate"))
+				require.NoError(t, err)
+			}))
+			defer server.Close()
+
+			content, err := newTestClient(t, server.URL, TransportConfig{}).
+				GetFileContent(context.Background(), "vault-id", "item-id", "file-id")
+			if tc.err != "" {
+				require.ErrorContains(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, "certificate", string(content))
+		})
+	}
+}
+
+func TestRESTClient_TracesRequests(t *testing.T) {
+	tracer := mocktracer.New()
+	previous := opentracing.GlobalTracer()
+	opentracing.SetGlobalTracer(tracer)
+	defer opentracing.SetGlobalTracer(previous)
+
+	var last *http.Request
+	server := httptest.NewServer(vaultsHandler(t, &last))
+	defer server.Close()
+
+	_, err := newTestClient(t, server.URL, TransportConfig{}).GetVaultsByTitle(context.Background(), "Employee")
+	require.NoError(t, err)
+
+	spans := tracer.FinishedSpans()
+	require.Len(t, spans, 1)
+	require.Equal(t, "GetVaultsByTitle", spans[0].OperationName)
+	require.Equal(t, http.MethodGet, spans[0].Tag("http.method"))
+	require.Equal(t, ext.SpanKindRPCClientEnum, spans[0].Tag("span.kind"))
+	require.NotEmpty(t, last.Header.Get("Mockpfx-Ids-Traceid"), "the span should be propagated to Connect")
+}
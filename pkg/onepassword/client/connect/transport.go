@@ -0,0 +1,83 @@
+package connect
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/1Password/connect-sdk-go/connect"
+)
+
+// DefaultUserAgent is the user agent sent when TransportConfig.UserAgent is empty.
+const DefaultUserAgent = "connect-sdk-go/" + connect.SDKVersion
+
+// TransportConfig configures how the Connect server is reached.
+// The zero value uses the system roots, the proxy of the environment and no timeout.
+type TransportConfig struct {
+	// CABundle holds PEM encoded certificates trusted in addition to the system roots.
+	CABundle []byte
+	// ClientCertificate and ClientKey hold the PEM encoded certificate and key used for mutual TLS.
+	ClientCertificate []byte
+	ClientKey         []byte
+	// ProxyURL is the proxy the requests are sent through. HTTPS_PROXY and NO_PROXY are used when empty.
+	ProxyURL string
+	// Timeout bounds every request, including reading the response body.
+	Timeout time.Duration
+	// OperationTimeout bounds every operation of the client, e.g. all the requests and retries of a file
+	// download. It is not used by HTTPClient.
+	OperationTimeout time.Duration
+	// UserAgent identifies the operator to the Connect server.
+	UserAgent string
+}
+
+// HTTPClient builds the HTTP client of the configuration.
+func (t TransportConfig) HTTPClient() (*http.Client, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if len(t.CABundle) > 0 {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(t.CABundle) {
+			return nil, errors.New("CA bundle contains no PEM encoded certificates")
+		}
+		tlsConfig.RootCAs = pool
+	}
+	switch {
+	case len(t.ClientCertificate) > 0 && len(t.ClientKey) > 0:
+		certificate, err := tls.X509KeyPair(t.ClientCertificate, t.ClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("invalid client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{certificate}
+	case len(t.ClientCertificate) > 0 || len(t.ClientKey) > 0:
+		return nil, errors.New("both the client certificate and the client key are required")
+	}
+	transport.TLSClientConfig = tlsConfig
+
+	if t.ProxyURL != "" {
+		proxyURL, err := url.Parse(t.ProxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy URL: %w", err)
+		}
+		if proxyURL.Scheme == "" || proxyURL.Host == "" {
+			return nil, fmt.Errorf("invalid proxy URL %q: scheme and host are required", t.ProxyURL)
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	return &http.Client{Transport: transport, Timeout: t.Timeout}, nil
+}
+
+func (t TransportConfig) userAgent() string {
+	if t.UserAgent == "" {
+		return DefaultUserAgent
+	}
+	return t.UserAgent
+}
@@ -0,0 +1,218 @@
+package connect
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/1Password/connect-sdk-go/onepassword"
+)
+
+// vaultsHandler serves a vault for every request and records the last request.
+func vaultsHandler(t *testing.T, last **http.Request) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		*last = r
+		w.Header().Set("Content-Type", "application/json")
+		require.NoError(t, json.NewEncoder(w).Encode([]onepassword.Vault{{ID: "vault-id", Name: VaultTitleEmployee}}))
+	}
+}
+
+func serverCABundle(server *httptest.Server) []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+}
+
+func newTestClient(t *testing.T, host string, transport TransportConfig) *Connect {
+	t.Helper()
+	client, err := NewClient(Config{ConnectHost: host, ConnectToken: "token", Transport: transport})
+	require.NoError(t, err)
+	return client
+}
+
+func TestConnect_TLS(t *testing.T) {
+	var last *http.Request
+	server := httptest.NewTLSServer(vaultsHandler(t, &last))
+	defer server.Close()
+
+	t.Run("should reject an untrusted server", func(t *testing.T) {
+		_, err := newTestClient(t, server.URL, TransportConfig{}).GetVaultsByTitle(context.Background(), "Employee")
+		require.Error(t, err)
+	})
+
+	t.Run("should trust the CA bundle", func(t *testing.T) {
+		client := newTestClient(t, server.URL, TransportConfig{CABundle: serverCABundle(server), UserAgent: "operator/test"})
+		vaults, err := client.GetVaultsByTitle(context.Background(), "Employee")
+		require.NoError(t, err)
+		require.Len(t, vaults, 1)
+		require.Equal(t, "vault-id", vaults[0].ID)
+
+		require.Equal(t, "/v1/vaults", last.URL.Path)
+		require.Equal(t, `title eq "Employee"`, last.URL.Query().Get("filter"))
+		require.Equal(t, "Bearer token", last.Header.Get("Authorization"))
+		require.Equal(t, "operator/test", last.Header.Get("User-Agent"))
+	})
+
+	t.Run("should send the default user agent", func(t *testing.T) {
+		client := newTestClient(t, server.URL, TransportConfig{CABundle: serverCABundle(server)})
+		_, err := client.GetVaultsByTitle(context.Background(), "Employee")
+		require.NoError(t, err)
+		require.Equal(t, DefaultUserAgent, last.Header.Get("User-Agent"))
+	})
+}
+
+func TestConnect_MutualTLS(t *testing.T) {
+	clientCert, clientKey := generateCertificate(t, "onepassword-operator")
+	certPool := x509.NewCertPool()
+	require.True(t, certPool.AppendCertsFromPEM(clientCert))
+
+	var last *http.Request
+	server := httptest.NewUnstartedServer(vaultsHandler(t, &last))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: certPool}
+	server.StartTLS()
+	defer server.Close()
+
+	_, err := newTestClient(t, server.URL, TransportConfig{CABundle: serverCABundle(server)}).
+		GetVaultsByTitle(context.Background(), "Employee")
+	require.Error(t, err, "the server should require a client certificate")
+
+	client := newTestClient(t, server.URL, TransportConfig{
+		CABundle:          serverCABundle(server),
+		ClientCertificate: clientCert,
+		ClientKey:         clientKey,
+	})
+	_, err = client.GetVaultsByTitle(context.Background(), "Employee")
+	require.NoError(t, err)
+	require.Equal(t, "onepassword-operator", last.TLS.PeerCertificates[0].Subject.CommonName)
+}
+
+func TestConnect_Proxy(t *testing.T) {
+	var last *http.Request
+	proxy := httptest.NewServer(vaultsHandler(t, &last))
+	defer proxy.Close()
+
+	client := newTestClient(t, "http://connect.example.com:8080", TransportConfig{ProxyURL: proxy.URL})
+	_, err := client.GetVaultsByTitle(context.Background(), "Employee")
+	require.NoError(t, err)
+	// A proxy receives the absolute URL of the Connect server.
+	require.Equal(t, "connect.example.com:8080", last.Host)
+	require.Equal(t, "/v1/vaults", last.URL.Path)
+}
+
+func TestConnect_Timeout(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	client := newTestClient(t, server.URL, TransportConfig{
+		CABundle: serverCABundle(server),
+		Timeout:  50 * time.Millisecond,
+	})
+	start := time.Now()
+	_, err := client.GetItemByID(context.Background(), "vault-id", "item-id")
+	require.Error(t, err)
+	require.Less(t, time.Since(start), 5*time.Second)
+}
+
+func TestConnect_ErrorResponse(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"status":404,"message":"item not found"}`))
+	}))
+	defer server.Close()
+
+	_, err := newTestClient(t, server.URL, TransportConfig{CABundle: serverCABundle(server)}).
+		GetItemByID(context.Background(), "vault-id", "item-id")
+	var connectErr *onepassword.Error
+	require.True(t, errors.As(err, &connectErr))
+	require.Equal(t, http.StatusNotFound, connectErr.StatusCode)
+	require.Equal(t, "item not found", connectErr.Message)
+}
+
+func TestTransportConfig_HTTPClient(t *testing.T) {
+	clientCert, clientKey := generateCertificate(t, "onepassword-operator")
+
+	testCases := map[string]TransportConfig{
+		"should reject an invalid CA bundle":      {CABundle: []byte("not a certificate")},
+		"should reject a certificate without key": {ClientCertificate: clientCert},
+		"should reject a key without certificate": {ClientKey: clientKey},
+		"should reject a mismatched key pair":     {ClientCertificate: clientCert, ClientKey: []byte("invalid")},
+		"should reject a proxy without scheme":    {ProxyURL: "proxy:3128"},
+	}
+	for description, transport := range testCases {
+		t.Run(description, func(t *testing.T) {
+			_, err := transport.HTTPClient()
+			require.Error(t, err)
+		})
+	}
+
+	httpClient, err := TransportConfig{Timeout: time.Second}.HTTPClient()
+	require.NoError(t, err)
+	require.Equal(t, time.Second, httpClient.Timeout)
+}
+
+// generateCertificate returns a PEM encoded self-signed certificate and its key.
+func generateCertificate(t *testing.T, commonName string) ([]byte, []byte) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: commonName},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+func TestConnect_GetItemsByTitleFetchesFullItems(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var response interface{}
+		switch r.URL.Path {
+		case "/v1/vaults/vault-id/items":
+			response = []onepassword.Item{{ID: "item-id", Vault: onepassword.ItemVault{ID: "vault-id"}}}
+		case "/v1/vaults/vault-id/items/item-id":
+			response = onepassword.Item{
+				ID:     "item-id",
+				Vault:  onepassword.ItemVault{ID: "vault-id"},
+				Fields: []*onepassword.ItemField{{Label: "password", Value: "secret"}},
+			}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		require.NoError(t, json.NewEncoder(w).Encode(response))
+	}))
+	defer server.Close()
+
+	items, err := newTestClient(t, server.URL, TransportConfig{CABundle: serverCABundle(server)}).
+		GetItemsByTitle(context.Background(), "vault-id", "item-title")
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	require.Len(t, items[0].Fields, 1)
+	require.Equal(t, "secret", items[0].Fields[0].Value)
+}
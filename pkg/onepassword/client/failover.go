@@ -0,0 +1,260 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+
+	"github.com/1Password/connect-sdk-go/onepassword"
+
+	"github.com/1Password/onepassword-operator/pkg/onepassword/client/file"
+	"github.com/1Password/onepassword-operator/pkg/onepassword/client/sdk"
+	"github.com/1Password/onepassword-operator/pkg/onepassword/model"
+)
+
+// Defaults of the circuit breaker of every backend of a FailoverClient.
+const (
+	DefaultFailureThreshold = 3
+	DefaultOpenDuration     = 30 * time.Second
+)
+
+// Backend is a named client a FailoverClient routes calls to.
+type Backend struct {
+	// Name identifies the backend in logs and metrics, e.g. "connect:http://onepassword-connect:8080".
+	Name   string
+	Client Client
+}
+
+// FailoverConfig configures the circuit breaker of every backend. Zero values use the defaults.
+type FailoverConfig struct {
+	// FailureThreshold is the number of consecutive failures after which a backend is skipped.
+	FailureThreshold int
+	// OpenDuration is how long a backend is skipped before it is tried again.
+	OpenDuration time.Duration
+}
+
+// FailoverClient is a Client routing every call to the first healthy backend of an ordered list.
+// A backend that fails FailureThreshold consecutive calls is skipped for OpenDuration, after which a
+// single call at a time tries it again while the others keep skipping it: a success makes it healthy, a
+// failure skips it again. When every backend is skipped, all of them are tried, so a call never fails
+// without reaching a backend.
+//
+// Errors about the request rather than the backend, such as a missing item, are returned without
+// trying the next backend.
+type FailoverClient struct {
+	logger   logr.Logger
+	backends []*failoverBackend
+}
+
+type failoverBackend struct {
+	Backend
+	breaker *circuitBreaker
+}
+
+var _ Client = (*FailoverClient)(nil)
+
+// NewFailoverClient creates a FailoverClient routing calls to the backends in order.
+func NewFailoverClient(logger logr.Logger, cfg FailoverConfig, backends ...Backend) *FailoverClient {
+	if cfg.FailureThreshold <= 0 {
+		cfg.FailureThreshold = DefaultFailureThreshold
+	}
+	if cfg.OpenDuration <= 0 {
+		cfg.OpenDuration = DefaultOpenDuration
+	}
+	c := &FailoverClient{logger: logger}
+	for _, backend := range backends {
+		c.backends = append(c.backends, &failoverBackend{
+			Backend: backend,
+			breaker: &circuitBreaker{threshold: cfg.FailureThreshold, openDuration: cfg.OpenDuration, now: time.Now},
+		})
+		backendCircuitOpen.WithLabelValues(backend.Name).Set(0)
+	}
+	return c
+}
+
+func (c *FailoverClient) GetItemByID(ctx context.Context, vaultID, itemID string) (*model.Item, error) {
+	return failover(ctx, c, "GetItemByID", func(client Client) (*model.Item, error) {
+		return client.GetItemByID(ctx, vaultID, itemID)
+	})
+}
+
+func (c *FailoverClient) GetItemsByTitle(ctx context.Context, vaultID, itemTitle string) ([]model.Item, error) {
+	return failover(ctx, c, "GetItemsByTitle", func(client Client) ([]model.Item, error) {
+		return client.GetItemsByTitle(ctx, vaultID, itemTitle)
+	})
+}
+
+func (c *FailoverClient) GetFileContent(ctx context.Context, vaultID, itemID, fileID string) ([]byte, error) {
+	return failover(ctx, c, "GetFileContent", func(client Client) ([]byte, error) {
+		return client.GetFileContent(ctx, vaultID, itemID, fileID)
+	})
+}
+
+func (c *FailoverClient) GetVaultsByTitle(ctx context.Context, title string) ([]model.Vault, error) {
+	return failover(ctx, c, "GetVaultsByTitle", func(client Client) ([]model.Vault, error) {
+		return client.GetVaultsByTitle(ctx, title)
+	})
+}
+
+// failover calls the backends in order until one of them serves the call.
+func failover[T any](
+	ctx context.Context,
+	c *FailoverClient,
+	operation string,
+	call func(client Client) (T, error),
+) (T, error) {
+	var errs []error
+	skipped := 0
+	for _, backend := range c.backends {
+		if !backend.breaker.allow() {
+			skipped++
+			continue
+		}
+		result, done, err := callBackend(ctx, c, backend, operation, call)
+		if done {
+			return result, err
+		}
+		errs = append(errs, err)
+	}
+	if skipped == len(c.backends) {
+		// Every backend is skipped: try all of them rather than failing without reaching one.
+		for _, backend := range c.backends {
+			result, done, err := callBackend(ctx, c, backend, operation, call)
+			if done {
+				return result, err
+			}
+			errs = append(errs, err)
+		}
+	}
+	var zero T
+	return zero, fmt.Errorf("all 1Password backends failed: %w", errors.Join(errs...))
+}
+
+// callBackend calls the backend and records the result. It reports whether the call is done, because the
+// backend served it or the next backend can't do better. Otherwise the error names the failing backend.
+func callBackend[T any](
+	ctx context.Context,
+	c *FailoverClient,
+	backend *failoverBackend,
+	operation string,
+	call func(client Client) (T, error),
+) (T, bool, error) {
+	var zero T
+	result, err := call(backend.Client)
+	switch {
+	case err == nil:
+		c.recordResult(backend, operation, backendResultSuccess)
+		return result, true, nil
+	case ctx.Err() != nil:
+		// The caller gave up, which says nothing about the health of the backend.
+		backend.breaker.release()
+		return zero, true, err
+	case !isBackendFailure(err):
+		c.recordResult(backend, operation, backendResultRequestError)
+		return zero, true, err
+	}
+	c.recordResult(backend, operation, backendResultFailure)
+	return zero, false, fmt.Errorf("%s: %w", backend.Name, err)
+}
+
+func (c *FailoverClient) recordResult(backend *failoverBackend, operation, result string) {
+	backendCalls.WithLabelValues(backend.Name, operation, result).Inc()
+	if result == backendResultFailure {
+		if backend.breaker.failure() {
+			c.logger.Info("1Password backend is failing, routing calls to the next backend",
+				"backend", backend.Name)
+			backendCircuitOpen.WithLabelValues(backend.Name).Set(1)
+		}
+		return
+	}
+	if backend.breaker.success() {
+		c.logger.Info("1Password backend recovered", "backend", backend.Name)
+		backendCircuitOpen.WithLabelValues(backend.Name).Set(0)
+	}
+}
+
+// isBackendFailure reports whether the error means the backend could not serve the call, rather than
+// the call being invalid, e.g. for an item that does not exist.
+func isBackendFailure(err error) bool {
+	if errors.Is(err, file.ErrNotFound) || errors.Is(err, sdk.ErrRequest) {
+		return false
+	}
+	var connectErr *onepassword.Error
+	if errors.As(err, &connectErr) {
+		return connectErr.StatusCode != http.StatusBadRequest && connectErr.StatusCode != http.StatusNotFound
+	}
+	return true
+}
+
+// isRateLimited reports whether the backend rejected the call because its rate limit was exceeded.
+func isRateLimited(err error) bool {
+	var connectErr *onepassword.Error
+	if errors.As(err, &connectErr) {
+		return connectErr.StatusCode == http.StatusTooManyRequests
+	}
+	return sdk.IsRateLimited(err)
+}
+
+// circuitBreaker tracks the consecutive failures of a backend.
+type circuitBreaker struct {
+	threshold    int
+	openDuration time.Duration
+	now          func() time.Time
+
+	mu        sync.Mutex
+	failures  int
+	openUntil time.Time
+	// probing is set while a call tries the backend again after its circuit opened.
+	probing bool
+}
+
+// allow reports whether the backend should be tried. A backend whose circuit is open is tried again
+// once openDuration has passed, by one call until its result is recorded or the probe is released.
+func (b *circuitBreaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.failures < b.threshold {
+		return true
+	}
+	if b.probing || b.now().Before(b.openUntil) {
+		return false
+	}
+	b.probing = true
+	return true
+}
+
+// release ends the probe of a call that was abandoned before its result was known.
+func (b *circuitBreaker) release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+}
+
+// failure records a failed call and reports whether it opened the circuit.
+func (b *circuitBreaker) failure() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	wasOpen := b.failures >= b.threshold
+	b.probing = false
+	b.failures++
+	if b.failures < b.threshold {
+		return false
+	}
+	b.openUntil = b.now().Add(b.openDuration)
+	return !wasOpen
+}
+
+// success records a successful call and reports whether it closed the circuit.
+func (b *circuitBreaker) success() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	wasOpen := b.failures >= b.threshold
+	b.probing = false
+	b.failures = 0
+	return wasOpen
+}
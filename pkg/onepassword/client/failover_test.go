@@ -0,0 +1,264 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+
+	"github.com/1Password/connect-sdk-go/onepassword"
+
+	"github.com/1Password/onepassword-operator/pkg/onepassword/client/connect"
+	"github.com/1Password/onepassword-operator/pkg/onepassword/model"
+)
+
+// stubBackend is a Client returning items titled with its name, or err when set. When block is set,
+// calls signal started and wait for block to be closed.
+type stubBackend struct {
+	name    string
+	err     error
+	calls   int
+	block   chan struct{}
+	started chan struct{}
+}
+
+func (b *stubBackend) GetItemByID(context.Context, string, string) (*model.Item, error) {
+	b.calls++
+	if b.block != nil {
+		b.started <- struct{}{}
+		<-b.block
+	}
+	if b.err != nil {
+		return nil, b.err
+	}
+	return &model.Item{Title: b.name}, nil
+}
+
+func (b *stubBackend) GetItemsByTitle(context.Context, string, string) ([]model.Item, error) {
+	return nil, nil
+}
+
+func (b *stubBackend) GetFileContent(context.Context, string, string, string) ([]byte, error) {
+	return nil, nil
+}
+
+func (b *stubBackend) GetVaultsByTitle(context.Context, string) ([]model.Vault, error) {
+	return nil, nil
+}
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func newTestFailoverClient(clock *fakeClock, backends ...*stubBackend) *FailoverClient {
+	var named []Backend
+	for _, backend := range backends {
+		named = append(named, Backend{Name: backend.name, Client: backend})
+	}
+	c := NewFailoverClient(logr.Discard(), FailoverConfig{FailureThreshold: 2, OpenDuration: time.Minute}, named...)
+	for _, backend := range c.backends {
+		backend.breaker.now = clock.Now
+	}
+	return c
+}
+
+func servedBy(t *testing.T, c *FailoverClient) string {
+	t.Helper()
+	item, err := c.GetItemByID(context.Background(), "vault", "item")
+	require.NoError(t, err)
+	return item.Title
+}
+
+func TestFailoverClient_RoutesToFirstHealthyBackend(t *testing.T) {
+	primary := &stubBackend{name: "test-routes-primary"}
+	secondary := &stubBackend{name: "test-routes-secondary"}
+	c := newTestFailoverClient(&fakeClock{now: time.Now()}, primary, secondary)
+
+	require.Equal(t, "test-routes-primary", servedBy(t, c))
+	require.Equal(t, 0, secondary.calls)
+
+	primary.err = errors.New("connection refused")
+	require.Equal(t, "test-routes-secondary", servedBy(t, c))
+
+	require.Equal(t, 1.0, testutil.ToFloat64(
+		backendCalls.WithLabelValues("test-routes-primary", "GetItemByID", backendResultSuccess)))
+	require.Equal(t, 1.0, testutil.ToFloat64(
+		backendCalls.WithLabelValues("test-routes-primary", "GetItemByID", backendResultFailure)))
+	require.Equal(t, 1.0, testutil.ToFloat64(
+		backendCalls.WithLabelValues("test-routes-secondary", "GetItemByID", backendResultSuccess)))
+}
+
+func TestFailoverClient_CircuitBreaker(t *testing.T) {
+	clock := &fakeClock{now: time.Now()}
+	primary := &stubBackend{name: "test-breaker-primary", err: errors.New("connection refused")}
+	secondary := &stubBackend{name: "test-breaker-secondary"}
+	c := newTestFailoverClient(clock, primary, secondary)
+
+	servedBy(t, c)
+	servedBy(t, c)
+	require.Equal(t, 2, primary.calls)
+	require.Equal(t, 1.0, testutil.ToFloat64(backendCircuitOpen.WithLabelValues("test-breaker-primary")))
+
+	// The open circuit skips the primary.
+	require.Equal(t, "test-breaker-secondary", servedBy(t, c))
+	require.Equal(t, 2, primary.calls)
+
+	// It is tried again once the open duration has passed, and skipped again while it keeps failing.
+	clock.now = clock.now.Add(time.Minute)
+	require.Equal(t, "test-breaker-secondary", servedBy(t, c))
+	require.Equal(t, 3, primary.calls)
+	servedBy(t, c)
+	require.Equal(t, 3, primary.calls)
+
+	// A success closes the circuit.
+	primary.err = nil
+	clock.now = clock.now.Add(time.Minute)
+	require.Equal(t, "test-breaker-primary", servedBy(t, c))
+	require.Equal(t, 0.0, testutil.ToFloat64(backendCircuitOpen.WithLabelValues("test-breaker-primary")))
+	require.Equal(t, "test-breaker-primary", servedBy(t, c))
+}
+
+func TestFailoverClient_SingleProbe(t *testing.T) {
+	clock := &fakeClock{now: time.Now()}
+	primary := &stubBackend{name: "test-probe-primary", err: errors.New("connection refused")}
+	secondary := &stubBackend{name: "test-probe-secondary"}
+	c := newTestFailoverClient(clock, primary, secondary)
+	servedBy(t, c)
+	servedBy(t, c)
+
+	// Once the open duration has passed, a single call tries the primary again.
+	clock.now = clock.now.Add(time.Minute)
+	primary.err = nil
+	primary.block = make(chan struct{})
+	primary.started = make(chan struct{}, 2)
+	served := func() <-chan string {
+		titles := make(chan string, 1)
+		go func() {
+			item, err := c.GetItemByID(context.Background(), "vault", "item")
+			if err == nil {
+				titles <- item.Title
+			}
+		}()
+		return titles
+	}
+	probe := served()
+	<-primary.started
+
+	// The other calls skip it until the probe returns.
+	select {
+	case title := <-served():
+		require.Equal(t, "test-probe-secondary", title)
+	case <-time.After(time.Second):
+		close(primary.block)
+		t.Fatal("a second call tried the primary while it was probed")
+	}
+	require.Len(t, primary.started, 0)
+
+	close(primary.block)
+	require.Equal(t, "test-probe-primary", <-probe)
+	primary.block = nil
+	require.Equal(t, "test-probe-primary", servedBy(t, c))
+}
+
+func TestFailoverClient_ReleasesAbandonedProbe(t *testing.T) {
+	clock := &fakeClock{now: time.Now()}
+	primary := &stubBackend{name: "test-abandoned-primary", err: errors.New("connection refused")}
+	secondary := &stubBackend{name: "test-abandoned-secondary"}
+	c := newTestFailoverClient(clock, primary, secondary)
+	servedBy(t, c)
+	servedBy(t, c)
+
+	clock.now = clock.now.Add(time.Minute)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := c.GetItemByID(ctx, "vault", "item")
+	require.Error(t, err)
+	require.Equal(t, 3, primary.calls)
+
+	// The cancelled call doesn't keep the next call from trying the primary again.
+	primary.err = nil
+	require.Equal(t, "test-abandoned-primary", servedBy(t, c))
+}
+
+func TestFailoverClient_AllBackendsOpen(t *testing.T) {
+	primary := &stubBackend{name: "test-open-primary", err: errors.New("connection refused")}
+	secondary := &stubBackend{name: "test-open-secondary", err: errors.New("unauthorized")}
+	c := newTestFailoverClient(&fakeClock{now: time.Now()}, primary, secondary)
+
+	for i := 0; i < 3; i++ {
+		_, err := c.GetItemByID(context.Background(), "vault", "item")
+		require.ErrorContains(t, err, "test-open-primary: connection refused")
+		require.ErrorContains(t, err, "test-open-secondary: unauthorized")
+	}
+	// Every backend is still tried when all circuits are open.
+	require.Equal(t, 3, primary.calls)
+	require.Equal(t, 3, secondary.calls)
+
+	secondary.err = nil
+	require.Equal(t, "test-open-secondary", servedBy(t, c))
+}
+
+func TestFailoverClient_DoesNotFailOver(t *testing.T) {
+	notFound := &onepassword.Error{StatusCode: 404, Message: "item not found"}
+
+	t.Run("on request errors", func(t *testing.T) {
+		primary := &stubBackend{name: "test-request-primary", err: notFound}
+		secondary := &stubBackend{name: "test-request-secondary"}
+		c := newTestFailoverClient(&fakeClock{now: time.Now()}, primary, secondary)
+
+		for i := 0; i < 3; i++ {
+			_, err := c.GetItemByID(context.Background(), "vault", "item")
+			require.ErrorIs(t, err, notFound)
+		}
+		require.Equal(t, 0, secondary.calls)
+		require.Equal(t, 0.0, testutil.ToFloat64(backendCircuitOpen.WithLabelValues("test-request-primary")))
+	})
+
+	t.Run("when the context is done", func(t *testing.T) {
+		primary := &stubBackend{name: "test-cancelled-primary", err: context.Canceled}
+		secondary := &stubBackend{name: "test-cancelled-secondary"}
+		c := newTestFailoverClient(&fakeClock{now: time.Now()}, primary, secondary)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err := c.GetItemByID(ctx, "vault", "item")
+		require.ErrorIs(t, err, context.Canceled)
+		require.Equal(t, 0, secondary.calls)
+	})
+}
+
+func TestNew_Failover(t *testing.T) {
+	cfg := Config{Logger: logr.Discard()}
+
+	single, err := New(context.Background(), cfg, Credentials{
+		ConnectHost: "http://connect:8080", ConnectToken: "token",
+	})
+	require.NoError(t, err)
+	// A single backend is wrapped too, so its calls are reported in the metrics.
+	require.IsType(t, &FailoverClient{}, single)
+	require.Len(t, single.(*FailoverClient).backends, 1)
+	require.Equal(t, "connect:http://connect:8080", single.(*FailoverClient).backends[0].Name)
+	require.IsType(t, &connect.Connect{}, single.(*FailoverClient).backends[0].Client)
+
+	multiple, err := New(context.Background(), cfg, Credentials{
+		ConnectHost: "http://connect-a:8080, http://connect-b:8080", ConnectToken: "token",
+	})
+	require.NoError(t, err)
+	require.IsType(t, &FailoverClient{}, multiple)
+	var names []string
+	for _, backend := range multiple.(*FailoverClient).backends {
+		names = append(names, backend.Name)
+	}
+	require.Equal(t, []string{"connect:http://connect-a:8080", "connect:http://connect-b:8080"}, names)
+
+	_, err = New(context.Background(), cfg, Credentials{})
+	require.ErrorIs(t, err, ErrNoCredentials)
+}
@@ -0,0 +1,303 @@
+package file
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/1Password/onepassword-operator/pkg/onepassword/model"
+)
+
+// ErrNotFound is returned when a vault, item or file does not exist.
+var ErrNotFound = errors.New("not found")
+
+// itemExtensions are the extensions of the item files.
+var itemExtensions = map[string]bool{".json": true, ".yaml": true, ".yml": true}
+
+// Config holds the configuration for the file client.
+type Config struct {
+	// Dir holds a directory per vault, named after the vault, with a JSON or YAML file per item.
+	Dir string
+}
+
+// File is a client serving vaults and items from a local directory, e.g. to run the operator
+// without 1Password credentials in development or CI clusters. The files are read on every call,
+// so edits are picked up by the next poll.
+type File struct {
+	dir string
+}
+
+// NewClient creates a new file client reading the directory of the configuration.
+func NewClient(config Config) (*File, error) {
+	info, err := os.Stat(config.Dir)
+	if err != nil {
+		return nil, fmt.Errorf("invalid file backend directory: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("invalid file backend directory: %q is not a directory", config.Dir)
+	}
+	return &File{dir: config.Dir}, nil
+}
+
+// itemDocument is the content of an item file.
+type itemDocument struct {
+	// ID defaults to the name of the file without its extension.
+	ID string `json:"id"`
+	// Title defaults to the ID.
+	Title    string `json:"title"`
+	Category string `json:"category"`
+	// Version defaults to the modification time of the file, so edits update the Secrets.
+	Version  int               `json:"version"`
+	Tags     []string          `json:"tags"`
+	URLs     []urlDocument     `json:"urls"`
+	Sections []sectionDocument `json:"sections"`
+	Fields   []fieldDocument   `json:"fields"`
+	Files    []fileDocument    `json:"files"`
+}
+
+type urlDocument struct {
+	URL     string `json:"url"`
+	Label   string `json:"label"`
+	Primary bool   `json:"primary"`
+}
+
+type sectionDocument struct {
+	ID    string `json:"id"`
+	Title string `json:"title"`
+}
+
+type fieldDocument struct {
+	// ID defaults to the label.
+	ID    string `json:"id"`
+	Label string `json:"label"`
+	Value string `json:"value"`
+	// Type is a field type in the Connect spelling, e.g. CONCEALED. It defaults to STRING.
+	Type    string `json:"type"`
+	Purpose string `json:"purpose"`
+	// Section is the ID of the section of the field.
+	Section string `json:"section"`
+}
+
+type fileDocument struct {
+	// ID defaults to the name.
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	// Path of the file, relative to the vault directory. Either Path or Content is set.
+	Path    string `json:"path"`
+	Content string `json:"content"`
+
+	size int
+}
+
+func (f *File) GetItemByID(ctx context.Context, vaultID, itemID string) (*model.Item, error) {
+	items, err := f.readItems(vaultID)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range items {
+		if item.ID == itemID {
+			return item.toModel(vaultID), nil
+		}
+	}
+	return nil, fmt.Errorf("item %q in vault %q: %w", itemID, vaultID, ErrNotFound)
+}
+
+func (f *File) GetItemsByTitle(ctx context.Context, vaultID, itemTitle string) ([]model.Item, error) {
+	items, err := f.readItems(vaultID)
+	if err != nil {
+		return nil, err
+	}
+	var matching []model.Item
+	for _, item := range items {
+		if item.Title == itemTitle {
+			matching = append(matching, *item.toModel(vaultID))
+		}
+	}
+	return matching, nil
+}
+
+func (f *File) GetFileContent(ctx context.Context, vaultID, itemID, fileID string) ([]byte, error) {
+	items, err := f.readItems(vaultID)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range items {
+		if item.ID != itemID {
+			continue
+		}
+		for _, file := range item.Files {
+			if file.ID == fileID {
+				return f.fileContent(vaultID, file)
+			}
+		}
+	}
+	return nil, fmt.Errorf("file %q of item %q in vault %q: %w", fileID, itemID, vaultID, ErrNotFound)
+}
+
+func (f *File) GetVaultsByTitle(ctx context.Context, title string) ([]model.Vault, error) {
+	entries, err := os.ReadDir(f.dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file backend directory: %w", err)
+	}
+	var vaults []model.Vault
+	for _, entry := range entries {
+		if !entry.IsDir() || !strings.EqualFold(entry.Name(), title) {
+			continue
+		}
+		vault := model.Vault{ID: entry.Name()}
+		if info, err := entry.Info(); err == nil {
+			vault.CreatedAt = info.ModTime()
+		}
+		vaults = append(vaults, vault)
+	}
+	return vaults, nil
+}
+
+// item is an item read from a file.
+type item struct {
+	itemDocument
+	modTime time.Time
+}
+
+// readItems reads the items of the vault. Files that are not JSON or YAML, such as attachments, are ignored.
+func (f *File) readItems(vaultID string) ([]item, error) {
+	vaultDir, err := f.vaultDir(vaultID)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(vaultDir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("vault %q: %w", vaultID, ErrNotFound)
+		}
+		return nil, fmt.Errorf("failed to read vault %q: %w", vaultID, err)
+	}
+
+	var items []item
+	for _, entry := range entries {
+		ext := filepath.Ext(entry.Name())
+		if entry.IsDir() || !itemExtensions[ext] {
+			continue
+		}
+		path := filepath.Join(vaultDir, entry.Name())
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read item file %q: %w", path, err)
+		}
+		var doc itemDocument
+		if err := yaml.UnmarshalStrict(content, &doc); err != nil {
+			return nil, fmt.Errorf("invalid item file %q: %w", path, err)
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read item file %q: %w", path, err)
+		}
+		if doc.ID == "" {
+			doc.ID = strings.TrimSuffix(entry.Name(), ext)
+		}
+		if doc.Title == "" {
+			doc.Title = doc.ID
+		}
+		for i := range doc.Files {
+			if err := f.loadFileDocument(vaultDir, &doc.Files[i]); err != nil {
+				return nil, fmt.Errorf("invalid item file %q: %w", path, err)
+			}
+		}
+		items = append(items, item{itemDocument: doc, modTime: info.ModTime()})
+	}
+	return items, nil
+}
+
+func (f *File) vaultDir(vaultID string) (string, error) {
+	if !filepath.IsLocal(vaultID) || strings.ContainsRune(vaultID, filepath.Separator) {
+		return "", fmt.Errorf("vault %q: %w", vaultID, ErrNotFound)
+	}
+	return filepath.Join(f.dir, vaultID), nil
+}
+
+// loadFileDocument defaults the ID of the file and sets its size.
+func (f *File) loadFileDocument(vaultDir string, file *fileDocument) error {
+	if file.ID == "" {
+		file.ID = file.Name
+	}
+	if file.Path == "" {
+		file.size = len(file.Content)
+		return nil
+	}
+	if !filepath.IsLocal(file.Path) {
+		return fmt.Errorf("file %q: path %q must be relative to the vault directory", file.Name, file.Path)
+	}
+	info, err := os.Stat(filepath.Join(vaultDir, file.Path))
+	if err != nil {
+		return fmt.Errorf("file %q: %w", file.Name, err)
+	}
+	file.size = int(info.Size())
+	return nil
+}
+
+func (f *File) fileContent(vaultID string, file fileDocument) ([]byte, error) {
+	if file.Path == "" {
+		return []byte(file.Content), nil
+	}
+	vaultDir, err := f.vaultDir(vaultID)
+	if err != nil {
+		return nil, err
+	}
+	content, err := os.ReadFile(filepath.Join(vaultDir, file.Path))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file %q: %w", file.Name, err)
+	}
+	return content, nil
+}
+
+func (i *item) toModel(vaultID string) *model.Item {
+	result := &model.Item{
+		ID:        i.ID,
+		VaultID:   vaultID,
+		Title:     i.Title,
+		Category:  i.Category,
+		Version:   i.Version,
+		Tags:      append([]string(nil), i.Tags...),
+		CreatedAt: i.modTime,
+		UpdatedAt: i.modTime,
+	}
+	if result.Category == "" {
+		result.Category = model.CategoryCustom
+	}
+	if result.Version == 0 {
+		result.Version = int(i.modTime.Unix())
+	}
+	for _, url := range i.URLs {
+		result.URLs = append(result.URLs, model.ItemURL{URL: url.URL, Label: url.Label, Primary: url.Primary})
+	}
+	for _, section := range i.Sections {
+		result.Sections = append(result.Sections, model.ItemSection{ID: section.ID, Title: section.Title})
+	}
+	for _, field := range i.Fields {
+		modelField := model.ItemField{
+			ID:        field.ID,
+			Label:     field.Label,
+			Value:     field.Value,
+			SectionID: field.Section,
+			FieldType: field.Type,
+			Purpose:   field.Purpose,
+		}
+		if modelField.ID == "" {
+			modelField.ID = field.Label
+		}
+		if modelField.FieldType == "" {
+			modelField.FieldType = model.FieldTypeString
+		}
+		result.Fields = append(result.Fields, modelField)
+	}
+	for _, file := range i.Files {
+		result.Files = append(result.Files, model.File{ID: file.ID, Name: file.Name, Size: file.size})
+	}
+	return result
+}
@@ -0,0 +1,170 @@
+package file
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/1Password/onepassword-operator/pkg/onepassword/model"
+)
+
+const databaseItem = `
+title: database
+category: DATABASE
+version: 3
+tags: [production]
+sections:
+  - id: connection
+    title: Connection
+fields:
+  - label: username
+    value: admin
+  - label: password
+    value: s3cr3t
+    type: CONCEALED
+    purpose: PASSWORD
+  - label: host
+    value: db.internal
+    section: connection
+files:
+  - name: ca.pem
+    path: files/ca.pem
+  - name: config.txt
+    content: inline
+`
+
+const apiItem = `{
+  "id": "api",
+  "title": "API Key",
+  "fields": [{"label": "credential", "value": "key"}]
+}`
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+}
+
+func newTestClient(t *testing.T) *File {
+	t.Helper()
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "Production", "database.yaml"), databaseItem)
+	writeFile(t, filepath.Join(dir, "Production", "api.json"), apiItem)
+	writeFile(t, filepath.Join(dir, "Production", "files", "ca.pem"), "certificate")
+	writeFile(t, filepath.Join(dir, "Production", "README.md"), "ignored")
+	writeFile(t, filepath.Join(dir, "Staging", "database.yml"), "fields: [{label: username, value: staging}]")
+
+	client, err := NewClient(Config{Dir: dir})
+	require.NoError(t, err)
+	return client
+}
+
+func TestFile_GetVaultsByTitle(t *testing.T) {
+	client := newTestClient(t)
+
+	vaults, err := client.GetVaultsByTitle(context.Background(), "production")
+	require.NoError(t, err)
+	require.Len(t, vaults, 1)
+	require.Equal(t, "Production", vaults[0].ID)
+
+	vaults, err = client.GetVaultsByTitle(context.Background(), "Missing")
+	require.NoError(t, err)
+	require.Empty(t, vaults)
+}
+
+func TestFile_GetItemByID(t *testing.T) {
+	client := newTestClient(t)
+
+	item, err := client.GetItemByID(context.Background(), "Production", "database")
+	require.NoError(t, err)
+	require.Equal(t, "database", item.ID)
+	require.Equal(t, "Production", item.VaultID)
+	require.Equal(t, model.CategoryDatabase, item.Category)
+	require.Equal(t, 3, item.Version)
+	require.Equal(t, []string{"production"}, item.Tags)
+	require.Equal(t, []model.ItemSection{{ID: "connection", Title: "Connection"}}, item.Sections)
+	require.Equal(t, []model.ItemField{
+		{ID: "username", Label: "username", Value: "admin", FieldType: model.FieldTypeString},
+		{ID: "password", Label: "password", Value: "s3cr3t", FieldType: model.FieldTypeConcealed, Purpose: "PASSWORD"},
+		{ID: "host", Label: "host", Value: "db.internal", SectionID: "connection", FieldType: model.FieldTypeString},
+	}, item.Fields)
+	require.Len(t, item.Files, 2)
+	require.Equal(t, "ca.pem", item.Files[0].ID)
+	require.Equal(t, len("certificate"), item.Files[0].Size)
+
+	// Defaults for a minimal item.
+	item, err = client.GetItemByID(context.Background(), "Staging", "database")
+	require.NoError(t, err)
+	require.Equal(t, "database", item.Title)
+	require.Equal(t, model.CategoryCustom, item.Category)
+	require.NotZero(t, item.Version, "the version should default to the modification time")
+
+	_, err = client.GetItemByID(context.Background(), "Production", "missing")
+	require.ErrorIs(t, err, ErrNotFound)
+	_, err = client.GetItemByID(context.Background(), "Missing", "database")
+	require.ErrorIs(t, err, ErrNotFound)
+	_, err = client.GetItemByID(context.Background(), "../Production", "database")
+	require.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestFile_GetItemsByTitle(t *testing.T) {
+	client := newTestClient(t)
+
+	items, err := client.GetItemsByTitle(context.Background(), "Production", "API Key")
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	require.Equal(t, "api", items[0].ID)
+
+	items, err = client.GetItemsByTitle(context.Background(), "Production", "missing")
+	require.NoError(t, err)
+	require.Empty(t, items)
+}
+
+func TestFile_GetFileContent(t *testing.T) {
+	client := newTestClient(t)
+
+	content, err := client.GetFileContent(context.Background(), "Production", "database", "ca.pem")
+	require.NoError(t, err)
+	require.Equal(t, []byte("certificate"), content)
+
+	content, err = client.GetFileContent(context.Background(), "Production", "database", "config.txt")
+	require.NoError(t, err)
+	require.Equal(t, []byte("inline"), content)
+
+	_, err = client.GetFileContent(context.Background(), "Production", "database", "missing")
+	require.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestFile_InvalidItems(t *testing.T) {
+	testCases := map[string]string{
+		"should reject unknown fields":       "fields: [{label: username, vaule: typo}]",
+		"should reject a path outside vault": "files: [{name: passwd, path: ../../etc/passwd}]",
+		"should reject a missing file":       "files: [{name: missing, path: files/missing}]",
+		"should reject invalid YAML":         "fields: [",
+	}
+	for description, content := range testCases {
+		t.Run(description, func(t *testing.T) {
+			dir := t.TempDir()
+			writeFile(t, filepath.Join(dir, "vault", "item.yaml"), content)
+			client, err := NewClient(Config{Dir: dir})
+			require.NoError(t, err)
+
+			_, err = client.GetItemByID(context.Background(), "vault", "item")
+			require.Error(t, err)
+			require.NotErrorIs(t, err, ErrNotFound)
+		})
+	}
+}
+
+func TestNewClient(t *testing.T) {
+	_, err := NewClient(Config{Dir: filepath.Join(t.TempDir(), "missing")})
+	require.Error(t, err)
+
+	path := filepath.Join(t.TempDir(), "file")
+	writeFile(t, path, "")
+	_, err = NewClient(Config{Dir: path})
+	require.Error(t, err)
+}
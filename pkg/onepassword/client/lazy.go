@@ -0,0 +1,215 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/1Password/connect-sdk-go/onepassword"
+
+	"github.com/1Password/onepassword-operator/pkg/onepassword/client/sdk"
+	"github.com/1Password/onepassword-operator/pkg/onepassword/model"
+)
+
+// Delays between the attempts of a LazyClient to create its client. The delay doubles after every failed
+// attempt, up to the maximum.
+const (
+	DefaultLazyMinRetryDelay = time.Second
+	DefaultLazyMaxRetryDelay = time.Minute
+)
+
+// ErrBackendUnavailable is returned by a LazyClient while 1Password can't be reached, either because the
+// client could not be created yet or because the backend failed the call.
+var ErrBackendUnavailable = errors.New("1Password backend is unavailable")
+
+// IsBackendUnavailable reports whether the error is caused by 1Password being unreachable.
+func IsBackendUnavailable(err error) bool {
+	return errors.Is(err, ErrBackendUnavailable)
+}
+
+// IsTransientError reports whether creating a client failed because 1Password or Kubernetes could not be
+// reached, or because the token Secret does not exist or can't be read yet, so retrying may succeed once
+// the Secret is created or access to it is granted. Other errors, e.g. an unknown backend or an invalid
+// certificate, come from the configuration and fail every attempt.
+func IsTransientError(err error) bool {
+	var netErr net.Error
+	var opErr *net.OpError
+	var dnsErr *net.DNSError
+	var connectErr *onepassword.Error
+	switch {
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, io.ErrUnexpectedEOF),
+		errors.As(err, &opErr), errors.As(err, &dnsErr), errors.As(err, &netErr) && netErr.Timeout():
+		return true
+	case errors.As(err, &connectErr):
+		return connectErr.StatusCode >= http.StatusInternalServerError ||
+			connectErr.StatusCode == http.StatusTooManyRequests
+	case apierrors.IsServerTimeout(err), apierrors.IsTimeout(err), apierrors.IsTooManyRequests(err),
+		apierrors.IsInternalError(err), apierrors.IsServiceUnavailable(err), apierrors.IsUnexpectedServerError(err),
+		apierrors.IsNotFound(err), apierrors.IsForbidden(err):
+		return true
+	}
+	return sdk.IsRateLimited(err) || sdk.IsUnreachable(err)
+}
+
+// LazyClient is a Client whose underlying client is created in the background, so the operator can start
+// while 1Password is unreachable. Until the client is created, and whenever the backend fails a call, calls
+// return errors wrapping ErrBackendUnavailable.
+type LazyClient struct {
+	logger    logr.Logger
+	newClient func(ctx context.Context) (Client, error)
+
+	minRetryDelay time.Duration
+	maxRetryDelay time.Duration
+
+	mu     sync.RWMutex
+	client Client
+	// lastErr is the last failure reaching the backend. It is nil while the backend is available.
+	lastErr error
+}
+
+var _ Client = (*LazyClient)(nil)
+
+// NewLazyClient creates a LazyClient building its client with newClient. Nothing is created until Init
+// or Start is called.
+func NewLazyClient(logger logr.Logger, newClient func(ctx context.Context) (Client, error)) *LazyClient {
+	return &LazyClient{
+		logger:        logger,
+		newClient:     newClient,
+		minRetryDelay: DefaultLazyMinRetryDelay,
+		maxRetryDelay: DefaultLazyMaxRetryDelay,
+	}
+}
+
+// Init tries once to create the client, unless it was already created.
+func (c *LazyClient) Init(ctx context.Context) error {
+	if c.initialized() {
+		return nil
+	}
+	client, err := c.newClient(ctx)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err != nil {
+		c.lastErr = err
+		return err
+	}
+	c.client = client
+	c.lastErr = nil
+	return nil
+}
+
+// Start retries to create the client until it succeeds or the context is done.
+func (c *LazyClient) Start(ctx context.Context) error {
+	delay := c.minRetryDelay
+	for !c.initialized() {
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil
+		case <-timer.C:
+		}
+
+		if err := c.Init(ctx); err != nil {
+			delay = min(2*delay, c.maxRetryDelay)
+			c.logger.Error(err, "1Password is still unavailable", "retryIn", delay.String())
+			continue
+		}
+		c.logger.Info("1Password is available")
+	}
+	return nil
+}
+
+// NeedLeaderElection reports that every replica creates its client, not only the leader.
+func (c *LazyClient) NeedLeaderElection() bool {
+	return false
+}
+
+// Check fails while the backend is unavailable. It is a healthz.Checker, so the readiness of the operator
+// reflects whether 1Password can be reached.
+func (c *LazyClient) Check(_ *http.Request) error {
+	_, err := c.current()
+	return err
+}
+
+func (c *LazyClient) initialized() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.client != nil
+}
+
+// current returns the client, or an error while the backend is unavailable.
+func (c *LazyClient) current() (Client, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	switch {
+	case c.lastErr != nil:
+		return c.client, fmt.Errorf("%w: %w", ErrBackendUnavailable, c.lastErr)
+	case c.client == nil:
+		return nil, ErrBackendUnavailable
+	}
+	return c.client, nil
+}
+
+// observe tracks the availability of the backend from the result of a call. Failures of the backend are
+// wrapped with ErrBackendUnavailable, while errors about the request, rate limits and cancelled calls
+// are returned as is and leave the availability unchanged.
+func (c *LazyClient) observe(ctx context.Context, err error) error {
+	if err != nil && (ctx.Err() != nil || isRateLimited(err)) {
+		return err
+	}
+	unavailable := err != nil && isBackendFailure(err)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !unavailable {
+		c.lastErr = nil
+		return err
+	}
+	c.lastErr = err
+	return fmt.Errorf("%w: %w", ErrBackendUnavailable, err)
+}
+
+func (c *LazyClient) GetItemByID(ctx context.Context, vaultID, itemID string) (*model.Item, error) {
+	client, err := c.current()
+	if client == nil {
+		return nil, err
+	}
+	item, err := client.GetItemByID(ctx, vaultID, itemID)
+	return item, c.observe(ctx, err)
+}
+
+func (c *LazyClient) GetItemsByTitle(ctx context.Context, vaultID, itemTitle string) ([]model.Item, error) {
+	client, err := c.current()
+	if client == nil {
+		return nil, err
+	}
+	items, err := client.GetItemsByTitle(ctx, vaultID, itemTitle)
+	return items, c.observe(ctx, err)
+}
+
+func (c *LazyClient) GetFileContent(ctx context.Context, vaultID, itemID, fileID string) ([]byte, error) {
+	client, err := c.current()
+	if client == nil {
+		return nil, err
+	}
+	content, err := client.GetFileContent(ctx, vaultID, itemID, fileID)
+	return content, c.observe(ctx, err)
+}
+
+func (c *LazyClient) GetVaultsByTitle(ctx context.Context, title string) ([]model.Vault, error) {
+	client, err := c.current()
+	if client == nil {
+		return nil, err
+	}
+	vaults, err := client.GetVaultsByTitle(ctx, title)
+	return vaults, c.observe(ctx, err)
+}
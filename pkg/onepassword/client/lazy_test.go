@@ -0,0 +1,144 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/1Password/connect-sdk-go/onepassword"
+	onepasswordsdk "github.com/1password/onepassword-sdk-go"
+
+	"github.com/1Password/onepassword-operator/pkg/onepassword/client/sdk"
+)
+
+func TestLazyClient_RetriesUntilAvailable(t *testing.T) {
+	backend := &stubBackend{name: "connect"}
+	var attempts atomic.Int32
+	c := NewLazyClient(logr.Discard(), func(context.Context) (Client, error) {
+		if attempts.Add(1) < 3 {
+			return nil, errors.New("connection refused")
+		}
+		return backend, nil
+	})
+	c.minRetryDelay = time.Millisecond
+	c.maxRetryDelay = time.Millisecond
+
+	err := c.Init(context.Background())
+	require.EqualError(t, err, "connection refused")
+	_, err = c.GetItemByID(context.Background(), "vault", "item")
+	require.ErrorIs(t, err, ErrBackendUnavailable)
+	require.ErrorContains(t, err, "connection refused")
+	require.Error(t, c.Check(nil))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(t, c.Start(ctx))
+	require.EqualValues(t, 3, attempts.Load())
+
+	item, err := c.GetItemByID(context.Background(), "vault", "item")
+	require.NoError(t, err)
+	require.Equal(t, "connect", item.Title)
+	require.NoError(t, c.Check(nil))
+}
+
+func TestLazyClient_StartStopsWithContext(t *testing.T) {
+	c := NewLazyClient(logr.Discard(), func(context.Context) (Client, error) {
+		return nil, errors.New("connection refused")
+	})
+	c.minRetryDelay = time.Millisecond
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	require.NoError(t, c.Start(ctx))
+	require.ErrorIs(t, c.Check(nil), ErrBackendUnavailable)
+}
+
+func TestLazyClient_TracksBackendFailures(t *testing.T) {
+	backend := &stubBackend{name: "connect"}
+	c := NewLazyClient(logr.Discard(), func(context.Context) (Client, error) {
+		return backend, nil
+	})
+	require.NoError(t, c.Init(context.Background()))
+
+	backend.err = &onepassword.Error{StatusCode: http.StatusNotFound, Message: "item not found"}
+	_, err := c.GetItemByID(context.Background(), "vault", "item")
+	require.False(t, IsBackendUnavailable(err), "a missing item is not a backend failure")
+	require.NoError(t, c.Check(nil))
+
+	backend.err = fmt.Errorf("failed to GetItemsByTitle using 1Password SDK: %w",
+		fmt.Errorf("%w: vault not found", sdk.ErrRequest))
+	_, err = c.GetItemByID(context.Background(), "vault", "item")
+	require.False(t, IsBackendUnavailable(err), "a vault the SDK can't find is not a backend failure")
+	require.NoError(t, c.Check(nil))
+
+	backend.err = &onepassword.Error{StatusCode: http.StatusTooManyRequests, Message: "rate limit exceeded"}
+	_, err = c.GetItemByID(context.Background(), "vault", "item")
+	require.False(t, IsBackendUnavailable(err), "a rate limit is not a backend failure")
+
+	backend.err = fmt.Errorf("failed to GetItemsByTitle using 1Password SDK: %w", &onepasswordsdk.RateLimitExceededError{})
+	_, err = c.GetItemByID(context.Background(), "vault", "item")
+	require.False(t, IsBackendUnavailable(err), "a rate limit of the SDK is not a backend failure")
+
+	backend.err = errors.New("connection refused")
+	_, err = c.GetItemByID(context.Background(), "vault", "item")
+	require.True(t, IsBackendUnavailable(err))
+	require.ErrorContains(t, c.Check(nil), "connection refused")
+
+	backend.err = &onepassword.Error{StatusCode: http.StatusTooManyRequests, Message: "rate limit exceeded"}
+	_, err = c.GetItemByID(context.Background(), "vault", "item")
+	require.Error(t, c.Check(nil), "a rate limit should not make the backend available again")
+
+	backend.err = nil
+	_, err = c.GetItemByID(context.Background(), "vault", "item")
+	require.NoError(t, err)
+	require.NoError(t, c.Check(nil), "a successful call should make the backend available again")
+}
+
+func TestIsTransientError(t *testing.T) {
+	tests := map[string]struct {
+		err       error
+		transient bool
+	}{
+		"connection refused": {
+			err:       &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")},
+			transient: true,
+		},
+		"timeout":                {err: fmt.Errorf("request: %w", context.DeadlineExceeded), transient: true},
+		"connect server error":   {err: &onepassword.Error{StatusCode: http.StatusBadGateway}, transient: true},
+		"kubernetes unavailable": {err: apierrors.NewServiceUnavailable("etcd"), transient: true},
+		"sdk unreachable": {
+			err:       errors.New("1Password sdk error: error sending request for url (https://my.1password.com)"),
+			transient: true,
+		},
+		"unknown backend": {err: errors.New(`unknown 1Password backend "vault"`)},
+		"invalid token":   {err: &onepassword.Error{StatusCode: http.StatusUnauthorized}},
+		"missing token secret": {
+			err: fmt.Errorf("failed to get secret: %w",
+				apierrors.NewNotFound(schema.GroupResource{Resource: "secrets"}, "token")),
+			transient: true,
+		},
+		"forbidden token secret": {
+			err: fmt.Errorf("failed to get secret: %w",
+				apierrors.NewForbidden(schema.GroupResource{Resource: "secrets"}, "token", errors.New("no RBAC"))),
+			transient: true,
+		},
+		"invalid proxy url": {err: &url.Error{Op: "parse", URL: "::", Err: errors.New("missing protocol scheme")}},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, tt.transient, IsTransientError(tt.err))
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package client
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// Sources of the credentials recorded by the credentials reload metric.
+const (
+	// CredentialsSourceDefault is the client the operator was started with.
+	CredentialsSourceDefault = "default"
+	// CredentialsSourceConnection is the client of a OnePasswordConnection or ClusterOnePasswordConnection.
+	CredentialsSourceConnection = "connection"
+)
+
+var credentialsReloads = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "onepassword_operator_credentials_reloads_total",
+		Help: "Number of times a 1Password client was rebuilt because its credentials changed, by result.",
+	},
+	[]string{"source", "result"},
+)
+
+func init() {
+	metrics.Registry.MustRegister(credentialsReloads)
+}
+
+// RecordCredentialsReload records a reload of the credentials of the given source.
+func RecordCredentialsReload(source string, err error) {
+	result := "success"
+	if err != nil {
+		result = "error"
+	}
+	credentialsReloads.WithLabelValues(source, result).Inc()
+}
+
+// Results of a call recorded by the backend calls metric.
+const (
+	backendResultSuccess = "success"
+	// backendResultRequestError is an error about the request, e.g. a missing item, returned by a healthy backend.
+	backendResultRequestError = "request_error"
+	// backendResultFailure is an error of the backend, after which the call is routed to the next backend.
+	backendResultFailure = "failure"
+)
+
+var (
+	backendCalls = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "onepassword_operator_backend_calls_total",
+			Help: "Number of calls to each 1Password backend of a failover client, by operation and result.",
+		},
+		[]string{"backend", "operation", "result"},
+	)
+	backendCircuitOpen = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "onepassword_operator_backend_circuit_open",
+			Help: "Whether a 1Password backend of a failover client is skipped after consecutive failures.",
+		},
+		[]string{"backend"},
+	)
+)
+
+func init() {
+	metrics.Registry.MustRegister(backendCalls, backendCircuitOpen)
+}
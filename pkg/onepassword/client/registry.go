@@ -0,0 +1,162 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/1Password/onepassword-operator/pkg/onepassword/client/connect"
+	"github.com/1Password/onepassword-operator/pkg/onepassword/client/file"
+	"github.com/1Password/onepassword-operator/pkg/onepassword/client/sdk"
+)
+
+// Names of the built-in backends.
+const (
+	BackendConnect        = "connect"
+	BackendServiceAccount = "serviceAccount"
+	BackendFile           = "file"
+)
+
+const (
+	// BackendEnv is the name of the backend the operator uses. When empty, the backend is selected from
+	// the credentials.
+	BackendEnv = "OP_BACKEND"
+	// FileBackendDirEnv is the directory the file backend serves vaults and items from.
+	FileBackendDirEnv = "OP_FILE_BACKEND_DIR"
+	// ShadowBackendEnv is the name of a backend the items are also read through, to compare the Secrets
+	// it renders with the ones being served before migrating to it.
+	ShadowBackendEnv = "OP_SHADOW_BACKEND"
+)
+
+// BackendFactory creates the client of a backend. Backends using Connect or Service Account credentials
+// read them from credentials; other backends read their settings from the environment.
+type BackendFactory func(ctx context.Context, cfg Config, credentials Credentials) (Client, error)
+
+var (
+	backendsMu sync.RWMutex
+	backends   = map[string]BackendFactory{}
+)
+
+func init() {
+	RegisterBackend(BackendConnect, newConnectBackend)
+	RegisterBackend(BackendServiceAccount, newServiceAccountBackend)
+	RegisterBackend(BackendFile, newFileBackend)
+}
+
+// RegisterBackend makes a backend available by name, e.g. from an init function.
+// It panics if the name is empty or already registered.
+func RegisterBackend(name string, factory BackendFactory) {
+	backendsMu.Lock()
+	defer backendsMu.Unlock()
+	if name == "" || factory == nil {
+		panic("client: RegisterBackend requires a name and a factory")
+	}
+	if _, ok := backends[name]; ok {
+		panic(fmt.Sprintf("client: backend %q is already registered", name))
+	}
+	backends[name] = factory
+}
+
+// RegisteredBackends returns the sorted names of the registered backends.
+func RegisteredBackends() []string {
+	backendsMu.RLock()
+	defer backendsMu.RUnlock()
+	names := make([]string, 0, len(backends))
+	for name := range backends {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// NewBackend creates the client of the named backend.
+func NewBackend(ctx context.Context, name string, cfg Config, credentials Credentials) (Client, error) {
+	backendsMu.RLock()
+	factory, ok := backends[name]
+	backendsMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown 1Password backend %q, registered backends are %s",
+			name, strings.Join(RegisteredBackends(), ", "))
+	}
+	return factory(ctx, cfg, credentials)
+}
+
+// newConnectBackend creates a client of the Connect servers listed in ConnectHost, failing over
+// between them in order when several are listed.
+func newConnectBackend(_ context.Context, cfg Config, credentials Credentials) (Client, error) {
+	if credentials.ConnectHost == "" || credentials.ConnectToken == "" {
+		return nil, fmt.Errorf("the %s backend requires a Connect host and token", BackendConnect)
+	}
+	backends, err := newConnectBackends(cfg, credentials)
+	if err != nil {
+		return nil, err
+	}
+	return newFailoverBackends(cfg, backends)
+}
+
+// newConnectBackends creates a backend per Connect server listed in ConnectHost.
+func newConnectBackends(cfg Config, credentials Credentials) ([]Backend, error) {
+	var backends []Backend
+	for _, host := range strings.Split(credentials.ConnectHost, ",") {
+		host = strings.TrimSpace(host)
+		if host == "" {
+			continue
+		}
+		cfg.Logger.Info("Using 1Password Connect", "host", host)
+		connectClient, err := connect.NewClient(connect.Config{
+			ConnectHost:  host,
+			ConnectToken: credentials.ConnectToken,
+			Transport:    credentials.ConnectTransport,
+		})
+		if err != nil {
+			return nil, err
+		}
+		backends = append(backends, Backend{Name: BackendConnect + ":" + host, Client: connectClient})
+	}
+	return backends, nil
+}
+
+func newServiceAccountBackend(ctx context.Context, cfg Config, credentials Credentials) (Client, error) {
+	if credentials.ServiceAccountToken == "" {
+		return nil, fmt.Errorf("the %s backend requires a Service Account token", BackendServiceAccount)
+	}
+	cfg.Logger.Info("Using Service Account Token")
+	sdkClient, err := sdk.NewClient(ctx, sdk.Config{
+		ServiceAccountToken: credentials.ServiceAccountToken,
+		IntegrationName:     "1password-operator",
+		IntegrationVersion:  cfg.Version,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return sdkClient, nil
+}
+
+func newFileBackend(_ context.Context, cfg Config, _ Credentials) (Client, error) {
+	dir := os.Getenv(FileBackendDirEnv)
+	if dir == "" {
+		return nil, fmt.Errorf("the %s backend requires %s", BackendFile, FileBackendDirEnv)
+	}
+	cfg.Logger.Info("Using the file backend, items are not read from 1Password", "dir", dir)
+	fileClient, err := file.NewClient(file.Config{Dir: dir})
+	if err != nil {
+		return nil, err
+	}
+	return fileClient, nil
+}
+
+// newFailoverBackends returns a FailoverClient of the backends. A single backend is wrapped too, so the
+// metrics and circuit breaker state of its calls are reported. As it has no other backend to route to,
+// its calls are never skipped.
+func newFailoverBackends(cfg Config, backends []Backend) (Client, error) {
+	if len(backends) == 0 {
+		return nil, ErrNoCredentials
+	}
+	if len(backends) > 1 {
+		cfg.Logger.Info("Failing over between 1Password backends", "backends", len(backends))
+	}
+	return NewFailoverClient(cfg.Logger, cfg.Failover, backends...), nil
+}
@@ -0,0 +1,55 @@
+package client
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/require"
+
+	"github.com/1Password/onepassword-operator/pkg/onepassword/client/file"
+)
+
+func TestRegisterBackend(t *testing.T) {
+	stub := &stubBackend{name: "custom"}
+	RegisterBackend("test-custom", func(context.Context, Config, Credentials) (Client, error) {
+		return stub, nil
+	})
+	require.Contains(t, RegisteredBackends(), "test-custom")
+
+	client, err := New(context.Background(), Config{Logger: logr.Discard(), Backend: "test-custom"}, Credentials{})
+	require.NoError(t, err)
+	require.Same(t, stub, client)
+
+	require.Panics(t, func() {
+		RegisterBackend("test-custom", func(context.Context, Config, Credentials) (Client, error) {
+			return nil, nil
+		})
+	})
+
+	_, err = NewBackend(context.Background(), "missing", Config{Logger: logr.Discard()}, Credentials{})
+	require.ErrorContains(t, err, `unknown 1Password backend "missing"`)
+}
+
+func TestNew_FileBackend(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "vault"), 0o755))
+	cfg := Config{Logger: logr.Discard(), Backend: BackendFile}
+
+	t.Setenv(FileBackendDirEnv, "")
+	_, err := New(context.Background(), cfg, Credentials{})
+	require.ErrorContains(t, err, FileBackendDirEnv)
+
+	t.Setenv(FileBackendDirEnv, dir)
+	client, err := New(context.Background(), cfg, Credentials{})
+	require.NoError(t, err)
+	require.IsType(t, &file.File{}, client)
+}
+
+func TestNew_NamedBackendRequiresCredentials(t *testing.T) {
+	cfg := Config{Logger: logr.Discard(), Backend: BackendConnect}
+	_, err := New(context.Background(), cfg, Credentials{ServiceAccountToken: "token"})
+	require.ErrorContains(t, err, "requires a Connect host and token")
+}
@@ -0,0 +1,296 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+
+	"github.com/1Password/onepassword-operator/pkg/onepassword/client/connect"
+	"github.com/1Password/onepassword-operator/pkg/onepassword/model"
+)
+
+// DefaultReloadInterval is how often a ReloadingClient reads its credentials when no file change is notified.
+const DefaultReloadInterval = 30 * time.Second
+
+// TokenSource provides a token that can change while the operator runs.
+type TokenSource interface {
+	Token(ctx context.Context) (string, error)
+	// String describes the source in logs, without revealing the token.
+	String() string
+}
+
+// StaticToken is a token that never changes, e.g. one read from an environment variable.
+type StaticToken string
+
+func (t StaticToken) Token(context.Context) (string, error) {
+	return string(t), nil
+}
+
+func (t StaticToken) String() string {
+	return "static token"
+}
+
+// FileTokenSource reads a token from a file, e.g. a Secret mounted as a volume, which the kubelet
+// updates when the Secret is rotated.
+type FileTokenSource struct {
+	Path string
+}
+
+func (s *FileTokenSource) Token(context.Context) (string, error) {
+	content, err := os.ReadFile(s.Path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read token file: %w", err)
+	}
+	return strings.TrimSpace(string(content)), nil
+}
+
+func (s *FileTokenSource) String() string {
+	return "file " + s.Path
+}
+
+// ConnectTransportSource configures how the Connect server is reached. The certificate files are read
+// on every reload, so certificates rotated on disk are used without restarting the operator.
+type ConnectTransportSource struct {
+	CAFile                string
+	ClientCertificateFile string
+	ClientKeyFile         string
+	ProxyURL              string
+	Timeout               time.Duration
+	OperationTimeout      time.Duration
+	UserAgent             string
+}
+
+// Files returns the files read by the source.
+func (s ConnectTransportSource) Files() []string {
+	var files []string
+	for _, file := range []string{s.CAFile, s.ClientCertificateFile, s.ClientKeyFile} {
+		if file != "" {
+			files = append(files, file)
+		}
+	}
+	return files
+}
+
+// Load reads the certificate files and returns the transport configuration.
+func (s ConnectTransportSource) Load() (connect.TransportConfig, error) {
+	transport := connect.TransportConfig{
+		ProxyURL:         s.ProxyURL,
+		Timeout:          s.Timeout,
+		OperationTimeout: s.OperationTimeout,
+		UserAgent:        s.UserAgent,
+	}
+	for _, file := range []struct {
+		path string
+		dest *[]byte
+	}{
+		{s.CAFile, &transport.CABundle},
+		{s.ClientCertificateFile, &transport.ClientCertificate},
+		{s.ClientKeyFile, &transport.ClientKey},
+	} {
+		if file.path == "" {
+			continue
+		}
+		content, err := os.ReadFile(file.path)
+		if err != nil {
+			return connect.TransportConfig{}, fmt.Errorf("failed to read Connect TLS file: %w", err)
+		}
+		*file.dest = content
+	}
+	return transport, nil
+}
+
+// CredentialSources provides the credentials of either 1Password Connect or a Service Account.
+// A nil source is not used.
+type CredentialSources struct {
+	ConnectHost         string
+	ConnectToken        TokenSource
+	ServiceAccountToken TokenSource
+	ConnectTransport    ConnectTransportSource
+}
+
+// Credentials reads the current credentials of the sources.
+func (s CredentialSources) Credentials(ctx context.Context) (Credentials, error) {
+	credentials := Credentials{ConnectHost: s.ConnectHost}
+	var err error
+	if s.ConnectToken != nil {
+		if credentials.ConnectToken, err = s.ConnectToken.Token(ctx); err != nil {
+			return Credentials{}, fmt.Errorf("failed to read Connect token from %s: %w", s.ConnectToken, err)
+		}
+	}
+	if s.ServiceAccountToken != nil {
+		if credentials.ServiceAccountToken, err = s.ServiceAccountToken.Token(ctx); err != nil {
+			return Credentials{}, fmt.Errorf("failed to read Service Account token from %s: %w",
+				s.ServiceAccountToken, err)
+		}
+	}
+	if credentials.ConnectTransport, err = s.ConnectTransport.Load(); err != nil {
+		return Credentials{}, err
+	}
+	return credentials, nil
+}
+
+// ReloadingClient is a Client whose underlying client is rebuilt when its credentials change, so rotated
+// tokens are picked up without restarting the operator. Every call is served by the client that is current
+// when the call starts, so calls in flight during a reload complete with the previous client.
+type ReloadingClient struct {
+	cfg       Config
+	sources   CredentialSources
+	interval  time.Duration
+	newClient func(ctx context.Context, credentials Credentials) (Client, error)
+
+	// reloadMu serializes reloads; calls only read current.
+	reloadMu sync.Mutex
+	current  atomic.Pointer[reloadedClient]
+}
+
+type reloadedClient struct {
+	client Client
+	// hash identifies the credentials the client was built with.
+	hash string
+}
+
+var _ Client = (*ReloadingClient)(nil)
+
+// NewReloadingClient creates a client from the credential sources. The credentials are read again every
+// interval, and whenever a token file changes, once Start is called.
+func NewReloadingClient(
+	ctx context.Context,
+	cfg Config,
+	sources CredentialSources,
+	interval time.Duration,
+) (*ReloadingClient, error) {
+	c := &ReloadingClient{
+		cfg:      cfg,
+		sources:  sources,
+		interval: interval,
+		newClient: func(ctx context.Context, credentials Credentials) (Client, error) {
+			return New(ctx, cfg, credentials)
+		},
+	}
+	if _, err := c.Reload(ctx); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Reload reads the credentials and swaps the underlying client if they changed.
+// It reports whether the client was swapped.
+func (c *ReloadingClient) Reload(ctx context.Context) (bool, error) {
+	c.reloadMu.Lock()
+	defer c.reloadMu.Unlock()
+
+	credentials, err := c.sources.Credentials(ctx)
+	if err != nil {
+		return false, err
+	}
+	hash := credentials.Hash()
+	current := c.current.Load()
+	if current != nil && current.hash == hash {
+		return false, nil
+	}
+
+	client, err := c.newClient(ctx, credentials)
+	if err != nil {
+		return false, err
+	}
+	c.current.Store(&reloadedClient{client: client, hash: hash})
+	return current != nil, nil
+}
+
+// Start reloads the credentials until the context is done. Token and certificate files are watched, and
+// every source is also read every interval, e.g. for Secret references or when file notifications are not
+// available.
+func (c *ReloadingClient) Start(ctx context.Context) error {
+	var events <-chan fsnotify.Event
+	if watcher, err := c.watchFiles(); err != nil {
+		c.cfg.Logger.Error(err, "Failed to watch credential files, falling back to polling", "interval", c.interval)
+	} else if watcher != nil {
+		defer watcher.Close() //nolint:errcheck
+		events = watcher.Events
+	}
+
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-events:
+		case <-ticker.C:
+		}
+		c.reload(ctx)
+	}
+}
+
+// NeedLeaderElection reports that every replica reloads its credentials, not only the leader.
+func (c *ReloadingClient) NeedLeaderElection() bool {
+	return false
+}
+
+func (c *ReloadingClient) reload(ctx context.Context) {
+	swapped, err := c.Reload(ctx)
+	switch {
+	case err != nil:
+		c.cfg.Logger.Error(err, "Failed to reload 1Password credentials, keeping the current client")
+		RecordCredentialsReload(CredentialsSourceDefault, err)
+	case swapped:
+		c.cfg.Logger.Info("Reloaded 1Password credentials")
+		RecordCredentialsReload(CredentialsSourceDefault, nil)
+	}
+}
+
+// watchFiles watches the directories of the token and certificate files. The kubelet replaces the files of
+// a mounted Secret by swapping a symlink, so the directory is watched rather than the file.
+func (c *ReloadingClient) watchFiles() (*fsnotify.Watcher, error) {
+	dirs := map[string]bool{}
+	for _, source := range []TokenSource{c.sources.ConnectToken, c.sources.ServiceAccountToken} {
+		if file, ok := source.(*FileTokenSource); ok {
+			dirs[filepath.Dir(file.Path)] = true
+		}
+	}
+	for _, file := range c.sources.ConnectTransport.Files() {
+		dirs[filepath.Dir(file)] = true
+	}
+	if len(dirs) == 0 {
+		return nil, nil
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	for dir := range dirs {
+		if err := watcher.Add(dir); err != nil {
+			return nil, errors.Join(err, watcher.Close())
+		}
+	}
+	return watcher, nil
+}
+
+func (c *ReloadingClient) client() Client {
+	return c.current.Load().client
+}
+
+func (c *ReloadingClient) GetItemByID(ctx context.Context, vaultID, itemID string) (*model.Item, error) {
+	return c.client().GetItemByID(ctx, vaultID, itemID)
+}
+
+func (c *ReloadingClient) GetItemsByTitle(ctx context.Context, vaultID, itemTitle string) ([]model.Item, error) {
+	return c.client().GetItemsByTitle(ctx, vaultID, itemTitle)
+}
+
+func (c *ReloadingClient) GetFileContent(ctx context.Context, vaultID, itemID, fileID string) ([]byte, error) {
+	return c.client().GetFileContent(ctx, vaultID, itemID, fileID)
+}
+
+func (c *ReloadingClient) GetVaultsByTitle(ctx context.Context, title string) ([]model.Vault, error) {
+	return c.client().GetVaultsByTitle(ctx, title)
+}
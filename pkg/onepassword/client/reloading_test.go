@@ -0,0 +1,192 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/require"
+
+	"github.com/1Password/onepassword-operator/pkg/onepassword/model"
+)
+
+// tokenClient is a Client returning items titled with the token it was built with.
+type tokenClient struct {
+	token string
+}
+
+func (c *tokenClient) GetItemByID(context.Context, string, string) (*model.Item, error) {
+	return &model.Item{Title: c.token}, nil
+}
+
+func (c *tokenClient) GetItemsByTitle(context.Context, string, string) ([]model.Item, error) {
+	return nil, nil
+}
+
+func (c *tokenClient) GetFileContent(context.Context, string, string, string) ([]byte, error) {
+	return nil, nil
+}
+
+func (c *tokenClient) GetVaultsByTitle(context.Context, string) ([]model.Vault, error) {
+	return nil, nil
+}
+
+func newTestReloadingClient(t *testing.T, sources CredentialSources, interval time.Duration) *ReloadingClient {
+	t.Helper()
+	c := &ReloadingClient{
+		cfg:      Config{Logger: logr.Discard()},
+		sources:  sources,
+		interval: interval,
+		newClient: func(_ context.Context, credentials Credentials) (Client, error) {
+			if credentials.ServiceAccountToken == "invalid" {
+				return nil, errors.New("invalid token")
+			}
+			return &tokenClient{token: credentials.ServiceAccountToken}, nil
+		},
+	}
+	_, err := c.Reload(context.Background())
+	require.NoError(t, err)
+	return c
+}
+
+func currentToken(t *testing.T, c *ReloadingClient) string {
+	t.Helper()
+	item, err := c.GetItemByID(context.Background(), "vault", "item")
+	require.NoError(t, err)
+	return item.Title
+}
+
+func writeToken(t *testing.T, path, token string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(path, []byte(token+"\n"), 0o600))
+}
+
+func TestReloadingClient_Reload(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "token")
+	writeToken(t, path, "first")
+
+	c := newTestReloadingClient(t, CredentialSources{ServiceAccountToken: &FileTokenSource{Path: path}}, time.Hour)
+	require.Equal(t, "first", currentToken(t, c))
+
+	swapped, err := c.Reload(ctx)
+	require.NoError(t, err)
+	require.False(t, swapped, "an unchanged token should not rebuild the client")
+
+	writeToken(t, path, "second")
+	swapped, err = c.Reload(ctx)
+	require.NoError(t, err)
+	require.True(t, swapped)
+	require.Equal(t, "second", currentToken(t, c))
+
+	// A failed reload keeps the current client.
+	writeToken(t, path, "invalid")
+	_, err = c.Reload(ctx)
+	require.Error(t, err)
+	require.Equal(t, "second", currentToken(t, c))
+
+	require.NoError(t, os.Remove(path))
+	_, err = c.Reload(ctx)
+	require.Error(t, err)
+	require.Equal(t, "second", currentToken(t, c))
+}
+
+func TestReloadingClient_StartWatchesTokenFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "token")
+	writeToken(t, path, "first")
+	// The interval is long enough that only the file notification can trigger the reload.
+	c := newTestReloadingClient(t, CredentialSources{ServiceAccountToken: &FileTokenSource{Path: path}}, time.Hour)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- c.Start(ctx)
+	}()
+
+	// The watcher may not be set up yet, so the token is written until it is picked up.
+	require.Eventually(t, func() bool {
+		writeToken(t, path, "rotated")
+		return currentToken(t, c) == "rotated"
+	}, 5*time.Second, 50*time.Millisecond)
+
+	cancel()
+	require.NoError(t, <-done)
+}
+
+func TestReloadingClient_ConcurrentCalls(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "token")
+	writeToken(t, path, "token-0")
+	c := newTestReloadingClient(t, CredentialSources{ServiceAccountToken: &FileTokenSource{Path: path}}, time.Hour)
+
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				item, err := c.GetItemByID(ctx, "vault", "item")
+				if err != nil || item.Title == "" {
+					t.Errorf("unexpected result during reload: %v, %v", item, err)
+					return
+				}
+			}
+		}()
+	}
+
+	for _, token := range []string{"token-1", "token-2", "token-3"} {
+		writeToken(t, path, token)
+		_, err := c.Reload(ctx)
+		require.NoError(t, err)
+	}
+	close(stop)
+	wg.Wait()
+	require.Equal(t, "token-3", currentToken(t, c))
+}
+
+func TestReloadingClient_ReloadsConnectCertificates(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	caFile := filepath.Join(dir, "ca.crt")
+	writeToken(t, caFile, "first-ca")
+
+	var built []Credentials
+	c := &ReloadingClient{
+		cfg: Config{Logger: logr.Discard()},
+		sources: CredentialSources{
+			ConnectHost:      "https://connect:8443",
+			ConnectToken:     StaticToken("token"),
+			ConnectTransport: ConnectTransportSource{CAFile: caFile, Timeout: time.Second},
+		},
+		interval: time.Hour,
+		newClient: func(_ context.Context, credentials Credentials) (Client, error) {
+			built = append(built, credentials)
+			return &tokenClient{token: credentials.ConnectToken}, nil
+		},
+	}
+	_, err := c.Reload(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "first-ca\n", string(built[0].ConnectTransport.CABundle))
+	require.Equal(t, time.Second, built[0].ConnectTransport.Timeout)
+
+	writeToken(t, caFile, "renewed-ca")
+	swapped, err := c.Reload(ctx)
+	require.NoError(t, err)
+	require.True(t, swapped, "a renewed CA bundle should rebuild the client")
+	require.Equal(t, "renewed-ca\n", string(built[1].ConnectTransport.CABundle))
+
+	require.NoError(t, os.Remove(caFile))
+	_, err = c.Reload(ctx)
+	require.Error(t, err)
+}
@@ -0,0 +1,91 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	sdk "github.com/1password/onepassword-sdk-go"
+)
+
+// ErrRequest is matched by the errors of calls 1Password rejected, e.g. for a vault or item that does not
+// exist or that the service account can't access, as opposed to failures reaching 1Password.
+var ErrRequest = errors.New("1Password rejected the request")
+
+// The SDK only types rate limit errors, as *sdk.RateLimitExceededError. Every other error of the SDK core,
+// including cancelled calls, is returned as errors.New of its message, without a type or status code to
+// match, so these errors are classified by their message.
+var (
+	requestErrorMessages = []string{
+		"not found",
+		"does not exist",
+		"no access",
+		"not have access",
+		"not authorized",
+		"permission denied",
+	}
+	unreachableErrorMessages = []string{
+		"error sending request",
+		"connection refused",
+		"connection reset",
+		"timed out",
+		"timeout",
+		"dns error",
+		"internal server error",
+		"bad gateway",
+		"service unavailable",
+		"gateway timeout",
+	}
+)
+
+// requestError is an SDK error rejecting the request. It keeps the message of the SDK error.
+type requestError struct {
+	err error
+}
+
+func (e *requestError) Error() string {
+	return e.err.Error()
+}
+
+func (e *requestError) Unwrap() error {
+	return e.err
+}
+
+func (e *requestError) Is(target error) bool {
+	return target == ErrRequest
+}
+
+// classifyError makes the SDK errors of calls cancelled by ctx match the error of ctx, which the SDK drops,
+// and the SDK errors rejecting the request match ErrRequest.
+func classifyError(ctx context.Context, err error) error {
+	switch {
+	case ctx.Err() != nil && !errors.Is(err, ctx.Err()):
+		return fmt.Errorf("%w: %w", ctx.Err(), err)
+	case IsRateLimited(err) || !containsAny(err, requestErrorMessages):
+		return err
+	}
+	return &requestError{err: err}
+}
+
+// IsRateLimited reports whether the SDK rejected the call because the rate limit was exceeded.
+func IsRateLimited(err error) bool {
+	var rateLimitErr *sdk.RateLimitExceededError
+	return errors.As(err, &rateLimitErr)
+}
+
+// IsUnreachable reports whether the SDK error means 1Password could not be reached, so retrying the call
+// may succeed.
+func IsUnreachable(err error) bool {
+	return !errors.Is(err, ErrRequest) && containsAny(err, unreachableErrorMessages)
+}
+
+func containsAny(err error, messages []string) bool {
+	message := strings.ToLower(err.Error())
+	for _, m := range messages {
+		if strings.Contains(message, m) {
+			return true
+		}
+	}
+	return false
+}
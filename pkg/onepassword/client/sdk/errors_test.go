@@ -0,0 +1,87 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	clientmock "github.com/1Password/onepassword-operator/pkg/onepassword/client/testing/mock"
+	sdk "github.com/1password/onepassword-sdk-go"
+)
+
+// coreError returns the error the SDK returns for an error of its core, which it builds with errors.New of
+// the message of the core error.
+func coreError(message string) error {
+	return errors.New(message)
+}
+
+func TestSDK_ClassifiesErrors(t *testing.T) {
+	testCases := map[string]struct {
+		err         error
+		request     bool
+		rateLimited bool
+		unreachable bool
+	}{
+		"vault not found": {
+			err:     coreError("error resolving vault: vault not found"),
+			request: true,
+		},
+		"no access": {
+			err:     coreError("service account has no access to the vault"),
+			request: true,
+		},
+		"rate limit": {
+			err:         &sdk.RateLimitExceededError{},
+			rateLimited: true,
+		},
+		"unreachable": {
+			err:         coreError("error sending request for url (https://my.1password.com/api/v2/vault)"),
+			unreachable: true,
+		},
+	}
+
+	for description, tc := range testCases {
+		t.Run(description, func(t *testing.T) {
+			m := &clientmock.ItemAPIMock{}
+			m.On("Get", context.Background(), "vault-id", "item-id").Return(sdk.Item{}, tc.err)
+			client := &SDK{client: &sdk.Client{ItemsAPI: m}}
+
+			_, err := client.GetItemByID(context.Background(), "vault-id", "item-id")
+			require.ErrorIs(t, err, tc.err)
+			require.Equal(t, tc.request, errors.Is(err, ErrRequest))
+			require.Equal(t, tc.rateLimited, IsRateLimited(err))
+			require.Equal(t, tc.unreachable, IsUnreachable(err))
+		})
+	}
+}
+
+func TestSDK_ClassifiesCancelledCalls(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	// The SDK core reports the cancellation as a message, without wrapping the error of the context.
+	sdkErr := coreError("error sending request for url (https://my.1password.com): operation was canceled")
+	m := &clientmock.ItemAPIMock{}
+	m.On("Get", ctx, "vault-id", "item-id").Return(sdk.Item{}, sdkErr)
+	client := &SDK{client: &sdk.Client{ItemsAPI: m}}
+
+	_, err := client.GetItemByID(ctx, "vault-id", "item-id")
+	require.ErrorIs(t, err, context.Canceled)
+	require.ErrorIs(t, err, sdkErr)
+}
+
+func TestNewClient_InvalidToken(t *testing.T) {
+	if testing.Short() {
+		t.Skip("loading the SDK core is slow")
+	}
+
+	_, err := NewClient(context.Background(), Config{
+		ServiceAccountToken: "invalid",
+		IntegrationName:     "1Password Kubernetes Operator",
+		IntegrationVersion:  "test",
+	})
+	require.ErrorContains(t, err, "service account token had invalid format")
+	require.False(t, IsRateLimited(err))
+	require.False(t, IsUnreachable(err), "an invalid token should not be retried")
+}
@@ -27,7 +27,7 @@ func NewClient(ctx context.Context, config Config) (*SDK, error) {
 		sdk.WithIntegrationInfo(config.IntegrationName, config.IntegrationVersion),
 	)
 	if err != nil {
-		return nil, fmt.Errorf("1Password sdk error: %w", err)
+		return nil, fmt.Errorf("1Password sdk error: %w", classifyError(ctx, err))
 	}
 
 	return &SDK{
@@ -38,7 +38,7 @@ func NewClient(ctx context.Context, config Config) (*SDK, error) {
 func (s *SDK) GetItemByID(ctx context.Context, vaultID, itemID string) (*model.Item, error) {
 	sdkItem, err := s.client.Items().Get(ctx, vaultID, itemID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to GetItemsByTitle using 1Password SDK: %w", err)
+		return nil, fmt.Errorf("failed to GetItemsByTitle using 1Password SDK: %w", classifyError(ctx, err))
 	}
 
 	var item model.Item
@@ -50,7 +50,7 @@ func (s *SDK) GetItemsByTitle(ctx context.Context, vaultID, itemTitle string) ([
 	// Get all items in the vault
 	sdkItems, err := s.client.Items().List(ctx, vaultID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to GetItemsByTitle using 1Password SDK: %w", err)
+		return nil, fmt.Errorf("failed to GetItemsByTitle using 1Password SDK: %w", classifyError(ctx, err))
 	}
 
 	// Filter items by title
@@ -71,7 +71,7 @@ func (s *SDK) GetFileContent(ctx context.Context, vaultID, itemID, fileID string
 		ID: fileID,
 	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to GetFileContent using 1Password SDK: %w", err)
+		return nil, fmt.Errorf("failed to GetFileContent using 1Password SDK: %w", classifyError(ctx, err))
 	}
 
 	return bytes, nil
@@ -81,7 +81,7 @@ func (s *SDK) GetVaultsByTitle(ctx context.Context, title string) ([]model.Vault
 	// List all vaults
 	sdkVaults, err := s.client.Vaults().List(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to GetVaultsByTitle using 1Password SDK: %w", err)
+		return nil, fmt.Errorf("failed to GetVaultsByTitle using 1Password SDK: %w", classifyError(ctx, err))
 	}
 
 	// Filter vaults by title
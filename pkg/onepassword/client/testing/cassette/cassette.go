@@ -0,0 +1,123 @@
+package cassette
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/1Password/connect-sdk-go/onepassword"
+
+	"github.com/1Password/onepassword-operator/pkg/onepassword/model"
+)
+
+// Redacted replaces the field values and file contents recorded in a cassette.
+const Redacted = "REDACTED"
+
+// Cassette holds the interactions of a client with 1Password, in the order they happened.
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is a call of a client.Client method and its result.
+type Interaction struct {
+	// Operation is the name of the method, e.g. "GetItemByID".
+	Operation string   `json:"operation"`
+	Args      []string `json:"args"`
+
+	Item    *model.Item   `json:"item,omitempty"`
+	Items   []model.Item  `json:"items,omitempty"`
+	Vaults  []model.Vault `json:"vaults,omitempty"`
+	Content []byte        `json:"content,omitempty"`
+	Error   *Error        `json:"error,omitempty"`
+}
+
+// Error is a recorded error. Errors with a status code are replayed as *onepassword.Error, like the
+// errors of the Connect API, so they are handled the same way.
+type Error struct {
+	Message    string `json:"message"`
+	StatusCode int    `json:"statusCode,omitempty"`
+}
+
+// Load reads a cassette from a JSON file.
+func Load(path string) (*Cassette, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cassette: %w", err)
+	}
+	var cassette Cassette
+	if err := json.Unmarshal(data, &cassette); err != nil {
+		return nil, fmt.Errorf("invalid cassette %q: %w", path, err)
+	}
+	return &cassette, nil
+}
+
+// Save writes the cassette to a JSON file.
+func (c *Cassette) Save(path string) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o600)
+}
+
+func newError(err error) *Error {
+	if err == nil {
+		return nil
+	}
+	recorded := &Error{Message: err.Error()}
+	var connectErr *onepassword.Error
+	if errors.As(err, &connectErr) {
+		recorded.StatusCode = connectErr.StatusCode
+	}
+	return recorded
+}
+
+func (e *Error) err() error {
+	if e == nil {
+		return nil
+	}
+	if e.StatusCode != 0 {
+		return &onepassword.Error{StatusCode: e.StatusCode, Message: e.Message}
+	}
+	return errors.New(e.Message)
+}
+
+// copyItem returns a copy of the item sharing no slices with it. The copied files have no content.
+func copyItem(item *model.Item) *model.Item {
+	if item == nil {
+		return nil
+	}
+	copied := *item
+	copied.Tags = append([]string(nil), item.Tags...)
+	copied.URLs = append([]model.ItemURL(nil), item.URLs...)
+	copied.Sections = append([]model.ItemSection(nil), item.Sections...)
+	copied.Fields = append([]model.ItemField(nil), item.Fields...)
+	copied.Files = nil
+	for _, file := range item.Files {
+		copied.Files = append(copied.Files, model.File{
+			ID:          file.ID,
+			Name:        file.Name,
+			Size:        file.Size,
+			ContentPath: file.ContentPath,
+		})
+	}
+	return &copied
+}
+
+// redactItem returns a copy of the item without its field values.
+func redactItem(item *model.Item) *model.Item {
+	redacted := copyItem(item)
+	if redacted == nil {
+		return nil
+	}
+	for i, field := range redacted.Fields {
+		if field.Value != "" {
+			redacted.Fields[i].Value = Redacted
+		}
+		if field.Address != nil {
+			redacted.Fields[i].Address = &model.ItemAddress{Street: Redacted}
+		}
+	}
+	return redacted
+}
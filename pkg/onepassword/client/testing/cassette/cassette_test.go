@@ -0,0 +1,116 @@
+package cassette
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/1Password/connect-sdk-go/onepassword"
+
+	"github.com/1Password/onepassword-operator/pkg/mocks"
+	"github.com/1Password/onepassword-operator/pkg/onepassword/model"
+)
+
+func TestRecordAndReplay(t *testing.T) {
+	ctx := context.Background()
+	item := &model.Item{
+		ID:      "item-id",
+		VaultID: "vault-id",
+		Title:   "database",
+		Version: 3,
+		Fields: []model.ItemField{
+			{Label: "username", Value: "admin"},
+			{Label: "password", Value: "s3cr3t"},
+		},
+		Files: []model.File{{ID: "file-id", Name: "ca.crt", Size: 11}},
+	}
+	updated := &model.Item{ID: "item-id", VaultID: "vault-id", Version: 4}
+
+	inner := &mocks.TestClient{}
+	inner.On("GetVaultsByTitle", "Production").Return([]model.Vault{{ID: "vault-id"}}, nil)
+	inner.On("GetItemByID", "vault-id", "item-id").Return(item, nil).Once()
+	inner.On("GetItemByID", "vault-id", "item-id").Return(updated, nil).Once()
+	inner.On("GetItemByID", "vault-id", "missing").
+		Return(nil, &onepassword.Error{StatusCode: http.StatusNotFound, Message: "item not found"})
+	inner.On("GetFileContent", "vault-id", "item-id", "file-id").Return([]byte("certificate"), nil)
+
+	recorder := NewRecorder(inner)
+	_, err := recorder.GetVaultsByTitle(ctx, "Production")
+	require.NoError(t, err)
+	recorded, err := recorder.GetItemByID(ctx, "vault-id", "item-id")
+	require.NoError(t, err)
+	require.Equal(t, "s3cr3t", recorded.Fields[1].Value, "the caller should get the values")
+	_, err = recorder.GetItemByID(ctx, "vault-id", "item-id")
+	require.NoError(t, err)
+	_, err = recorder.GetItemByID(ctx, "vault-id", "missing")
+	require.Error(t, err)
+	content, err := recorder.GetFileContent(ctx, "vault-id", "item-id", "file-id")
+	require.NoError(t, err)
+	require.Equal(t, "certificate", string(content))
+
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	require.NoError(t, recorder.Save(path))
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.NotContains(t, string(data), "s3cr3t")
+	require.NotContains(t, string(data), "admin")
+	require.NotContains(t, string(data), "Y2VydGlmaWNhdGU", "the file content should be redacted")
+
+	replayer, err := NewReplayerFromFile(path)
+	require.NoError(t, err)
+
+	vaults, err := replayer.GetVaultsByTitle(ctx, "Production")
+	require.NoError(t, err)
+	require.Equal(t, []model.Vault{{ID: "vault-id"}}, vaults)
+
+	replayed, err := replayer.GetItemByID(ctx, "vault-id", "item-id")
+	require.NoError(t, err)
+	require.Equal(t, 3, replayed.Version)
+	require.Equal(t, "username", replayed.Fields[0].Label)
+	require.Equal(t, Redacted, replayed.Fields[0].Value)
+	require.Equal(t, "ca.crt", replayed.Files[0].Name)
+
+	for range 2 {
+		replayed, err = replayer.GetItemByID(ctx, "vault-id", "item-id")
+		require.NoError(t, err)
+		require.Equal(t, 4, replayed.Version, "the last interaction should be repeated")
+	}
+
+	_, err = replayer.GetItemByID(ctx, "vault-id", "missing")
+	var connectErr *onepassword.Error
+	require.True(t, errors.As(err, &connectErr))
+	require.Equal(t, http.StatusNotFound, connectErr.StatusCode)
+
+	content, err = replayer.GetFileContent(ctx, "vault-id", "item-id", "file-id")
+	require.NoError(t, err)
+	require.Equal(t, Redacted, string(content))
+
+	_, err = replayer.GetItemsByTitle(ctx, "vault-id", "database")
+	require.ErrorIs(t, err, ErrNoInteraction)
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = replayer.GetVaultsByTitle(cancelled, "Production")
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+func TestReplayer_ItemsAreCopied(t *testing.T) {
+	replayer := NewReplayer(&Cassette{Interactions: []Interaction{{
+		Operation: "GetItemByID",
+		Args:      []string{"vault-id", "item-id"},
+		Item:      &model.Item{ID: "item-id", Fields: []model.ItemField{{Label: "password", Value: "secret"}}},
+	}}})
+
+	item, err := replayer.GetItemByID(context.Background(), "vault-id", "item-id")
+	require.NoError(t, err)
+	item.Fields[0].Value = "changed"
+
+	item, err = replayer.GetItemByID(context.Background(), "vault-id", "item-id")
+	require.NoError(t, err)
+	require.Equal(t, "secret", item.Fields[0].Value)
+}
@@ -0,0 +1,95 @@
+package cassette
+
+import (
+	"context"
+	"sync"
+
+	"github.com/1Password/onepassword-operator/pkg/onepassword/client"
+	"github.com/1Password/onepassword-operator/pkg/onepassword/model"
+)
+
+// Recorder is a client.Client recording the calls of a client, e.g. one reaching a real 1Password
+// account, to a cassette. Field values and file contents are redacted, so the cassette can be shared
+// to reproduce an issue without sharing secrets. The results returned to the caller are not redacted.
+type Recorder struct {
+	client client.Client
+
+	mu       sync.Mutex
+	cassette Cassette
+}
+
+var _ client.Client = (*Recorder)(nil)
+
+// NewRecorder creates a Recorder recording the calls of the client.
+func NewRecorder(c client.Client) *Recorder {
+	return &Recorder{client: c}
+}
+
+// Cassette returns the interactions recorded so far.
+func (r *Recorder) Cassette() *Cassette {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return &Cassette{Interactions: append([]Interaction(nil), r.cassette.Interactions...)}
+}
+
+// Save writes the interactions recorded so far to a JSON file.
+func (r *Recorder) Save(path string) error {
+	return r.Cassette().Save(path)
+}
+
+func (r *Recorder) GetItemByID(ctx context.Context, vaultID, itemID string) (*model.Item, error) {
+	item, err := r.client.GetItemByID(ctx, vaultID, itemID)
+	r.record(Interaction{
+		Operation: "GetItemByID",
+		Args:      []string{vaultID, itemID},
+		Item:      redactItem(item),
+		Error:     newError(err),
+	})
+	return item, err
+}
+
+func (r *Recorder) GetItemsByTitle(ctx context.Context, vaultID, itemTitle string) ([]model.Item, error) {
+	items, err := r.client.GetItemsByTitle(ctx, vaultID, itemTitle)
+	redacted := make([]model.Item, len(items))
+	for i := range items {
+		redacted[i] = *redactItem(&items[i])
+	}
+	r.record(Interaction{
+		Operation: "GetItemsByTitle",
+		Args:      []string{vaultID, itemTitle},
+		Items:     redacted,
+		Error:     newError(err),
+	})
+	return items, err
+}
+
+func (r *Recorder) GetFileContent(ctx context.Context, vaultID, itemID, fileID string) ([]byte, error) {
+	content, err := r.client.GetFileContent(ctx, vaultID, itemID, fileID)
+	interaction := Interaction{
+		Operation: "GetFileContent",
+		Args:      []string{vaultID, itemID, fileID},
+		Error:     newError(err),
+	}
+	if err == nil {
+		interaction.Content = []byte(Redacted)
+	}
+	r.record(interaction)
+	return content, err
+}
+
+func (r *Recorder) GetVaultsByTitle(ctx context.Context, title string) ([]model.Vault, error) {
+	vaults, err := r.client.GetVaultsByTitle(ctx, title)
+	r.record(Interaction{
+		Operation: "GetVaultsByTitle",
+		Args:      []string{title},
+		Vaults:    append([]model.Vault(nil), vaults...),
+		Error:     newError(err),
+	})
+	return vaults, err
+}
+
+func (r *Recorder) record(interaction Interaction) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cassette.Interactions = append(r.cassette.Interactions, interaction)
+}
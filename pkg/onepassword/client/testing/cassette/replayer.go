@@ -0,0 +1,107 @@
+package cassette
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/1Password/onepassword-operator/pkg/onepassword/client"
+	"github.com/1Password/onepassword-operator/pkg/onepassword/model"
+)
+
+// ErrNoInteraction is returned for a call that was not recorded in the cassette.
+var ErrNoInteraction = errors.New("no recorded interaction")
+
+// Replayer is a client.Client answering calls with the interactions of a cassette. The interactions of
+// a call with the same operation and arguments are replayed in the order they were recorded, and the
+// last one is repeated once they are used up, so polling keeps getting the latest result.
+type Replayer struct {
+	mu           sync.Mutex
+	interactions map[string][]Interaction
+	served       map[string]int
+}
+
+var _ client.Client = (*Replayer)(nil)
+
+// NewReplayer creates a Replayer of the cassette.
+func NewReplayer(cassette *Cassette) *Replayer {
+	r := &Replayer{
+		interactions: map[string][]Interaction{},
+		served:       map[string]int{},
+	}
+	for _, interaction := range cassette.Interactions {
+		key := interactionKey(interaction.Operation, interaction.Args...)
+		r.interactions[key] = append(r.interactions[key], interaction)
+	}
+	return r
+}
+
+// NewReplayerFromFile creates a Replayer of the cassette saved in the JSON file.
+func NewReplayerFromFile(path string) (*Replayer, error) {
+	cassette, err := Load(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewReplayer(cassette), nil
+}
+
+func (r *Replayer) GetItemByID(ctx context.Context, vaultID, itemID string) (*model.Item, error) {
+	interaction, err := r.next(ctx, "GetItemByID", vaultID, itemID)
+	if err != nil {
+		return nil, err
+	}
+	return copyItem(interaction.Item), interaction.Error.err()
+}
+
+func (r *Replayer) GetItemsByTitle(ctx context.Context, vaultID, itemTitle string) ([]model.Item, error) {
+	interaction, err := r.next(ctx, "GetItemsByTitle", vaultID, itemTitle)
+	if err != nil {
+		return nil, err
+	}
+	var items []model.Item
+	for i := range interaction.Items {
+		items = append(items, *copyItem(&interaction.Items[i]))
+	}
+	return items, interaction.Error.err()
+}
+
+func (r *Replayer) GetFileContent(ctx context.Context, vaultID, itemID, fileID string) ([]byte, error) {
+	interaction, err := r.next(ctx, "GetFileContent", vaultID, itemID, fileID)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(nil), interaction.Content...), interaction.Error.err()
+}
+
+func (r *Replayer) GetVaultsByTitle(ctx context.Context, title string) ([]model.Vault, error) {
+	interaction, err := r.next(ctx, "GetVaultsByTitle", title)
+	if err != nil {
+		return nil, err
+	}
+	return append([]model.Vault(nil), interaction.Vaults...), interaction.Error.err()
+}
+
+// next returns the interaction answering the call. A done ctx fails the call, like it fails a request.
+func (r *Replayer) next(ctx context.Context, operation string, args ...string) (Interaction, error) {
+	if err := ctx.Err(); err != nil {
+		return Interaction{}, err
+	}
+	key := interactionKey(operation, args...)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	interactions := r.interactions[key]
+	if len(interactions) == 0 {
+		return Interaction{}, fmt.Errorf("%s(%s): %w", operation, strings.Join(args, ", "), ErrNoInteraction)
+	}
+	i := r.served[key]
+	if i < len(interactions)-1 {
+		r.served[key]++
+	}
+	return interactions[i], nil
+}
+
+func interactionKey(operation string, args ...string) string {
+	return operation + "\x00" + strings.Join(args, "\x00")
+}
@@ -0,0 +1,152 @@
+package chaos
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/1Password/connect-sdk-go/onepassword"
+
+	"github.com/1Password/onepassword-operator/pkg/onepassword/client"
+	"github.com/1Password/onepassword-operator/pkg/onepassword/model"
+)
+
+// Fault changes the result of the calls it matches.
+type Fault struct {
+	// Operation limits the fault to a client.Client method, e.g. "GetFileContent". Empty matches every call.
+	Operation string
+	// Latency delays the call. The call fails early when ctx is done.
+	Latency time.Duration
+	// Err fails the call without calling the wrapped client. Nil calls it after the latency.
+	Err error
+	// Skip is the number of matching calls to let through before the fault applies, e.g. to fail
+	// in the middle of a reconciliation.
+	Skip int
+	// Times is the number of calls the fault applies to. Zero applies it until Clear is called.
+	Times int
+}
+
+// Client is a client.Client injecting faults into the calls of a client.
+type Client struct {
+	client client.Client
+
+	mu     sync.Mutex
+	faults []*Fault
+}
+
+var _ client.Client = (*Client)(nil)
+
+// New creates a Client injecting the faults into the calls of the client.
+func New(c client.Client, faults ...Fault) *Client {
+	chaosClient := &Client{client: c}
+	for _, fault := range faults {
+		chaosClient.Inject(fault)
+	}
+	return chaosClient
+}
+
+// Inject adds the fault. Every fault matching a call applies in the order they were added: their
+// latencies add up and the first one with an error fails the call.
+func (c *Client) Inject(fault Fault) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.faults = append(c.faults, &fault)
+}
+
+// Clear removes all faults.
+func (c *Client) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.faults = nil
+}
+
+// RateLimitError returns the error of 1Password Connect when the rate limit is exceeded.
+func RateLimitError() error {
+	return &onepassword.Error{StatusCode: http.StatusTooManyRequests, Message: "rate limit exceeded"}
+}
+
+// ServerError returns the error of 1Password Connect when it fails, e.g. when a file is not synchronized yet.
+func ServerError() error {
+	return &onepassword.Error{StatusCode: http.StatusInternalServerError, Message: "internal server error"}
+}
+
+func (c *Client) GetItemByID(ctx context.Context, vaultID, itemID string) (*model.Item, error) {
+	if err := c.apply(ctx, "GetItemByID"); err != nil {
+		return nil, err
+	}
+	return c.client.GetItemByID(ctx, vaultID, itemID)
+}
+
+func (c *Client) GetItemsByTitle(ctx context.Context, vaultID, itemTitle string) ([]model.Item, error) {
+	if err := c.apply(ctx, "GetItemsByTitle"); err != nil {
+		return nil, err
+	}
+	return c.client.GetItemsByTitle(ctx, vaultID, itemTitle)
+}
+
+func (c *Client) GetFileContent(ctx context.Context, vaultID, itemID, fileID string) ([]byte, error) {
+	if err := c.apply(ctx, "GetFileContent"); err != nil {
+		return nil, err
+	}
+	return c.client.GetFileContent(ctx, vaultID, itemID, fileID)
+}
+
+func (c *Client) GetVaultsByTitle(ctx context.Context, title string) ([]model.Vault, error) {
+	if err := c.apply(ctx, "GetVaultsByTitle"); err != nil {
+		return nil, err
+	}
+	return c.client.GetVaultsByTitle(ctx, title)
+}
+
+// apply applies the faults matching the call and returns the error failing it, if any.
+func (c *Client) apply(ctx context.Context, operation string) error {
+	var latency time.Duration
+	var err error
+	for _, fault := range c.matching(operation) {
+		latency += fault.Latency
+		if err == nil {
+			err = fault.Err
+		}
+	}
+
+	if latency > 0 {
+		timer := time.NewTimer(latency)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+	return err
+}
+
+// matching returns the faults applying to the call and consumes them.
+func (c *Client) matching(operation string) []Fault {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var matching []Fault
+	remaining := c.faults[:0]
+	for _, fault := range c.faults {
+		if fault.Operation != "" && fault.Operation != operation {
+			remaining = append(remaining, fault)
+			continue
+		}
+		if fault.Skip > 0 {
+			fault.Skip--
+			remaining = append(remaining, fault)
+			continue
+		}
+		matching = append(matching, *fault)
+		if fault.Times > 0 {
+			fault.Times--
+			if fault.Times == 0 {
+				continue
+			}
+		}
+		remaining = append(remaining, fault)
+	}
+	c.faults = remaining
+	return matching
+}
@@ -0,0 +1,70 @@
+package chaos
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/1Password/connect-sdk-go/onepassword"
+
+	"github.com/1Password/onepassword-operator/pkg/mocks"
+	"github.com/1Password/onepassword-operator/pkg/onepassword/model"
+)
+
+func newInnerClient() *mocks.TestClient {
+	inner := &mocks.TestClient{}
+	inner.On("GetItemByID", "vault-id", "item-id").Return(&model.Item{ID: "item-id"}, nil)
+	inner.On("GetVaultsByTitle", "Production").Return([]model.Vault{{ID: "vault-id"}}, nil)
+	return inner
+}
+
+func TestClient_Errors(t *testing.T) {
+	ctx := context.Background()
+	inner := newInnerClient()
+	client := New(inner, Fault{Operation: "GetItemByID", Err: RateLimitError(), Skip: 1, Times: 2})
+
+	_, err := client.GetItemByID(ctx, "vault-id", "item-id")
+	require.NoError(t, err, "the first call should be let through")
+
+	for range 2 {
+		_, err = client.GetItemByID(ctx, "vault-id", "item-id")
+		var connectErr *onepassword.Error
+		require.True(t, errors.As(err, &connectErr))
+		require.Equal(t, http.StatusTooManyRequests, connectErr.StatusCode)
+		require.Contains(t, err.Error(), "rate limit")
+	}
+
+	_, err = client.GetItemByID(ctx, "vault-id", "item-id")
+	require.NoError(t, err)
+	_, err = client.GetVaultsByTitle(ctx, "Production")
+	require.NoError(t, err, "the fault should only apply to its operation")
+	inner.AssertNumberOfCalls(t, "GetItemByID", 2)
+}
+
+func TestClient_Latency(t *testing.T) {
+	client := New(newInnerClient())
+	client.Inject(Fault{Latency: 20 * time.Millisecond})
+	client.Inject(Fault{Operation: "GetVaultsByTitle", Err: ServerError()})
+
+	start := time.Now()
+	_, err := client.GetItemByID(context.Background(), "vault-id", "item-id")
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, time.Since(start), 20*time.Millisecond)
+
+	_, err = client.GetVaultsByTitle(context.Background(), "Production")
+	require.ErrorContains(t, err, "internal server error")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	client.Inject(Fault{Latency: time.Minute})
+	_, err = client.GetItemByID(ctx, "vault-id", "item-id")
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	client.Clear()
+	_, err = client.GetVaultsByTitle(context.Background(), "Production")
+	require.NoError(t, err)
+}
@@ -0,0 +1,382 @@
+package onepassword
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	onepasswordv1 "github.com/1Password/onepassword-operator/api/v1"
+	opclient "github.com/1Password/onepassword-operator/pkg/onepassword/client"
+	"github.com/1Password/onepassword-operator/pkg/onepassword/client/connect"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	toolscache "k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+var logConnections = logf.Log.WithName("connections")
+
+// errNoDefaultClient is returned for resources without a connection when the operator was started
+// without credentials.
+var errNoDefaultClient = errors.New(
+	"no connection is selected and the operator has no default 1Password credentials",
+)
+
+// NewClientFunc creates a 1Password client for the credentials of a connection.
+type NewClientFunc func(ctx context.Context, credentials opclient.Credentials) (opclient.Client, error)
+
+// connectionClientTimeout bounds the creation of the client of a connection, e.g. the authentication
+// of a Service Account, so a backend that does not answer can't block the reconciles selecting it.
+const connectionClientTimeout = 30 * time.Second
+
+// ConnectionClients builds and caches a 1Password client per OnePasswordConnection and
+// ClusterOnePasswordConnection. A client is rebuilt when its connection or token Secret changes.
+type ConnectionClients struct {
+	kubeClient client.Reader
+	newClient  NewClientFunc
+
+	// mu guards clients. Each client is built under the lock of its own entry, so a slow connection
+	// only blocks the resources selecting it.
+	mu      sync.Mutex
+	clients map[string]*connectionClient
+}
+
+type connectionClient struct {
+	mu sync.Mutex
+	// hash identifies the connection spec and token the client was built with.
+	hash   string
+	client opclient.Client
+}
+
+// NewConnectionClients returns a ConnectionClients reading connections and their token Secrets with kubeClient,
+// which should be backed by a cache. The token Secret of a ClusterOnePasswordConnection may be outside the
+// watched namespaces, so kubeClient should not be restricted to them.
+func NewConnectionClients(kubeClient client.Reader, newClient NewClientFunc) *ConnectionClients {
+	return &ConnectionClients{
+		kubeClient: kubeClient,
+		newClient:  newClient,
+		clients:    map[string]*connectionClient{},
+	}
+}
+
+// EvictDeletedConnections drops the cached client of a connection once the connection is deleted.
+func (c *ConnectionClients) EvictDeletedConnections(ctx context.Context, informers cache.Informers) error {
+	for _, obj := range []client.Object{
+		&onepasswordv1.OnePasswordConnection{},
+		&onepasswordv1.ClusterOnePasswordConnection{},
+	} {
+		informer, err := informers.GetInformer(ctx, obj)
+		if err != nil {
+			return fmt.Errorf("failed to watch connections: %w", err)
+		}
+		if _, err := informer.AddEventHandler(toolscache.ResourceEventHandlerFuncs{DeleteFunc: c.evict}); err != nil {
+			return fmt.Errorf("failed to watch connections: %w", err)
+		}
+	}
+	return nil
+}
+
+func (c *ConnectionClients) evict(obj interface{}) {
+	if tombstone, ok := obj.(toolscache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	var key string
+	switch connection := obj.(type) {
+	case *onepasswordv1.OnePasswordConnection:
+		key = connectionCacheKey(connection.Namespace, &onepasswordv1.ConnectionReference{
+			Kind: onepasswordv1.OnePasswordConnectionKind, Name: connection.Name,
+		})
+	case *onepasswordv1.ClusterOnePasswordConnection:
+		key = connectionCacheKey("", &onepasswordv1.ConnectionReference{
+			Kind: onepasswordv1.ClusterOnePasswordConnectionKind, Name: connection.Name,
+		})
+	default:
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.clients[key]; ok {
+		delete(c.clients, key)
+		logConnections.Info("Dropped the 1Password client of a deleted connection", "connection", key)
+	}
+}
+
+// connectionCacheKey identifies the client of the connection referenced from the namespace.
+func connectionCacheKey(namespace string, ref *onepasswordv1.ConnectionReference) string {
+	if ref.KindOrDefault() == onepasswordv1.ClusterOnePasswordConnectionKind {
+		return ref.String()
+	}
+	return namespace + "/" + ref.String()
+}
+
+// entry returns the cache entry of the key, creating it if needed.
+func (c *ConnectionClients) entry(key string) *connectionClient {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.clients[key]
+	if !ok {
+		entry = &connectionClient{}
+		c.clients[key] = entry
+	}
+	return entry
+}
+
+// ClientFor returns the client of the referenced connection.
+//
+// Namespace is the namespace of the referencing resource; cluster-scoped resources pass an empty
+// namespace and can only reference a ClusterOnePasswordConnection. A namespaced resource can only
+// reference a ClusterOnePasswordConnection that allows its namespace.
+func (c *ConnectionClients) ClientFor(
+	ctx context.Context,
+	namespace string,
+	ref *onepasswordv1.ConnectionReference,
+) (opclient.Client, error) {
+	spec, secretNamespace, err := c.getConnectionSpec(ctx, namespace, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := c.getSecretValue(ctx, secretNamespace, spec.TokenSecretRef.Name, spec.TokenSecretRef.Key)
+	if err != nil {
+		return nil, fmt.Errorf("connection %s: token: %w", ref, err)
+	}
+
+	credentials := opclient.Credentials{}
+	switch spec.Backend {
+	case onepasswordv1.ConnectionBackendConnect:
+		credentials.ConnectHost = spec.Host
+		credentials.ConnectToken = string(token)
+		if credentials.ConnectTransport, err = c.getConnectTransport(ctx, ref, spec.Connect, secretNamespace); err != nil {
+			return nil, fmt.Errorf("connection %s: %w", ref, err)
+		}
+	case onepasswordv1.ConnectionBackendServiceAccount:
+		credentials.ServiceAccountToken = string(token)
+	default:
+		return nil, fmt.Errorf("connection %s: unsupported backend %q", ref, spec.Backend)
+	}
+
+	hash := credentials.Hash()
+	entry := c.entry(connectionCacheKey(namespace, ref))
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+	if entry.client != nil && entry.hash == hash {
+		return entry.client, nil
+	}
+
+	rebuild := entry.client != nil
+	buildCtx, cancel := context.WithTimeout(ctx, connectionClientTimeout)
+	defer cancel()
+	opClient, err := c.newClient(buildCtx, credentials)
+	if rebuild {
+		opclient.RecordCredentialsReload(opclient.CredentialsSourceConnection, err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("connection %s: failed to create 1Password client: %w", ref, err)
+	}
+	if rebuild {
+		logConnections.Info("Rebuilt 1Password client after its connection changed", "connection", ref.String())
+	}
+	entry.hash, entry.client = hash, opClient
+	return opClient, nil
+}
+
+// getConnectionSpec returns the spec of the referenced connection and the namespace of its token Secret.
+func (c *ConnectionClients) getConnectionSpec(
+	ctx context.Context,
+	namespace string,
+	ref *onepasswordv1.ConnectionReference,
+) (*onepasswordv1.OnePasswordConnectionSpec, string, error) {
+	switch ref.KindOrDefault() {
+	case onepasswordv1.OnePasswordConnectionKind:
+		if namespace == "" {
+			return nil, "", fmt.Errorf("connection %s: cluster-scoped resources can only reference a %s",
+				ref, onepasswordv1.ClusterOnePasswordConnectionKind)
+		}
+		connection := &onepasswordv1.OnePasswordConnection{}
+		if err := c.kubeClient.Get(ctx, client.ObjectKey{Name: ref.Name, Namespace: namespace}, connection); err != nil {
+			return nil, "", fmt.Errorf("failed to get connection %s: %w", ref, err)
+		}
+		// A namespaced connection can only read Secrets of its own namespace.
+		return &connection.Spec, namespace, nil
+	case onepasswordv1.ClusterOnePasswordConnectionKind:
+		connection := &onepasswordv1.ClusterOnePasswordConnection{}
+		if err := c.kubeClient.Get(ctx, client.ObjectKey{Name: ref.Name}, connection); err != nil {
+			return nil, "", fmt.Errorf("failed to get connection %s: %w", ref, err)
+		}
+		if connection.Spec.TokenSecretRef.Namespace == "" {
+			return nil, "", fmt.Errorf("connection %s: tokenSecretRef.namespace is required", ref)
+		}
+		if err := c.checkNamespaceAllowed(ctx, namespace, &connection.Spec); err != nil {
+			return nil, "", fmt.Errorf("connection %s: %w", ref, err)
+		}
+		return &connection.Spec.OnePasswordConnectionSpec, connection.Spec.TokenSecretRef.Namespace, nil
+	default:
+		return nil, "", fmt.Errorf("connection %s: unsupported kind %q", ref, ref.Kind)
+	}
+}
+
+// checkNamespaceAllowed fails when resources of the namespace may not select the ClusterOnePasswordConnection.
+// Cluster-scoped resources, which pass an empty namespace, may always select it.
+func (c *ConnectionClients) checkNamespaceAllowed(
+	ctx context.Context,
+	namespace string,
+	spec *onepasswordv1.ClusterOnePasswordConnectionSpec,
+) error {
+	if namespace == "" || slices.Contains(spec.AllowedNamespaces, namespace) {
+		return nil
+	}
+	if spec.NamespaceSelector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(spec.NamespaceSelector)
+		if err != nil {
+			return fmt.Errorf("invalid namespaceSelector: %w", err)
+		}
+		ns := &corev1.Namespace{}
+		if err := c.kubeClient.Get(ctx, client.ObjectKey{Name: namespace}, ns); err != nil {
+			return fmt.Errorf("failed to get namespace %s: %w", namespace, err)
+		}
+		if selector.Matches(labels.Set(ns.Labels)) {
+			return nil
+		}
+	}
+	return fmt.Errorf("namespace %s is not allowed to use the connection", namespace)
+}
+
+// getSecretValue returns the non-empty value of a Secret key.
+func (c *ConnectionClients) getSecretValue(ctx context.Context, namespace, name, key string) ([]byte, error) {
+	secret := &corev1.Secret{}
+	secretKey := client.ObjectKey{Name: name, Namespace: namespace}
+	if err := c.kubeClient.Get(ctx, secretKey, secret); err != nil {
+		return nil, fmt.Errorf("failed to get secret %s: %w", secretKey, err)
+	}
+	value := secret.Data[key]
+	if len(value) == 0 {
+		return nil, fmt.Errorf("secret %s has no key %q", secretKey, key)
+	}
+	return value, nil
+}
+
+// getConnectTransport reads the certificates of the transport spec. References of a ClusterOnePasswordConnection
+// without a namespace are read from the namespace of its token Secret, like the ones of a OnePasswordConnection.
+func (c *ConnectionClients) getConnectTransport(
+	ctx context.Context,
+	ref *onepasswordv1.ConnectionReference,
+	spec *onepasswordv1.ConnectTransportSpec,
+	secretNamespace string,
+) (connect.TransportConfig, error) {
+	if spec == nil {
+		return connect.TransportConfig{}, nil
+	}
+	namespaceFor := func(namespace string) string {
+		if namespace != "" && ref.KindOrDefault() == onepasswordv1.ClusterOnePasswordConnectionKind {
+			return namespace
+		}
+		return secretNamespace
+	}
+
+	transport := connect.TransportConfig{ProxyURL: spec.ProxyURL, UserAgent: spec.UserAgent}
+	if spec.Timeout != nil {
+		transport.Timeout = spec.Timeout.Duration
+	}
+	if spec.OperationTimeout != nil {
+		transport.OperationTimeout = spec.OperationTimeout.Duration
+	}
+	var err error
+	if caRef := spec.CASecretRef; caRef != nil {
+		transport.CABundle, err = c.getSecretValue(ctx, namespaceFor(caRef.Namespace), caRef.Name, caRef.Key)
+		if err != nil {
+			return connect.TransportConfig{}, fmt.Errorf("CA bundle: %w", err)
+		}
+	}
+	if certRef := spec.ClientCertificateSecretRef; certRef != nil {
+		namespace := namespaceFor(certRef.Namespace)
+		transport.ClientCertificate, err = c.getSecretValue(ctx, namespace, certRef.Name, corev1.TLSCertKey)
+		if err != nil {
+			return connect.TransportConfig{}, fmt.Errorf("client certificate: %w", err)
+		}
+		transport.ClientKey, err = c.getSecretValue(ctx, namespace, certRef.Name, corev1.TLSPrivateKeyKey)
+		if err != nil {
+			return connect.TransportConfig{}, fmt.Errorf("client certificate: %w", err)
+		}
+	}
+	return transport, nil
+}
+
+// ConnectionReferenceFor returns the connection selected by the spec, falling back to the
+// ConnectionAnnotation of the given annotations. It returns nil when no connection is selected.
+func ConnectionReferenceFor(
+	spec *onepasswordv1.OnePasswordItemSpec,
+	annotations map[string]string,
+) (*onepasswordv1.ConnectionReference, error) {
+	if spec != nil && spec.ConnectionRef != nil {
+		return spec.ConnectionRef, nil
+	}
+	value := strings.TrimSpace(annotations[ConnectionAnnotation])
+	if value == "" {
+		return nil, nil
+	}
+	return ParseConnectionReference(value)
+}
+
+// ParseConnectionReference parses a connection reference in the "<name>" or "<kind>/<name>" format.
+func ParseConnectionReference(value string) (*onepasswordv1.ConnectionReference, error) {
+	ref := &onepasswordv1.ConnectionReference{Name: value}
+	if kind, name, ok := strings.Cut(value, "/"); ok {
+		ref.Kind, ref.Name = kind, name
+	}
+	switch {
+	case ref.Name == "":
+		return nil, fmt.Errorf("invalid connection %q: name is empty", value)
+	case ref.Kind != "" && ref.Kind != onepasswordv1.OnePasswordConnectionKind &&
+		ref.Kind != onepasswordv1.ClusterOnePasswordConnectionKind:
+		return nil, fmt.Errorf("invalid connection %q: unsupported kind %q", value, ref.Kind)
+	}
+	return ref, nil
+}
+
+// ClientForConnection returns the client of the referenced connection, or defaultClient when ref is nil.
+// Connections may be nil when the operator does not support connections, e.g. in tests.
+func ClientForConnection(
+	ctx context.Context,
+	connections *ConnectionClients,
+	defaultClient opclient.Client,
+	namespace string,
+	ref *onepasswordv1.ConnectionReference,
+) (opclient.Client, error) {
+	if ref == nil {
+		if defaultClient == nil {
+			return nil, errNoDefaultClient
+		}
+		return defaultClient, nil
+	}
+	if connections == nil {
+		return nil, fmt.Errorf("connection %s: connections are not enabled", ref)
+	}
+	return connections.ClientFor(ctx, namespace, ref)
+}
+
+// SetConnectionAnnotation returns a copy of the annotations recording the connection, so the Secret
+// is updated with the same connection it was built with.
+func SetConnectionAnnotation(
+	annotations map[string]string,
+	ref *onepasswordv1.ConnectionReference,
+) map[string]string {
+	updated := make(map[string]string, len(annotations)+1)
+	for k, v := range annotations {
+		updated[k] = v
+	}
+	if ref == nil {
+		delete(updated, ConnectionAnnotation)
+		return updated
+	}
+	updated[ConnectionAnnotation] = ref.String()
+	return updated
+}
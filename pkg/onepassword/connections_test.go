@@ -0,0 +1,522 @@
+package onepassword
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	onepasswordv1 "github.com/1Password/onepassword-operator/api/v1"
+	"github.com/1Password/onepassword-operator/pkg/mocks"
+	opclient "github.com/1Password/onepassword-operator/pkg/onepassword/client"
+	"github.com/1Password/onepassword-operator/pkg/onepassword/client/connect"
+	"github.com/1Password/onepassword-operator/pkg/onepassword/model"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	toolscache "k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// recordingClientFactory records the credentials of the clients it builds.
+type recordingClientFactory struct {
+	built []opclient.Credentials
+}
+
+func (f *recordingClientFactory) newClient(
+	_ context.Context,
+	credentials opclient.Credentials,
+) (opclient.Client, error) {
+	f.built = append(f.built, credentials)
+	return &mocks.TestClient{}, nil
+}
+
+func newConnectionsTestClient(t *testing.T, objs ...runtime.Object) client.Client {
+	t.Helper()
+	s := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(s))
+	require.NoError(t, onepasswordv1.AddToScheme(s))
+	return fake.NewClientBuilder().WithScheme(s).WithRuntimeObjects(objs...).Build()
+}
+
+func tokenSecret(name, namespace, token string) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Data:       map[string][]byte{"token": []byte(token)},
+	}
+}
+
+func TestConnectionClientsClientFor(t *testing.T) {
+	ctx := context.Background()
+	connection := &onepasswordv1.OnePasswordConnection{
+		ObjectMeta: metav1.ObjectMeta{Name: "team-a", Namespace: "team-a"},
+		Spec: onepasswordv1.OnePasswordConnectionSpec{
+			Backend:        onepasswordv1.ConnectionBackendServiceAccount,
+			TokenSecretRef: onepasswordv1.SecretKeyReference{Name: "team-a-token", Key: "token"},
+		},
+	}
+	clusterConnection := &onepasswordv1.ClusterOnePasswordConnection{
+		ObjectMeta: metav1.ObjectMeta{Name: "shared"},
+		Spec: onepasswordv1.ClusterOnePasswordConnectionSpec{
+			OnePasswordConnectionSpec: onepasswordv1.OnePasswordConnectionSpec{
+				Backend: onepasswordv1.ConnectionBackendConnect,
+				Host:    "http://connect:8080",
+				TokenSecretRef: onepasswordv1.SecretKeyReference{
+					Name: "connect-token", Namespace: "onepassword", Key: "token",
+				},
+			},
+		},
+	}
+	kubeClient := newConnectionsTestClient(t, connection, clusterConnection,
+		tokenSecret("team-a-token", "team-a", "sa-token"),
+		tokenSecret("connect-token", "onepassword", "connect-token"),
+	)
+	factory := &recordingClientFactory{}
+	connections := NewConnectionClients(kubeClient, factory.newClient)
+
+	ref := &onepasswordv1.ConnectionReference{Name: "team-a"}
+	first, err := connections.ClientFor(ctx, "team-a", ref)
+	require.NoError(t, err)
+	second, err := connections.ClientFor(ctx, "team-a", ref)
+	require.NoError(t, err)
+	assert.Same(t, first, second, "the client should be cached")
+
+	clusterRef := &onepasswordv1.ConnectionReference{
+		Kind: onepasswordv1.ClusterOnePasswordConnectionKind, Name: "shared",
+	}
+	_, err = connections.ClientFor(ctx, "", clusterRef)
+	require.NoError(t, err)
+
+	assert.Equal(t, []opclient.Credentials{
+		{ServiceAccountToken: "sa-token"},
+		{ConnectHost: "http://connect:8080", ConnectToken: "connect-token"},
+	}, factory.built)
+
+	// A rotated token rebuilds the client.
+	rotated := tokenSecret("team-a-token", "team-a", "rotated-token")
+	require.NoError(t, kubeClient.Update(ctx, rotated))
+	third, err := connections.ClientFor(ctx, "team-a", ref)
+	require.NoError(t, err)
+	assert.NotSame(t, first, third)
+	assert.Equal(t, opclient.Credentials{ServiceAccountToken: "rotated-token"}, factory.built[2])
+}
+
+func TestConnectionClientsClientForErrors(t *testing.T) {
+	ctx := context.Background()
+	connection := &onepasswordv1.OnePasswordConnection{
+		ObjectMeta: metav1.ObjectMeta{Name: "team-a", Namespace: "team-a"},
+		Spec: onepasswordv1.OnePasswordConnectionSpec{
+			Backend:        onepasswordv1.ConnectionBackendServiceAccount,
+			TokenSecretRef: onepasswordv1.SecretKeyReference{Name: "team-a-token", Key: "missing"},
+		},
+	}
+	kubeClient := newConnectionsTestClient(t, connection, tokenSecret("team-a-token", "team-a", "sa-token"))
+	connections := NewConnectionClients(kubeClient, (&recordingClientFactory{}).newClient)
+
+	tests := map[string]struct {
+		namespace string
+		ref       *onepasswordv1.ConnectionReference
+		want      string
+	}{
+		"missing connection": {
+			namespace: "team-b",
+			ref:       &onepasswordv1.ConnectionReference{Name: "team-a"},
+			want:      "failed to get connection OnePasswordConnection/team-a",
+		},
+		"namespaced connection from cluster resource": {
+			ref:  &onepasswordv1.ConnectionReference{Name: "team-a"},
+			want: "cluster-scoped resources can only reference a ClusterOnePasswordConnection",
+		},
+		"missing token key": {
+			namespace: "team-a",
+			ref:       &onepasswordv1.ConnectionReference{Name: "team-a"},
+			want:      `has no key "missing"`,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := connections.ClientFor(ctx, tt.namespace, tt.ref)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.want)
+		})
+	}
+}
+
+func TestConnectionClientsClusterConnectionNamespaces(t *testing.T) {
+	ctx := context.Background()
+	newClusterConnection := func(name string, spec onepasswordv1.ClusterOnePasswordConnectionSpec) client.Object {
+		spec.Backend = onepasswordv1.ConnectionBackendServiceAccount
+		spec.TokenSecretRef = onepasswordv1.SecretKeyReference{Name: "sa-token", Namespace: "onepassword", Key: "token"}
+		return &onepasswordv1.ClusterOnePasswordConnection{ObjectMeta: metav1.ObjectMeta{Name: name}, Spec: spec}
+	}
+	kubeClient := newConnectionsTestClient(t,
+		newClusterConnection("restricted", onepasswordv1.ClusterOnePasswordConnectionSpec{}),
+		newClusterConnection("listed", onepasswordv1.ClusterOnePasswordConnectionSpec{
+			AllowedNamespaces: []string{"team-a"},
+		}),
+		newClusterConnection("selected", onepasswordv1.ClusterOnePasswordConnectionSpec{
+			NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "billing"}},
+		}),
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-a"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "billing", Labels: map[string]string{"team": "billing"}}},
+		tokenSecret("sa-token", "onepassword", "sa-token"),
+	)
+	connections := NewConnectionClients(kubeClient, (&recordingClientFactory{}).newClient)
+
+	tests := map[string]struct {
+		connection string
+		namespace  string
+		allowed    bool
+	}{
+		"cluster resource":       {connection: "restricted", allowed: true},
+		"namespace not allowed":  {connection: "restricted", namespace: "team-a"},
+		"namespace listed":       {connection: "listed", namespace: "team-a", allowed: true},
+		"namespace not listed":   {connection: "listed", namespace: "billing"},
+		"namespace selected":     {connection: "selected", namespace: "billing", allowed: true},
+		"namespace not selected": {connection: "selected", namespace: "team-a"},
+		"missing namespace":      {connection: "selected", namespace: "unknown"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ref := &onepasswordv1.ConnectionReference{
+				Kind: onepasswordv1.ClusterOnePasswordConnectionKind, Name: tt.connection,
+			}
+			_, err := connections.ClientFor(ctx, tt.namespace, ref)
+			if tt.allowed {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "ClusterOnePasswordConnection/"+tt.connection)
+		})
+	}
+}
+
+func TestConnectionClientsBuildsClientsIndependently(t *testing.T) {
+	ctx := context.Background()
+	kubeClient := newConnectionsTestClient(t,
+		&onepasswordv1.OnePasswordConnection{
+			ObjectMeta: metav1.ObjectMeta{Name: "slow", Namespace: "team-a"},
+			Spec: onepasswordv1.OnePasswordConnectionSpec{
+				Backend:        onepasswordv1.ConnectionBackendServiceAccount,
+				TokenSecretRef: onepasswordv1.SecretKeyReference{Name: "slow-token", Key: "token"},
+			},
+		},
+		&onepasswordv1.OnePasswordConnection{
+			ObjectMeta: metav1.ObjectMeta{Name: "fast", Namespace: "team-a"},
+			Spec: onepasswordv1.OnePasswordConnectionSpec{
+				Backend:        onepasswordv1.ConnectionBackendServiceAccount,
+				TokenSecretRef: onepasswordv1.SecretKeyReference{Name: "fast-token", Key: "token"},
+			},
+		},
+		tokenSecret("slow-token", "team-a", "slow"),
+		tokenSecret("fast-token", "team-a", "fast"),
+	)
+	release := make(chan struct{})
+	connections := NewConnectionClients(kubeClient, func(
+		ctx context.Context,
+		credentials opclient.Credentials,
+	) (opclient.Client, error) {
+		if credentials.ServiceAccountToken == "slow" {
+			select {
+			case <-release:
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+		return &mocks.TestClient{}, nil
+	})
+
+	slowDone := make(chan error)
+	go func() {
+		_, err := connections.ClientFor(ctx, "team-a", &onepasswordv1.ConnectionReference{Name: "slow"})
+		slowDone <- err
+	}()
+
+	_, err := connections.ClientFor(ctx, "team-a", &onepasswordv1.ConnectionReference{Name: "fast"})
+	require.NoError(t, err, "a slow connection should not block the others")
+
+	close(release)
+	require.NoError(t, <-slowDone)
+}
+
+func TestConnectionClientsEvict(t *testing.T) {
+	ctx := context.Background()
+	connection := &onepasswordv1.OnePasswordConnection{
+		ObjectMeta: metav1.ObjectMeta{Name: "team-a", Namespace: "team-a"},
+		Spec: onepasswordv1.OnePasswordConnectionSpec{
+			Backend:        onepasswordv1.ConnectionBackendServiceAccount,
+			TokenSecretRef: onepasswordv1.SecretKeyReference{Name: "team-a-token", Key: "token"},
+		},
+	}
+	kubeClient := newConnectionsTestClient(t, connection, tokenSecret("team-a-token", "team-a", "sa-token"))
+	connections := NewConnectionClients(kubeClient, (&recordingClientFactory{}).newClient)
+
+	_, err := connections.ClientFor(ctx, "team-a", &onepasswordv1.ConnectionReference{Name: "team-a"})
+	require.NoError(t, err)
+	require.Len(t, connections.clients, 1)
+
+	connections.evict(toolscache.DeletedFinalStateUnknown{Key: "team-a/team-a", Obj: connection})
+	assert.Empty(t, connections.clients, "the client of a deleted connection should be dropped")
+}
+
+func TestConnectionClientsConnectTransport(t *testing.T) {
+	ctx := context.Background()
+	clusterConnection := &onepasswordv1.ClusterOnePasswordConnection{
+		ObjectMeta: metav1.ObjectMeta{Name: "shared"},
+		Spec: onepasswordv1.ClusterOnePasswordConnectionSpec{
+			OnePasswordConnectionSpec: onepasswordv1.OnePasswordConnectionSpec{
+				Backend: onepasswordv1.ConnectionBackendConnect,
+				Host:    "https://connect:8443",
+				TokenSecretRef: onepasswordv1.SecretKeyReference{
+					Name: "connect-token", Namespace: "onepassword", Key: "token",
+				},
+				Connect: &onepasswordv1.ConnectTransportSpec{
+					// Without a namespace, the CA bundle is read from the namespace of the token.
+					CASecretRef: &onepasswordv1.SecretKeyReference{Name: "connect-ca", Key: "ca.crt"},
+					ClientCertificateSecretRef: &onepasswordv1.SecretReference{
+						Name: "connect-client", Namespace: "certificates",
+					},
+					ProxyURL:         "http://proxy:3128",
+					Timeout:          &metav1.Duration{Duration: 10 * time.Second},
+					OperationTimeout: &metav1.Duration{Duration: time.Minute},
+					UserAgent:        "operator/test",
+				},
+			},
+		},
+	}
+	kubeClient := newConnectionsTestClient(t, clusterConnection,
+		tokenSecret("connect-token", "onepassword", "connect-token"),
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "connect-ca", Namespace: "onepassword"},
+			Data:       map[string][]byte{"ca.crt": []byte("ca")},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "connect-client", Namespace: "certificates"},
+			Type:       corev1.SecretTypeTLS,
+			Data:       map[string][]byte{corev1.TLSCertKey: []byte("cert"), corev1.TLSPrivateKeyKey: []byte("key")},
+		},
+	)
+	factory := &recordingClientFactory{}
+	connections := NewConnectionClients(kubeClient, factory.newClient)
+
+	ref := &onepasswordv1.ConnectionReference{Kind: onepasswordv1.ClusterOnePasswordConnectionKind, Name: "shared"}
+	first, err := connections.ClientFor(ctx, "", ref)
+	require.NoError(t, err)
+	require.Len(t, factory.built, 1)
+	assert.Equal(t, connect.TransportConfig{
+		CABundle:          []byte("ca"),
+		ClientCertificate: []byte("cert"),
+		ClientKey:         []byte("key"),
+		ProxyURL:          "http://proxy:3128",
+		Timeout:           10 * time.Second,
+		OperationTimeout:  time.Minute,
+		UserAgent:         "operator/test",
+	}, factory.built[0].ConnectTransport)
+
+	// A renewed client certificate rebuilds the client.
+	renewed := &corev1.Secret{}
+	renewedKey := types.NamespacedName{Name: "connect-client", Namespace: "certificates"}
+	require.NoError(t, kubeClient.Get(ctx, renewedKey, renewed))
+	renewed.Data[corev1.TLSCertKey] = []byte("renewed-cert")
+	require.NoError(t, kubeClient.Update(ctx, renewed))
+	second, err := connections.ClientFor(ctx, "", ref)
+	require.NoError(t, err)
+	assert.NotSame(t, first, second)
+	assert.Equal(t, []byte("renewed-cert"), factory.built[1].ConnectTransport.ClientCertificate)
+
+	// A missing CA bundle fails the connection.
+	require.NoError(t, kubeClient.Delete(ctx, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "connect-ca", Namespace: "onepassword"},
+	}))
+	_, err = connections.ClientFor(ctx, "", ref)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "CA bundle")
+}
+
+func TestConnectionReferenceFor(t *testing.T) {
+	specRef := &onepasswordv1.ConnectionReference{Name: "from-spec"}
+
+	tests := map[string]struct {
+		spec        *onepasswordv1.OnePasswordItemSpec
+		annotations map[string]string
+		want        *onepasswordv1.ConnectionReference
+		wantErr     bool
+	}{
+		"nothing selected": {},
+		"spec wins": {
+			spec:        &onepasswordv1.OnePasswordItemSpec{ConnectionRef: specRef},
+			annotations: map[string]string{ConnectionAnnotation: "from-annotation"},
+			want:        specRef,
+		},
+		"annotation name": {
+			annotations: map[string]string{ConnectionAnnotation: "team-a"},
+			want:        &onepasswordv1.ConnectionReference{Name: "team-a"},
+		},
+		"annotation kind and name": {
+			annotations: map[string]string{ConnectionAnnotation: "ClusterOnePasswordConnection/shared"},
+			want: &onepasswordv1.ConnectionReference{
+				Kind: onepasswordv1.ClusterOnePasswordConnectionKind, Name: "shared",
+			},
+		},
+		"annotation with unknown kind": {
+			annotations: map[string]string{ConnectionAnnotation: "Secret/shared"},
+			wantErr:     true,
+		},
+		"annotation without name": {
+			annotations: map[string]string{ConnectionAnnotation: "OnePasswordConnection/"},
+			wantErr:     true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := ConnectionReferenceFor(tt.spec, tt.annotations)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestClientForConnection(t *testing.T) {
+	ctx := context.Background()
+	defaultClient := &mocks.TestClient{}
+
+	got, err := ClientForConnection(ctx, nil, defaultClient, "default", nil)
+	require.NoError(t, err)
+	assert.Same(t, defaultClient, got)
+
+	_, err = ClientForConnection(ctx, nil, nil, "default", nil)
+	assert.True(t, errors.Is(err, errNoDefaultClient))
+
+	_, err = ClientForConnection(ctx, nil, defaultClient, "default", &onepasswordv1.ConnectionReference{Name: "team-a"})
+	assert.Error(t, err)
+}
+
+func TestSetConnectionAnnotation(t *testing.T) {
+	annotations := map[string]string{ConnectionAnnotation: "team-a", "other": "value"}
+
+	updated := SetConnectionAnnotation(annotations, &onepasswordv1.ConnectionReference{Name: "team-a"})
+	assert.Equal(t, "OnePasswordConnection/team-a", updated[ConnectionAnnotation])
+	assert.Equal(t, "team-a", annotations[ConnectionAnnotation], "the given annotations should not be modified")
+
+	updated = SetConnectionAnnotation(annotations, nil)
+	assert.Equal(t, map[string]string{"other": "value"}, updated)
+}
+
+func TestUpdateSecretHandlerConnection(t *testing.T) {
+	ctx := context.Background()
+	connection := &onepasswordv1.OnePasswordConnection{
+		ObjectMeta: metav1.ObjectMeta{Name: "team-a", Namespace: namespace},
+		Spec: onepasswordv1.OnePasswordConnectionSpec{
+			Backend:        onepasswordv1.ConnectionBackendServiceAccount,
+			TokenSecretRef: onepasswordv1.SecretKeyReference{Name: "team-a-token", Key: "token"},
+		},
+	}
+	existingSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Annotations: map[string]string{
+				VersionAnnotation:    "old-version",
+				ItemPathAnnotation:   itemPath,
+				ConnectionAnnotation: "OnePasswordConnection/team-a",
+			},
+		},
+	}
+	kubeClient := newConnectionsTestClient(t, defaultNamespace, connection, existingSecret,
+		tokenSecret("team-a-token", namespace, "sa-token"))
+
+	connectionClient := &mocks.TestClient{}
+	connectionClient.On("GetItemByID", mock.Anything, mock.Anything).Return(createItem(), nil)
+	connectionClient.On("GetVaultsByTitle", mock.Anything).Return([]model.Vault{}, nil)
+
+	h := &SecretUpdateHandler{
+		client:    kubeClient,
+		apiReader: kubeClient,
+		// The default client must not be used for the Secret.
+		opClient: &mocks.TestClient{},
+		connections: NewConnectionClients(kubeClient,
+			func(context.Context, opclient.Credentials) (opclient.Client, error) {
+				return connectionClient, nil
+			}),
+	}
+
+	require.NoError(t, h.UpdateKubernetesSecretsTask(ctx))
+
+	updatedSecret := &corev1.Secret{}
+	require.NoError(t, kubeClient.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, updatedSecret))
+	assert.Equal(t, []byte(username), updatedSecret.Data["username"])
+	assert.Equal(t, "OnePasswordConnection/team-a", updatedSecret.Annotations[ConnectionAnnotation])
+	connectionClient.AssertCalled(t, "GetItemByID", mock.Anything, mock.Anything)
+}
+
+func TestUpdateSecretHandlerForgedClusterItemLabel(t *testing.T) {
+	ctx := context.Background()
+	clusterConnection := &onepasswordv1.ClusterOnePasswordConnection{
+		ObjectMeta: metav1.ObjectMeta{Name: "shared"},
+		Spec: onepasswordv1.ClusterOnePasswordConnectionSpec{
+			OnePasswordConnectionSpec: onepasswordv1.OnePasswordConnectionSpec{
+				Backend: onepasswordv1.ConnectionBackendServiceAccount,
+				TokenSecretRef: onepasswordv1.SecretKeyReference{
+					Name: "sa-token", Namespace: "onepassword", Key: "token",
+				},
+			},
+			AllowedNamespaces: []string{"team-a"},
+		},
+	}
+	clusterItem := &onepasswordv1.ClusterOnePasswordItem{
+		ObjectMeta: metav1.ObjectMeta{Name: "shared-credentials", UID: "cluster-item-uid"},
+		Spec: onepasswordv1.ClusterOnePasswordItemSpec{
+			OnePasswordItemSpec: onepasswordv1.OnePasswordItemSpec{ItemPath: itemPath},
+		},
+	}
+	// A tenant of the namespace labels its own Secret as if the ClusterOnePasswordItem managed it, to
+	// fetch items through a connection its namespace is not allowed to use.
+	forgedSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "forged",
+			Namespace: namespace,
+			Labels:    map[string]string{ClusterItemLabel: clusterItem.Name},
+			Annotations: map[string]string{
+				VersionAnnotation:    "old-version",
+				ItemPathAnnotation:   itemPath,
+				ConnectionAnnotation: "ClusterOnePasswordConnection/shared",
+			},
+		},
+	}
+	kubeClient := newConnectionsTestClient(t, defaultNamespace, clusterConnection, clusterItem, forgedSecret,
+		tokenSecret("sa-token", "onepassword", "sa-token"))
+
+	connectionClient := &mocks.TestClient{}
+	h := &SecretUpdateHandler{
+		client:    kubeClient,
+		apiReader: kubeClient,
+		opClient:  &mocks.TestClient{},
+		connections: NewConnectionClients(kubeClient,
+			func(context.Context, opclient.Credentials) (opclient.Client, error) {
+				return connectionClient, nil
+			}),
+	}
+
+	require.NoError(t, h.UpdateKubernetesSecretsTask(ctx))
+
+	secret := &corev1.Secret{}
+	require.NoError(t, kubeClient.Get(ctx, types.NamespacedName{Name: "forged", Namespace: namespace}, secret))
+	assert.Equal(t, "old-version", secret.Annotations[VersionAnnotation], "the forged Secret should not be synced")
+	connectionClient.AssertNotCalled(t, "GetItemByID", mock.Anything, mock.Anything)
+}
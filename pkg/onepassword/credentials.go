@@ -0,0 +1,155 @@
+package onepassword
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	opclient "github.com/1Password/onepassword-operator/pkg/onepassword/client"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Environment variables selecting reloadable credentials for the client the operator is started with.
+const (
+	ConnectTokenFileEnv        = "OP_CONNECT_TOKEN_FILE"
+	ServiceAccountTokenFileEnv = "OP_SERVICE_ACCOUNT_TOKEN_FILE"
+	// The Secret references are "<name>/<key>" in the namespace of the operator, or "<namespace>/<name>/<key>".
+	ConnectTokenSecretEnv        = "OP_CONNECT_TOKEN_SECRET"
+	ServiceAccountTokenSecretEnv = "OP_SERVICE_ACCOUNT_TOKEN_SECRET"
+)
+
+// Environment variables configuring how the Connect server is reached.
+const (
+	ConnectCAFileEnv                = "OP_CONNECT_CA_FILE"
+	ConnectClientCertificateFileEnv = "OP_CONNECT_CLIENT_CERT_FILE"
+	ConnectClientKeyFileEnv         = "OP_CONNECT_CLIENT_KEY_FILE"
+	ConnectProxyURLEnv              = "OP_CONNECT_PROXY_URL"
+	// ConnectTimeoutEnv and ConnectOperationTimeoutEnv are durations such as "10s".
+	ConnectTimeoutEnv          = "OP_CONNECT_TIMEOUT"
+	ConnectOperationTimeoutEnv = "OP_CONNECT_OPERATION_TIMEOUT"
+	ConnectUserAgentEnv        = "OP_CONNECT_USER_AGENT"
+)
+
+// SecretTokenSource reads a token from a key of a Secret.
+type SecretTokenSource struct {
+	Reader    client.Reader
+	Namespace string
+	Name      string
+	Key       string
+}
+
+func (s *SecretTokenSource) Token(ctx context.Context) (string, error) {
+	secret := &corev1.Secret{}
+	if err := s.Reader.Get(ctx, client.ObjectKey{Name: s.Name, Namespace: s.Namespace}, secret); err != nil {
+		return "", fmt.Errorf("failed to get secret: %w", err)
+	}
+	token, ok := secret.Data[s.Key]
+	if !ok {
+		return "", fmt.Errorf("secret has no key %q", s.Key)
+	}
+	return strings.TrimSpace(string(token)), nil
+}
+
+func (s *SecretTokenSource) String() string {
+	return fmt.Sprintf("secret %s/%s key %s", s.Namespace, s.Name, s.Key)
+}
+
+// ConnectTransportFromEnvironment returns the Connect transport configured by the environment.
+func ConnectTransportFromEnvironment() (opclient.ConnectTransportSource, error) {
+	transport := opclient.ConnectTransportSource{
+		CAFile:                os.Getenv(ConnectCAFileEnv),
+		ClientCertificateFile: os.Getenv(ConnectClientCertificateFileEnv),
+		ClientKeyFile:         os.Getenv(ConnectClientKeyFileEnv),
+		ProxyURL:              os.Getenv(ConnectProxyURLEnv),
+		UserAgent:             os.Getenv(ConnectUserAgentEnv),
+	}
+	for env, timeout := range map[string]*time.Duration{
+		ConnectTimeoutEnv:          &transport.Timeout,
+		ConnectOperationTimeoutEnv: &transport.OperationTimeout,
+	} {
+		value := os.Getenv(env)
+		if value == "" {
+			continue
+		}
+		var err error
+		if *timeout, err = time.ParseDuration(value); err != nil {
+			return opclient.ConnectTransportSource{}, fmt.Errorf("invalid %s: %w", env, err)
+		}
+	}
+	return transport, nil
+}
+
+// CredentialSourcesFromEnvironment returns the credential sources configured by the environment, and
+// whether any of them can change while the operator runs. Tokens set directly in OP_CONNECT_TOKEN or
+// OP_SERVICE_ACCOUNT_TOKEN are used when no file or Secret is set for them.
+func CredentialSourcesFromEnvironment(
+	reader client.Reader,
+	namespace string,
+) (opclient.CredentialSources, bool, error) {
+	sources := opclient.CredentialSources{ConnectHost: os.Getenv("OP_CONNECT_HOST")}
+	reloadable := false
+
+	var err error
+	sources.ConnectToken, err = tokenSourceFromEnvironment(reader, namespace,
+		"OP_CONNECT_TOKEN", ConnectTokenFileEnv, ConnectTokenSecretEnv, &reloadable)
+	if err != nil {
+		return opclient.CredentialSources{}, false, err
+	}
+	sources.ServiceAccountToken, err = tokenSourceFromEnvironment(reader, namespace,
+		"OP_SERVICE_ACCOUNT_TOKEN", ServiceAccountTokenFileEnv, ServiceAccountTokenSecretEnv, &reloadable)
+	if err != nil {
+		return opclient.CredentialSources{}, false, err
+	}
+	return sources, reloadable, nil
+}
+
+func tokenSourceFromEnvironment(
+	reader client.Reader,
+	namespace, tokenEnv, fileEnv, secretEnv string,
+	reloadable *bool,
+) (opclient.TokenSource, error) {
+	path := os.Getenv(fileEnv)
+	ref := os.Getenv(secretEnv)
+	switch {
+	case path != "" && ref != "":
+		return nil, fmt.Errorf("only one of %s and %s can be set", fileEnv, secretEnv)
+	case path != "":
+		*reloadable = true
+		return &opclient.FileTokenSource{Path: path}, nil
+	case ref != "":
+		source, err := parseSecretTokenSource(reader, namespace, ref)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", secretEnv, err)
+		}
+		*reloadable = true
+		return source, nil
+	}
+	if token := os.Getenv(tokenEnv); token != "" {
+		return opclient.StaticToken(token), nil
+	}
+	return nil, nil
+}
+
+func parseSecretTokenSource(reader client.Reader, namespace, ref string) (*SecretTokenSource, error) {
+	parts := strings.Split(ref, "/")
+	switch len(parts) {
+	case 2:
+		if namespace == "" {
+			return nil, fmt.Errorf("the namespace of %q is required when the operator namespace is unknown", ref)
+		}
+		parts = append([]string{namespace}, parts...)
+	case 3:
+	default:
+		return nil, fmt.Errorf("%q must be <name>/<key> or <namespace>/<name>/<key>", ref)
+	}
+	for _, part := range parts {
+		if part == "" {
+			return nil, fmt.Errorf("%q must be <name>/<key> or <namespace>/<name>/<key>", ref)
+		}
+	}
+	return &SecretTokenSource{Reader: reader, Namespace: parts[0], Name: parts[1], Key: parts[2]}, nil
+}
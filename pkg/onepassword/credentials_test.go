@@ -0,0 +1,131 @@
+package onepassword
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	opclient "github.com/1Password/onepassword-operator/pkg/onepassword/client"
+)
+
+func TestCredentialSourcesFromEnvironment(t *testing.T) {
+	kubeClient := newConnectionsTestClient(t, tokenSecret("operator-token", "onepassword", "secret-token"))
+
+	tests := map[string]struct {
+		env            map[string]string
+		wantReloadable bool
+		wantConnect    opclient.TokenSource
+		wantSA         opclient.TokenSource
+		wantErr        bool
+	}{
+		"tokens from environment variables": {
+			env: map[string]string{
+				"OP_CONNECT_HOST":  "http://connect:8080",
+				"OP_CONNECT_TOKEN": "connect-token",
+			},
+			wantConnect: opclient.StaticToken("connect-token"),
+		},
+		"token file": {
+			env: map[string]string{
+				"OP_SERVICE_ACCOUNT_TOKEN": "ignored",
+				ServiceAccountTokenFileEnv: "/var/run/secrets/onepassword/token",
+			},
+			wantReloadable: true,
+			wantSA:         &opclient.FileTokenSource{Path: "/var/run/secrets/onepassword/token"},
+		},
+		"secret in operator namespace": {
+			env:            map[string]string{ConnectTokenSecretEnv: "operator-token/token"},
+			wantReloadable: true,
+			wantConnect: &SecretTokenSource{
+				Reader: kubeClient, Namespace: "onepassword", Name: "operator-token", Key: "token",
+			},
+		},
+		"secret in another namespace": {
+			env:            map[string]string{ServiceAccountTokenSecretEnv: "shared/operator-token/token"},
+			wantReloadable: true,
+			wantSA: &SecretTokenSource{
+				Reader: kubeClient, Namespace: "shared", Name: "operator-token", Key: "token",
+			},
+		},
+		"invalid secret reference": {
+			env:     map[string]string{ServiceAccountTokenSecretEnv: "operator-token"},
+			wantErr: true,
+		},
+		"file and secret": {
+			env: map[string]string{
+				ServiceAccountTokenFileEnv:   "/token",
+				ServiceAccountTokenSecretEnv: "operator-token/token",
+			},
+			wantErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			for _, env := range []string{
+				"OP_CONNECT_HOST", "OP_CONNECT_TOKEN", "OP_SERVICE_ACCOUNT_TOKEN",
+				ConnectTokenFileEnv, ServiceAccountTokenFileEnv, ConnectTokenSecretEnv, ServiceAccountTokenSecretEnv,
+			} {
+				t.Setenv(env, tt.env[env])
+			}
+
+			sources, reloadable, err := CredentialSourcesFromEnvironment(kubeClient, "onepassword")
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantReloadable, reloadable)
+			assert.Equal(t, tt.env["OP_CONNECT_HOST"], sources.ConnectHost)
+			assert.Equal(t, tt.wantConnect, sources.ConnectToken)
+			assert.Equal(t, tt.wantSA, sources.ServiceAccountToken)
+		})
+	}
+}
+
+func TestSecretTokenSource(t *testing.T) {
+	ctx := context.Background()
+	kubeClient := newConnectionsTestClient(t, tokenSecret("operator-token", "onepassword", "secret-token\n"))
+
+	source := &SecretTokenSource{Reader: kubeClient, Namespace: "onepassword", Name: "operator-token", Key: "token"}
+	token, err := source.Token(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "secret-token", token)
+
+	source.Key = "missing"
+	_, err = source.Token(ctx)
+	assert.Error(t, err)
+
+	source.Name = "missing"
+	_, err = source.Token(ctx)
+	assert.Error(t, err)
+}
+
+func TestConnectTransportFromEnvironment(t *testing.T) {
+	t.Setenv(ConnectCAFileEnv, "/etc/connect/ca.crt")
+	t.Setenv(ConnectClientCertificateFileEnv, "/etc/connect/tls.crt")
+	t.Setenv(ConnectClientKeyFileEnv, "/etc/connect/tls.key")
+	t.Setenv(ConnectProxyURLEnv, "http://proxy:3128")
+	t.Setenv(ConnectTimeoutEnv, "15s")
+	t.Setenv(ConnectOperationTimeoutEnv, "1m")
+	t.Setenv(ConnectUserAgentEnv, "operator/test")
+
+	transport, err := ConnectTransportFromEnvironment()
+	require.NoError(t, err)
+	assert.Equal(t, opclient.ConnectTransportSource{
+		CAFile:                "/etc/connect/ca.crt",
+		ClientCertificateFile: "/etc/connect/tls.crt",
+		ClientKeyFile:         "/etc/connect/tls.key",
+		ProxyURL:              "http://proxy:3128",
+		Timeout:               15 * time.Second,
+		OperationTimeout:      time.Minute,
+		UserAgent:             "operator/test",
+	}, transport)
+
+	t.Setenv(ConnectTimeoutEnv, "15")
+	_, err = ConnectTransportFromEnvironment()
+	assert.Error(t, err)
+}
@@ -7,6 +7,7 @@ import (
 
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
+	onepasswordv1 "github.com/1Password/onepassword-operator/api/v1"
 	opclient "github.com/1Password/onepassword-operator/pkg/onepassword/client"
 	"github.com/1Password/onepassword-operator/pkg/onepassword/model"
 )
@@ -28,12 +29,7 @@ func GetOnePasswordItemByPath(ctx context.Context, opClient opclient.Client, pat
 	if IsValidClientUUID(itemNameOrID) {
 		item, err = opClient.GetItemByID(ctx, vaultID, itemNameOrID)
 		if err == nil {
-			// Success, load files and return
-			err = loadItemFiles(ctx, opClient, vaultID, item)
-			if err != nil {
-				return nil, fmt.Errorf("failed to load item files for vaultID='%s' and itemNameOrID='%s': %w",
-					vaultID, itemNameOrID, err)
-			}
+			setItemFileLoaders(ctx, opClient, vaultID, item)
 			return item, nil
 		}
 		// If UUID lookup failed, fallback to title lookup
@@ -50,12 +46,31 @@ func GetOnePasswordItemByPath(ctx context.Context, opClient opclient.Client, pat
 		return nil, fmt.Errorf("failed to get item by ID for vaultID='%s' and itemID='%s': %w", vaultID, itemID, err)
 	}
 
-	err = loadItemFiles(ctx, opClient, vaultID, item)
-	if err != nil {
-		return nil, fmt.Errorf("failed to load item files for vaultID='%s' and itemID='%s': %w", vaultID, itemID, err)
+	setItemFileLoaders(ctx, opClient, vaultID, item)
+	return item, nil
+}
+
+// GetReferencedItems retrieves the items, other than the main item, the spec reads fields from.
+// The items are keyed by the path used in the spec.
+func GetReferencedItems(
+	ctx context.Context,
+	opClient opclient.Client,
+	spec *onepasswordv1.OnePasswordItemSpec,
+) (map[string]*model.Item, error) {
+	paths := spec.ReferencedItemPaths()
+	if len(paths) == 0 {
+		return nil, nil
 	}
 
-	return item, nil
+	items := make(map[string]*model.Item, len(paths))
+	for _, path := range paths {
+		item, err := GetOnePasswordItemByPath(ctx, opClient, path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve referenced item %q: %w", path, err)
+		}
+		items[path] = item
+	}
+	return items, nil
 }
 
 func ParseVaultAndItemFromPath(path string) (string, string, error) {
@@ -127,14 +142,17 @@ func getItemIDByTitle(ctx context.Context, client opclient.Client, vaultId, item
 	return oldestItem.ID, nil
 }
 
-func loadItemFiles(ctx context.Context, client opclient.Client, vaultID string,
-	item *model.Item) error {
-	for i, file := range item.Files {
-		content, err := client.GetFileContent(ctx, vaultID, item.ID, file.ID)
-		if err != nil {
-			return err
-		}
-		item.Files[i].SetContent(content)
+// setItemFileLoaders makes the files of the item download their content when it is first needed,
+// so attachments that are not used are never fetched.
+func setItemFileLoaders(ctx context.Context, client opclient.Client, vaultID string, item *model.Item) {
+	for i := range item.Files {
+		fileID, fileName := item.Files[i].ID, item.Files[i].Name
+		item.Files[i].SetContentLoader(func() ([]byte, error) {
+			content, err := client.GetFileContent(ctx, vaultID, item.ID, fileID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to download file %q of item %q: %w", fileName, item.ID, err)
+			}
+			return content, nil
+		})
 	}
-	return nil
 }
@@ -0,0 +1,60 @@
+package onepassword
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/1Password/onepassword-operator/pkg/onepassword/client/connect"
+	"github.com/1Password/onepassword-operator/pkg/onepassword/client/file"
+)
+
+// A reconcile whose context is cancelled, e.g. when the manager shuts down, should not wait for a
+// Connect server that does not answer.
+func TestGetOnePasswordItemByPathCancelled(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	opClient, err := connect.NewClient(connect.Config{ConnectHost: server.URL, ConnectToken: "token"})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	start := time.Now()
+	_, err = GetOnePasswordItemByPath(ctx, opClient, "vaults/Employee/items/database")
+	require.ErrorIs(t, err, context.Canceled)
+	require.Less(t, time.Since(start), time.Second)
+}
+
+func TestGetOnePasswordItemByPathFileBackend(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "Demo"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "Demo", "database.yaml"), []byte(`
+fields:
+  - label: password
+    value: demo
+    type: CONCEALED
+`), 0o600))
+	opClient, err := file.NewClient(file.Config{Dir: dir})
+	require.NoError(t, err)
+
+	item, err := GetOnePasswordItemByPath(context.Background(), opClient, "vaults/Demo/items/database")
+	require.NoError(t, err)
+	require.Equal(t, "database", item.ID)
+	require.Equal(t, "Demo", item.VaultID)
+	require.Equal(t, "demo", item.Fields[0].Value)
+}
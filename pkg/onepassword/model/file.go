@@ -2,8 +2,12 @@ package model
 
 import (
 	"errors"
+	"sync"
 )
 
+// ErrFileContentNotLoaded is returned by File.Content when the content was neither set nor can be downloaded.
+var ErrFileContentNotLoaded = errors.New("file content not loaded")
+
 // File represents a file stored in 1Password.
 type File struct {
 	ID          string
@@ -11,17 +15,37 @@ type File struct {
 	Size        int
 	ContentPath string
 	content     []byte
+	// loader is shared by copies of the file, so the content is downloaded at most once.
+	loader *fileLoader
+}
+
+type fileLoader struct {
+	once    sync.Once
+	load    func() ([]byte, error)
+	content []byte
+	err     error
 }
 
-// Content returns the content of the file if they have been loaded and returns an error if they have not been loaded.
-// Use `client.GetFileContent(file *File)` instead to make sure the content is fetched automatically if not present.
+// Content returns the content of the file. Content set with SetContentLoader is downloaded on the
+// first call. An error is returned if the content has not been set and cannot be downloaded.
 func (f *File) Content() ([]byte, error) {
-	if f.content == nil {
-		return nil, errors.New("file content not loaded")
+	if f.content != nil {
+		return f.content, nil
 	}
-	return f.content, nil
+	if f.loader == nil {
+		return nil, ErrFileContentNotLoaded
+	}
+	f.loader.once.Do(func() {
+		f.loader.content, f.loader.err = f.loader.load()
+	})
+	return f.loader.content, f.loader.err
 }
 
 func (f *File) SetContent(content []byte) {
 	f.content = content
 }
+
+// SetContentLoader sets the function that downloads the content of the file when it is first needed.
+func (f *File) SetContentLoader(load func() ([]byte, error)) {
+	f.loader = &fileLoader{load: load}
+}
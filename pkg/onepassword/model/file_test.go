@@ -0,0 +1,44 @@
+package model
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFile_Content(t *testing.T) {
+	file := File{Name: "config.yaml"}
+	_, err := file.Content()
+	require.ErrorIs(t, err, ErrFileContentNotLoaded)
+
+	file.SetContent([]byte("content"))
+	content, err := file.Content()
+	require.NoError(t, err)
+	require.Equal(t, []byte("content"), content)
+}
+
+func TestFile_ContentLoader(t *testing.T) {
+	calls := 0
+	file := File{Name: "config.yaml"}
+	file.SetContentLoader(func() ([]byte, error) {
+		calls++
+		return []byte("content"), nil
+	})
+
+	// Copies share the loader, so the content is downloaded once.
+	copied := file
+	for _, f := range []File{file, copied} {
+		content, err := f.Content()
+		require.NoError(t, err)
+		require.Equal(t, []byte("content"), content)
+	}
+	require.Equal(t, 1, calls)
+
+	failing := File{Name: "broken"}
+	failing.SetContentLoader(func() ([]byte, error) {
+		return nil, errors.New("download failed")
+	})
+	_, err := failing.Content()
+	require.EqualError(t, err, "download failed")
+}
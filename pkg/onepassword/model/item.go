@@ -9,8 +9,11 @@ import (
 
 // Item represents 1Password item.
 type Item struct {
-	ID        string
-	VaultID   string
+	ID      string
+	VaultID string
+	Title   string
+	// Category is the item category in the Connect spelling, e.g. "API_CREDENTIAL", for both backends.
+	Category  string
 	Version   int
 	Tags      []string
 	URLs      []ItemURL
@@ -18,6 +21,38 @@ type Item struct {
 	Fields    []ItemField
 	Files     []File
 	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// Item categories in the spelling used by 1Password Connect.
+const (
+	CategoryLogin         = "LOGIN"
+	CategoryPassword      = "PASSWORD"
+	CategoryAPICredential = "API_CREDENTIAL"
+	CategoryServer        = "SERVER"
+	CategoryDatabase      = "DATABASE"
+	CategorySecureNote    = "SECURE_NOTE"
+	CategoryDocument      = "DOCUMENT"
+	CategorySSHKey        = "SSH_KEY"
+	CategoryCustom        = "CUSTOM"
+)
+
+// sdkCategories maps the SDK item categories whose Connect spelling is not the upper snake case form.
+var sdkCategories = map[sdk.ItemCategory]string{
+	sdk.ItemCategoryAPICredentials: CategoryAPICredential,
+	sdk.ItemCategoryEmail:          "EMAIL_ACCOUNT",
+	sdk.ItemCategoryRewards:        "REWARD_PROGRAM",
+	sdk.ItemCategoryRouter:         "WIRELESS_ROUTER",
+	sdk.ItemCategorySSHKey:         CategorySSHKey,
+	sdk.ItemCategoryUnsupported:    CategoryCustom,
+}
+
+// categoryFromSDK converts an SDK item category, e.g. "SecureNote", to the Connect spelling.
+func categoryFromSDK(category sdk.ItemCategory) string {
+	if c, ok := sdkCategories[category]; ok {
+		return c
+	}
+	return toUpperSnakeCase(string(category))
 }
 
 // ItemURL represents a URL associated with a 1Password item.
@@ -37,6 +72,8 @@ type ItemSection struct {
 func (i *Item) FromConnectItem(item *connect.Item) {
 	i.ID = item.ID
 	i.VaultID = item.Vault.ID
+	i.Title = item.Title
+	i.Category = string(item.Category)
 	i.Version = item.Version
 
 	i.Tags = append(i.Tags, item.Tags...)
@@ -72,6 +109,8 @@ func (i *Item) FromConnectItem(item *connect.Item) {
 			Value:     field.Value,
 			SectionID: sectionID,
 			FieldType: string(field.Type),
+			Purpose:   string(field.Purpose),
+			Address:   addressFromConnectValue(string(field.Type), field.Value),
 		})
 	}
 
@@ -84,12 +123,15 @@ func (i *Item) FromConnectItem(item *connect.Item) {
 	}
 
 	i.CreatedAt = item.CreatedAt
+	i.UpdatedAt = item.UpdatedAt
 }
 
 // FromSDKItem populates the Item from an SDK item.
 func (i *Item) FromSDKItem(item *sdk.Item) {
 	i.ID = item.ID
 	i.VaultID = item.VaultID
+	i.Title = item.Title
+	i.Category = categoryFromSDK(item.Category)
 	i.Version = int(item.Version)
 
 	i.Tags = make([]string, len(item.Tags))
@@ -122,6 +164,7 @@ func (i *Item) FromSDKItem(item *sdk.Item) {
 			Value:     field.Value,
 			SectionID: sectionID,
 			FieldType: string(field.FieldType),
+			Address:   addressFromSDKDetails(field.Details),
 		})
 	}
 
@@ -143,15 +186,19 @@ func (i *Item) FromSDKItem(item *sdk.Item) {
 	}
 
 	i.CreatedAt = item.CreatedAt
+	i.UpdatedAt = item.UpdatedAt
 }
 
 // FromSDKItemOverview populates the Item from an SDK item overview.
 func (i *Item) FromSDKItemOverview(item *sdk.ItemOverview) {
 	i.ID = item.ID
 	i.VaultID = item.VaultID
+	i.Title = item.Title
+	i.Category = categoryFromSDK(item.Category)
 
 	i.Tags = make([]string, len(item.Tags))
 	copy(i.Tags, item.Tags)
 
 	i.CreatedAt = item.CreatedAt
+	i.UpdatedAt = item.UpdatedAt
 }
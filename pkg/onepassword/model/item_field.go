@@ -1,5 +1,48 @@
 package model
 
+import (
+	"encoding/json"
+	"strings"
+
+	sdk "github.com/1password/onepassword-sdk-go"
+)
+
+// Field types in the spelling used by 1Password Connect. Use ItemField.Type to compare
+// against them, as the SDK backend reports field types in a different spelling.
+const (
+	FieldTypeString           = "STRING"
+	FieldTypeConcealed        = "CONCEALED"
+	FieldTypeEmail            = "EMAIL"
+	FieldTypeURL              = "URL"
+	FieldTypeOTP              = "OTP"
+	FieldTypeDate             = "DATE"
+	FieldTypeMonthYear        = "MONTH_YEAR"
+	FieldTypeMenu             = "MENU"
+	FieldTypeAddress          = "ADDRESS"
+	FieldTypePhone            = "PHONE"
+	FieldTypeReference        = "REFERENCE"
+	FieldTypeCreditCardNumber = "CREDIT_CARD_NUMBER"
+	FieldTypeCreditCardType   = "CREDIT_CARD_TYPE"
+	FieldTypeSSHKey           = "SSH_KEY"
+	FieldTypeUnknown          = "UNKNOWN"
+)
+
+// Field purposes of the built-in fields of Login and Password items.
+const (
+	FieldPurposeUsername = "USERNAME"
+	FieldPurposePassword = "PASSWORD"
+	FieldPurposeNotes    = "NOTES"
+)
+
+// sdkFieldTypes maps the SDK field types whose Connect spelling is not the upper snake case form.
+var sdkFieldTypes = map[string]string{
+	string(sdk.ItemFieldTypeText):        FieldTypeString,
+	string(sdk.ItemFieldTypeTOTP):        FieldTypeOTP,
+	string(sdk.ItemFieldTypeURL):         FieldTypeURL,
+	string(sdk.ItemFieldTypeSSHKey):      FieldTypeSSHKey,
+	string(sdk.ItemFieldTypeUnsupported): FieldTypeUnknown,
+}
+
 // ItemField Representation of a single field on an Item
 type ItemField struct {
 	ID        string
@@ -7,4 +50,85 @@ type ItemField struct {
 	Value     string
 	SectionID string
 	FieldType string
+	// Purpose is set on the built-in username, password and notes fields by the Connect backend.
+	Purpose string
+	// Address holds the components of an ADDRESS field, when the backend provides them.
+	Address *ItemAddress
+}
+
+// ItemAddress holds the components of an address field.
+type ItemAddress struct {
+	Street  string `json:"street"`
+	City    string `json:"city"`
+	State   string `json:"state"`
+	Zip     string `json:"zip"`
+	Country string `json:"country"`
+}
+
+// String renders the address on a single line, e.g. "1 Main St, Toronto, ON M5V 2T6, CA".
+func (a ItemAddress) String() string {
+	var parts []string
+	for _, part := range []string{a.Street, a.City, strings.TrimSpace(a.State + " " + a.Zip), a.Country} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// addressFromConnectValue returns the components of a Connect address field, which holds them
+// as a JSON object in its value, or nil if the value is not such an object.
+func addressFromConnectValue(fieldType, value string) *ItemAddress {
+	if fieldType != FieldTypeAddress || !strings.HasPrefix(strings.TrimSpace(value), "{") {
+		return nil
+	}
+	address := &ItemAddress{}
+	if err := json.Unmarshal([]byte(value), address); err != nil {
+		return nil
+	}
+	return address
+}
+
+// addressFromSDKDetails returns the components of an SDK address field, or nil if it has none.
+func addressFromSDKDetails(details *sdk.ItemFieldDetails) *ItemAddress {
+	// Address panics on details of another type.
+	if details == nil || details.Type != sdk.ItemFieldDetailsTypeVariantAddress {
+		return nil
+	}
+	sdkAddress := details.Address()
+	if sdkAddress == nil {
+		return nil
+	}
+	return &ItemAddress{
+		Street:  sdkAddress.Street,
+		City:    sdkAddress.City,
+		State:   sdkAddress.State,
+		Zip:     sdkAddress.Zip,
+		Country: sdkAddress.Country,
+	}
+}
+
+// Type returns the field type in the Connect spelling, e.g. "SSH_KEY" for both "SSH_KEY" and "SshKey".
+func (f ItemField) Type() string {
+	if t, ok := sdkFieldTypes[f.FieldType]; ok {
+		return t
+	}
+	if strings.ToUpper(f.FieldType) == f.FieldType {
+		return f.FieldType
+	}
+
+	// Convert the remaining SDK types from camel case, e.g. "MonthYear" to "MONTH_YEAR".
+	return toUpperSnakeCase(f.FieldType)
+}
+
+// toUpperSnakeCase converts a camel case SDK identifier, e.g. "MonthYear", to "MONTH_YEAR".
+func toUpperSnakeCase(s string) string {
+	var b strings.Builder
+	for i, r := range s {
+		if i > 0 && r >= 'A' && r <= 'Z' {
+			b.WriteByte('_')
+		}
+		b.WriteRune(r)
+	}
+	return strings.ToUpper(b.String())
 }
@@ -0,0 +1,51 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	sdk "github.com/1password/onepassword-sdk-go"
+)
+
+func TestItemField_Type(t *testing.T) {
+	tests := map[string]string{
+		"STRING":                                  FieldTypeString,
+		"SSH_KEY":                                 FieldTypeSSHKey,
+		string(sdk.ItemFieldTypeText):             FieldTypeString,
+		string(sdk.ItemFieldTypeConcealed):        FieldTypeConcealed,
+		string(sdk.ItemFieldTypeSSHKey):           FieldTypeSSHKey,
+		string(sdk.ItemFieldTypeTOTP):             FieldTypeOTP,
+		string(sdk.ItemFieldTypeURL):              FieldTypeURL,
+		string(sdk.ItemFieldTypeMonthYear):        FieldTypeMonthYear,
+		string(sdk.ItemFieldTypeCreditCardNumber): FieldTypeCreditCardNumber,
+		string(sdk.ItemFieldTypeUnsupported):      FieldTypeUnknown,
+		"":                                        "",
+	}
+
+	for fieldType, want := range tests {
+		t.Run(fieldType, func(t *testing.T) {
+			require.Equal(t, want, ItemField{FieldType: fieldType}.Type())
+		})
+	}
+}
+
+func TestItemAddress(t *testing.T) {
+	want := &ItemAddress{Street: "1 Main St", City: "Toronto", State: "ON", Zip: "M5V 2T6", Country: "CA"}
+
+	connectValue := `{"street":"1 Main St","city":"Toronto","state":"ON","zip":"M5V 2T6","country":"CA"}`
+	require.Equal(t, want, addressFromConnectValue(FieldTypeAddress, connectValue))
+	require.Nil(t, addressFromConnectValue(FieldTypeAddress, "1 Main St"))
+	require.Nil(t, addressFromConnectValue(FieldTypeString, connectValue))
+
+	details := sdk.NewItemFieldDetailsTypeVariantAddress(&sdk.AddressFieldDetails{
+		Street: "1 Main St", City: "Toronto", State: "ON", Zip: "M5V 2T6", Country: "CA",
+	})
+	require.Equal(t, want, addressFromSDKDetails(&details))
+	otpDetails := sdk.NewItemFieldDetailsTypeVariantOTP(&sdk.OTPFieldDetails{})
+	require.Nil(t, addressFromSDKDetails(&otpDetails))
+	require.Nil(t, addressFromSDKDetails(nil))
+
+	require.Equal(t, "1 Main St, Toronto, ON M5V 2T6, CA", want.String())
+	require.Equal(t, "Toronto, CA", ItemAddress{City: "Toronto", Country: "CA"}.String())
+}
@@ -16,8 +16,10 @@ func TestItem_FromConnectItem(t *testing.T) {
 		Vault: connect.ItemVault{
 			ID: "test-vault-id",
 		},
-		Version: 1,
-		Tags:    []string{"tag1", "tag2"},
+		Title:    "Test Item",
+		Category: connect.ApiCredential,
+		Version:  1,
+		Tags:     []string{"tag1", "tag2"},
 		Fields: []*connect.ItemField{
 			{
 				ID:    "f1",
@@ -40,10 +42,11 @@ func TestItem_FromConnectItem(t *testing.T) {
 				},
 			},
 			{
-				ID:    "f3",
-				Label: "field3",
-				Value: "value3",
-				Type:  "STRING",
+				ID:      "f3",
+				Label:   "field3",
+				Value:   "value3",
+				Type:    "STRING",
+				Purpose: "USERNAME",
 			},
 		},
 		Files: []*connect.File{
@@ -51,6 +54,7 @@ func TestItem_FromConnectItem(t *testing.T) {
 			{ID: "file2", Name: "file2.txt", Size: 1234},
 		},
 		CreatedAt: time.Now(),
+		UpdatedAt: time.Now().Add(time.Hour),
 	}
 
 	item := &Item{}
@@ -58,6 +62,8 @@ func TestItem_FromConnectItem(t *testing.T) {
 
 	require.Equal(t, connectItem.ID, item.ID)
 	require.Equal(t, connectItem.Vault.ID, item.VaultID)
+	require.Equal(t, connectItem.Title, item.Title)
+	require.Equal(t, CategoryAPICredential, item.Category)
 	require.Equal(t, connectItem.Version, item.Version)
 	require.ElementsMatch(t, connectItem.Tags, item.Tags)
 
@@ -66,6 +72,7 @@ func TestItem_FromConnectItem(t *testing.T) {
 		require.Equal(t, field.Value, item.Fields[i].Value)
 		require.Equal(t, field.ID, item.Fields[i].ID)
 		require.Equal(t, string(field.Type), item.Fields[i].FieldType)
+		require.Equal(t, string(field.Purpose), item.Fields[i].Purpose)
 	}
 
 	// Verify sections are built from field references.
@@ -85,15 +92,18 @@ func TestItem_FromConnectItem(t *testing.T) {
 	}
 
 	require.Equal(t, connectItem.CreatedAt, item.CreatedAt)
+	require.Equal(t, connectItem.UpdatedAt, item.UpdatedAt)
 }
 
 func TestItem_FromSDKItem(t *testing.T) {
 	sec1ID := "sec1"
 	sdkItem := &sdk.Item{
-		ID:      "test-item-id",
-		VaultID: "test-vault-id",
-		Version: 1,
-		Tags:    []string{"tag1", "tag2"},
+		ID:       "test-item-id",
+		VaultID:  "test-vault-id",
+		Title:    "Test Item",
+		Category: sdk.ItemCategoryAPICredentials,
+		Version:  1,
+		Tags:     []string{"tag1", "tag2"},
 		Sections: []sdk.ItemSection{
 			{ID: "sec1", Title: "Section One"},
 		},
@@ -106,6 +116,7 @@ func TestItem_FromSDKItem(t *testing.T) {
 			{Attributes: sdk.FileAttributes{Name: "file2.txt", Size: 1234}, FieldID: "file2"},
 		},
 		CreatedAt: time.Now(),
+		UpdatedAt: time.Now().Add(time.Hour),
 	}
 
 	item := &Item{}
@@ -113,6 +124,8 @@ func TestItem_FromSDKItem(t *testing.T) {
 
 	require.Equal(t, sdkItem.ID, item.ID)
 	require.Equal(t, sdkItem.VaultID, item.VaultID)
+	require.Equal(t, sdkItem.Title, item.Title)
+	require.Equal(t, CategoryAPICredential, item.Category)
 	require.Equal(t, int(sdkItem.Version), item.Version)
 	require.ElementsMatch(t, sdkItem.Tags, item.Tags)
 
@@ -139,14 +152,18 @@ func TestItem_FromSDKItem(t *testing.T) {
 	}
 
 	require.Equal(t, sdkItem.CreatedAt, item.CreatedAt)
+	require.Equal(t, sdkItem.UpdatedAt, item.UpdatedAt)
 }
 
 func TestItem_FromSDKItemOverview(t *testing.T) {
 	sdkItemOverview := &sdk.ItemOverview{
 		ID:        "test-item-id",
 		VaultID:   "test-vault-id",
+		Title:     "Test Item",
+		Category:  sdk.ItemCategorySecureNote,
 		Tags:      []string{"tag1", "tag2"},
 		CreatedAt: time.Now(),
+		UpdatedAt: time.Now().Add(time.Hour),
 	}
 
 	item := &Item{}
@@ -155,5 +172,22 @@ func TestItem_FromSDKItemOverview(t *testing.T) {
 	require.Equal(t, sdkItemOverview.ID, item.ID)
 	require.Equal(t, sdkItemOverview.VaultID, item.VaultID)
 	require.ElementsMatch(t, sdkItemOverview.Tags, item.Tags)
+	require.Equal(t, sdkItemOverview.Title, item.Title)
+	require.Equal(t, CategorySecureNote, item.Category)
 	require.Equal(t, sdkItemOverview.CreatedAt, item.CreatedAt)
+	require.Equal(t, sdkItemOverview.UpdatedAt, item.UpdatedAt)
+}
+
+func TestCategoryFromSDK(t *testing.T) {
+	tests := map[sdk.ItemCategory]string{
+		sdk.ItemCategoryLogin:          CategoryLogin,
+		sdk.ItemCategoryCreditCard:     "CREDIT_CARD",
+		sdk.ItemCategoryAPICredentials: CategoryAPICredential,
+		sdk.ItemCategorySSHKey:         CategorySSHKey,
+		sdk.ItemCategoryRouter:         "WIRELESS_ROUTER",
+		sdk.ItemCategoryUnsupported:    CategoryCustom,
+	}
+	for category, want := range tests {
+		require.Equal(t, want, categoryFromSDK(category), string(category))
+	}
 }
@@ -29,27 +29,34 @@ type SecretUpdateHandlerConfig struct {
 	ShouldAutoRestartWorkloadsGlobally bool
 	AllowEmptyValues                   bool
 	WatchedNamespaces                  []string
+	// Shadow compares the Secrets without a connection with a second backend. Nil disables shadow mode.
+	Shadow *ShadowComparer
 }
 
 func NewSecretUpdateHandler(
 	kubernetesClient client.Client,
 	apiReader client.Reader,
 	opClient opclient.Client,
+	connections *ConnectionClients,
 	config SecretUpdateHandlerConfig,
 ) *SecretUpdateHandler {
 	return &SecretUpdateHandler{
-		client:    kubernetesClient,
-		apiReader: apiReader,
-		opClient:  opClient,
-		config:    config,
+		client:      kubernetesClient,
+		apiReader:   apiReader,
+		opClient:    opClient,
+		connections: connections,
+		config:      config,
 	}
 }
 
 type SecretUpdateHandler struct {
 	client    client.Client
 	apiReader client.Reader
-	opClient  opclient.Client
-	config    SecretUpdateHandlerConfig
+	// opClient is the client of Secrets without a connection. It is nil when the operator was
+	// started without credentials.
+	opClient    opclient.Client
+	connections *ConnectionClients
+	config      SecretUpdateHandlerConfig
 }
 
 func (h *SecretUpdateHandler) UpdateKubernetesSecretsTask(ctx context.Context) error {
@@ -180,20 +187,26 @@ func (h *SecretUpdateHandler) updateKubernetesSecrets(ctx context.Context) (
 			continue
 		}
 
-		onePasswordItemCR := h.getOnePasswordItem(secret)
+		itemSpec, err := h.getOnePasswordItemSpec(ctx, secret)
+		if err != nil {
+			log.Error(err, fmt.Sprintf("failed to resolve the template of secret %s", secret.Name))
+			continue
+		}
 
 		var onePasswordItemPath string
-		var secretTemplate *onepasswordv1.SecretTemplate
-		var imagePullSecret *onepasswordv1.ImagePullSecretConfig
-		if onePasswordItemCR != nil {
-			onePasswordItemPath = onePasswordItemCR.Spec.ItemPath
-			secretTemplate = onePasswordItemCR.Spec.Template
-			imagePullSecret = onePasswordItemCR.Spec.ImagePullSecret
+		if itemSpec != nil {
+			onePasswordItemPath = itemSpec.ItemPath
 		} else {
 			onePasswordItemPath = secret.Annotations[ItemPathAnnotation]
 		}
 
-		item, err := GetOnePasswordItemByPath(ctx, h.opClient, onePasswordItemPath)
+		opClient, err := h.opClientForSecret(ctx, secret, itemSpec)
+		if err != nil {
+			log.Error(err, fmt.Sprintf("failed to get the 1Password client of secret %s", secret.Name))
+			continue
+		}
+
+		item, err := GetOnePasswordItemByPath(ctx, opClient, onePasswordItemPath)
 		if err != nil {
 			log.Error(err, fmt.Sprintf("failed to retrieve 1Password item at path %s for secret %s",
 				secret.Annotations[ItemPathAnnotation], secret.Name,
@@ -201,10 +214,26 @@ func (h *SecretUpdateHandler) updateKubernetesSecrets(ctx context.Context) (
 			continue
 		}
 
+		linkedItems, err := GetReferencedItems(ctx, opClient, itemSpec)
+		if err != nil {
+			log.Error(err, fmt.Sprintf("failed to retrieve referenced 1Password items for secret %s", secret.Name))
+			continue
+		}
+		h.compareWithShadow(ctx, &secret, onePasswordItemPath, item, linkedItems, itemSpec)
+
 		itemVersion := fmt.Sprint(item.Version)
 		itemPathString := fmt.Sprintf("vaults/%v/items/%v", item.VaultID, item.ID)
+		linkedVersions := kubeSecrets.LinkedItemVersions(linkedItems)
+		var templateHash string
+		if itemSpec != nil {
+			templateHash = kubeSecrets.TemplateHash(itemSpec.Template)
+		}
+		mappingHash := kubeSecrets.MappingHash(itemSpec)
 
-		if currentVersion != itemVersion || secret.Annotations[ItemPathAnnotation] != itemPathString {
+		if currentVersion != itemVersion || secret.Annotations[ItemPathAnnotation] != itemPathString ||
+			secret.Annotations[kubeSecrets.LinkedItemVersionsAnnotation] != linkedVersions ||
+			secret.Annotations[kubeSecrets.TemplateHashAnnotation] != templateHash ||
+			secret.Annotations[kubeSecrets.MappingHashAnnotation] != mappingHash {
 			if isItemLockedForForcedRestarts(item) {
 				log.V(logs.DebugLevel).Info(fmt.Sprintf(
 					"Secret '%v' has been updated in 1Password but is set to be ignored. "+
@@ -213,18 +242,27 @@ func (h *SecretUpdateHandler) updateKubernetesSecrets(ctx context.Context) (
 				))
 				secret.Annotations[VersionAnnotation] = itemVersion
 				secret.Annotations[ItemPathAnnotation] = itemPathString
+				setLinkedItemVersions(&secret, linkedVersions)
+				setTemplateHash(&secret, templateHash)
+				setAnnotation(&secret, kubeSecrets.MappingHashAnnotation, mappingHash)
 				if err := h.client.Update(ctx, &secret); err != nil {
 					log.Error(err, fmt.Sprintf("failed to update secret %s annotations to version %s", secret.Name, itemVersion))
 					continue
 				}
 				continue
 			}
+			data, err := kubeSecrets.BuildKubernetesSecretData(*item, h.config.AllowEmptyValues, itemSpec, linkedItems)
+			if err != nil {
+				log.Error(err, fmt.Sprintf("failed to build data of secret %s for version %s", secret.Name, itemVersion))
+				continue
+			}
 			log.Info(fmt.Sprintf("Updating kubernetes secret '%v'", secret.GetName()))
 			secret.Annotations[VersionAnnotation] = itemVersion
 			secret.Annotations[ItemPathAnnotation] = itemPathString
-			secret.Data = kubeSecrets.BuildKubernetesSecretData(
-				*item, h.config.AllowEmptyValues, secretTemplate, imagePullSecret,
-			)
+			setLinkedItemVersions(&secret, linkedVersions)
+			setTemplateHash(&secret, templateHash)
+			setAnnotation(&secret, kubeSecrets.MappingHashAnnotation, mappingHash)
+			secret.Data = data
 			log.V(logs.DebugLevel).Info(fmt.Sprintf("New secret path: %v and version: %v",
 				secret.Annotations[ItemPathAnnotation], secret.Annotations[VersionAnnotation],
 			))
@@ -241,6 +279,46 @@ func (h *SecretUpdateHandler) updateKubernetesSecrets(ctx context.Context) (
 	return updatedSecrets, nil
 }
 
+// compareWithShadow compares the data of a Secret read with the default client with the shadow backend,
+// in the background.
+func (h *SecretUpdateHandler) compareWithShadow(
+	ctx context.Context,
+	secret *corev1.Secret,
+	path string,
+	item *model.Item,
+	linkedItems map[string]*model.Item,
+	itemSpec *onepasswordv1.OnePasswordItemSpec,
+) {
+	if h.config.Shadow == nil {
+		return
+	}
+	if ref, err := ConnectionReferenceFor(itemSpec, secret.Annotations); err != nil || ref != nil {
+		return
+	}
+	h.config.Shadow.CompareInBackground(ctx, secret, path, item, linkedItems, h.config.AllowEmptyValues, itemSpec)
+}
+
+func setLinkedItemVersions(secret *corev1.Secret, linkedVersions string) {
+	if linkedVersions == "" {
+		delete(secret.Annotations, kubeSecrets.LinkedItemVersionsAnnotation)
+		return
+	}
+	secret.Annotations[kubeSecrets.LinkedItemVersionsAnnotation] = linkedVersions
+}
+
+func setTemplateHash(secret *corev1.Secret, templateHash string) {
+	setAnnotation(secret, kubeSecrets.TemplateHashAnnotation, templateHash)
+}
+
+// setAnnotation sets the annotation of the secret, or removes it when the value is empty.
+func setAnnotation(secret *corev1.Secret, key, value string) {
+	if value == "" {
+		delete(secret.Annotations, key)
+		return
+	}
+	secret.Annotations[key] = value
+}
+
 func isItemLockedForForcedRestarts(item *model.Item) bool {
 	tags := item.Tags
 	for i := 0; i < len(tags); i++ {
@@ -295,6 +373,68 @@ func (h *SecretUpdateHandler) getIsSetForAutoRestartByNamespaceMap(
 	return namespacesMap, nil
 }
 
+// getOnePasswordItemSpec returns the spec of the OnePasswordItem or ClusterOnePasswordItem
+// the secret was created from, with its template reference resolved, or nil if there is none.
+func (h *SecretUpdateHandler) getOnePasswordItemSpec(
+	ctx context.Context,
+	secret corev1.Secret,
+) (*onepasswordv1.OnePasswordItemSpec, error) {
+	if secret.Labels[ClusterItemLabel] != "" {
+		clusterItem := h.getOwningClusterOnePasswordItem(secret)
+		if clusterItem != nil {
+			return kubeSecrets.ResolveSecretTemplate(ctx, h.client, "", &clusterItem.Spec.OnePasswordItemSpec)
+		}
+		return nil, nil
+	}
+
+	onePasswordItem := h.getOnePasswordItem(secret)
+	if onePasswordItem != nil {
+		return kubeSecrets.ResolveSecretTemplate(ctx, h.client, secret.Namespace, &onePasswordItem.Spec)
+	}
+	return nil, nil
+}
+
+// opClientForSecret returns the client of the connection selected by the spec of the Secret's
+// OnePasswordItem, or recorded in the ConnectionAnnotation of the Secret.
+func (h *SecretUpdateHandler) opClientForSecret(
+	ctx context.Context,
+	secret corev1.Secret,
+	itemSpec *onepasswordv1.OnePasswordItemSpec,
+) (opclient.Client, error) {
+	ref, err := ConnectionReferenceFor(itemSpec, secret.Annotations)
+	if err != nil {
+		return nil, err
+	}
+	namespace := secret.Namespace
+	if h.getOwningClusterOnePasswordItem(secret) != nil {
+		// Secrets of ClusterOnePasswordItems can only use cluster connections, like their item. The label
+		// alone is not trusted, as anyone creating Secrets in the namespace can set it.
+		namespace = ""
+	}
+	return ClientForConnection(ctx, h.connections, h.opClient, namespace, ref)
+}
+
+// getOwningClusterOnePasswordItem returns the ClusterOnePasswordItem named by the ClusterItemLabel of the
+// Secret, if it exists and owns the Secret.
+func (h *SecretUpdateHandler) getOwningClusterOnePasswordItem(
+	secret corev1.Secret,
+) *onepasswordv1.ClusterOnePasswordItem {
+	clusterItemName := secret.Labels[ClusterItemLabel]
+	if clusterItemName == "" {
+		return nil
+	}
+	clusterItem := &onepasswordv1.ClusterOnePasswordItem{}
+	if err := h.client.Get(context.TODO(), client.ObjectKey{Name: clusterItemName}, clusterItem); err != nil {
+		return nil
+	}
+	for _, ref := range secret.OwnerReferences {
+		if ref.UID == clusterItem.UID {
+			return clusterItem
+		}
+	}
+	return nil
+}
+
 func (h *SecretUpdateHandler) getOnePasswordItem(secret corev1.Secret) *onepasswordv1.OnePasswordItem {
 	onePasswordItem := &onepasswordv1.OnePasswordItem{}
 
@@ -9,7 +9,10 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 
+	onepasswordv1 "github.com/1Password/onepassword-operator/api/v1"
+	kubeSecrets "github.com/1Password/onepassword-operator/pkg/kubernetessecrets"
 	"github.com/1Password/onepassword-operator/pkg/mocks"
+	"github.com/1Password/onepassword-operator/pkg/onepassword/client/testing/chaos"
 	"github.com/1Password/onepassword-operator/pkg/onepassword/model"
 
 	appsv1 "k8s.io/api/apps/v1"
@@ -18,6 +21,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/kubectl/pkg/scheme"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
@@ -1209,6 +1213,248 @@ func TestUpdateSecretHandlerAllowEmptyValues(t *testing.T) {
 	}
 }
 
+func TestUpdateSecretHandlerClusterOnePasswordItem(t *testing.T) {
+	ctx := context.Background()
+	s := runtime.NewScheme()
+	assert.NoError(t, clientgoscheme.AddToScheme(s))
+	assert.NoError(t, onepasswordv1.AddToScheme(s))
+
+	clusterItem := &onepasswordv1.ClusterOnePasswordItem{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "shared-credentials",
+			UID:  "cluster-item-uid",
+		},
+		Spec: onepasswordv1.ClusterOnePasswordItemSpec{
+			OnePasswordItemSpec: onepasswordv1.OnePasswordItemSpec{
+				ItemPath: itemPath,
+				Template: &onepasswordv1.SecretTemplate{
+					Data: map[string]string{
+						"credentials": "{{ .Fields.username }}:{{ .Fields.password }}",
+					},
+				},
+			},
+		},
+	}
+	existingSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      clusterItem.Name,
+			Namespace: namespace,
+			Labels: map[string]string{
+				ClusterItemLabel: clusterItem.Name,
+			},
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: onepasswordv1.GroupVersion.String(),
+				Kind:       "ClusterOnePasswordItem",
+				Name:       clusterItem.Name,
+				UID:        clusterItem.UID,
+			}},
+			Annotations: map[string]string{
+				VersionAnnotation:  "old-version",
+				ItemPathAnnotation: itemPath,
+			},
+		},
+		Data: map[string][]byte{
+			"credentials": []byte("old-value"),
+		},
+	}
+
+	cl := fake.NewClientBuilder().WithScheme(s).WithRuntimeObjects(defaultNamespace, clusterItem, existingSecret).Build()
+
+	mockOpClient := &mocks.TestClient{}
+	mockOpClient.On("GetItemByID", mock.Anything, mock.Anything).Return(createItem(), nil)
+	mockOpClient.On("GetVaultsByTitle", mock.Anything).Return([]model.Vault{}, nil)
+
+	h := &SecretUpdateHandler{
+		client:    cl,
+		apiReader: cl,
+		opClient:  mockOpClient,
+	}
+
+	err := h.UpdateKubernetesSecretsTask(ctx)
+	assert.NoError(t, err)
+
+	updatedSecret := &corev1.Secret{}
+	err = cl.Get(ctx, types.NamespacedName{Name: clusterItem.Name, Namespace: namespace}, updatedSecret)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]byte{
+		"credentials": []byte(username + ":" + password),
+	}, updatedSecret.Data)
+	assert.Equal(t, fmt.Sprint(itemVersion), updatedSecret.Annotations[VersionAnnotation])
+}
+
+func TestUpdateSecretHandlerTemplateChange(t *testing.T) {
+	ctx := context.Background()
+	s := runtime.NewScheme()
+	assert.NoError(t, clientgoscheme.AddToScheme(s))
+	assert.NoError(t, onepasswordv1.AddToScheme(s))
+
+	sharedTemplate := &onepasswordv1.OnePasswordTemplate{
+		ObjectMeta: metav1.ObjectMeta{Name: "credentials", Namespace: namespace},
+		Spec: onepasswordv1.OnePasswordTemplateSpec{
+			Data: map[string]string{
+				"credentials": `{{ template "userinfo" . }}`,
+			},
+			Partials: map[string]string{
+				"userinfo": "{{ .Fields.username }}:{{ .Fields.password }}",
+			},
+		},
+	}
+	onePasswordItem := &onepasswordv1.OnePasswordItem{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec: onepasswordv1.OnePasswordItemSpec{
+			ItemPath: itemPath,
+			Template: &onepasswordv1.SecretTemplate{
+				Ref: &onepasswordv1.TemplateReference{Name: sharedTemplate.Name},
+			},
+		},
+	}
+	// The secret is up to date with the item but was rendered from an older template.
+	existingSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Annotations: map[string]string{
+				VersionAnnotation:                  fmt.Sprint(itemVersion),
+				ItemPathAnnotation:                 itemPath,
+				kubeSecrets.TemplateHashAnnotation: "old-hash",
+			},
+		},
+		Data: map[string][]byte{
+			"credentials": []byte("old-value"),
+		},
+	}
+
+	cl := fake.NewClientBuilder().WithScheme(s).
+		WithRuntimeObjects(defaultNamespace, sharedTemplate, onePasswordItem, existingSecret).Build()
+
+	mockOpClient := &mocks.TestClient{}
+	mockOpClient.On("GetItemByID", mock.Anything, mock.Anything).Return(createItem(), nil)
+	mockOpClient.On("GetVaultsByTitle", mock.Anything).Return([]model.Vault{}, nil)
+
+	h := &SecretUpdateHandler{
+		client:    cl,
+		apiReader: cl,
+		opClient:  mockOpClient,
+	}
+
+	err := h.UpdateKubernetesSecretsTask(ctx)
+	assert.NoError(t, err)
+
+	updatedSecret := &corev1.Secret{}
+	err = cl.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, updatedSecret)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]byte{
+		"credentials": []byte(username + ":" + password),
+	}, updatedSecret.Data)
+	assert.NotEqual(t, "old-hash", updatedSecret.Annotations[kubeSecrets.TemplateHashAnnotation])
+}
+
+func TestUpdateSecretHandlerMappingChange(t *testing.T) {
+	ctx := context.Background()
+	s := runtime.NewScheme()
+	assert.NoError(t, clientgoscheme.AddToScheme(s))
+	assert.NoError(t, onepasswordv1.AddToScheme(s))
+
+	// The spec was edited after the secret was created from the current item version.
+	onePasswordItem := &onepasswordv1.OnePasswordItem{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec: onepasswordv1.OnePasswordItemSpec{
+			ItemPath:     itemPath,
+			KeyTransform: &onepasswordv1.KeyTransform{Case: onepasswordv1.KeyCaseUpperSnake},
+		},
+	}
+	existingSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Annotations: map[string]string{
+				VersionAnnotation:  fmt.Sprint(itemVersion),
+				ItemPathAnnotation: itemPath,
+			},
+		},
+		Data: expectedSecretData,
+	}
+
+	cl := fake.NewClientBuilder().WithScheme(s).
+		WithRuntimeObjects(defaultNamespace, onePasswordItem, existingSecret).Build()
+
+	mockOpClient := &mocks.TestClient{}
+	mockOpClient.On("GetItemByID", mock.Anything, mock.Anything).Return(createItem(), nil)
+	mockOpClient.On("GetVaultsByTitle", mock.Anything).Return([]model.Vault{}, nil)
+
+	h := &SecretUpdateHandler{
+		client:    cl,
+		apiReader: cl,
+		opClient:  mockOpClient,
+	}
+	assert.NoError(t, h.UpdateKubernetesSecretsTask(ctx))
+
+	updatedSecret := &corev1.Secret{}
+	assert.NoError(t, cl.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, updatedSecret))
+	assert.Equal(t, map[string][]byte{
+		"USERNAME": []byte(username),
+		"PASSWORD": []byte(password),
+	}, updatedSecret.Data)
+	assert.Equal(t, kubeSecrets.MappingHash(&onePasswordItem.Spec),
+		updatedSecret.Annotations[kubeSecrets.MappingHashAnnotation])
+}
+
+func TestUpdateSecretHandlerBackendFailures(t *testing.T) {
+	ctx := context.Background()
+	outdatedSecret := func(name string) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+				Annotations: map[string]string{
+					VersionAnnotation:  "1",
+					ItemPathAnnotation: itemPath,
+				},
+			},
+			Data: map[string][]byte{"password": []byte("old-value")},
+		}
+	}
+	cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).
+		WithRuntimeObjects(defaultNamespace, outdatedSecret("first"), outdatedSecret("second")).Build()
+
+	mockOpClient := &mocks.TestClient{}
+	mockOpClient.On("GetItemByID", mock.Anything, mock.Anything).Return(createItem(), nil)
+	mockOpClient.On("GetItemsByTitle", mock.Anything, mock.Anything).Return([]model.Item{}, nil)
+	mockOpClient.On("GetVaultsByTitle", mock.Anything).Return([]model.Vault{}, nil)
+	chaosOpClient := chaos.New(mockOpClient, chaos.Fault{Operation: "GetItemByID", Err: chaos.ServerError(), Times: 1})
+
+	h := &SecretUpdateHandler{
+		client:    cl,
+		apiReader: cl,
+		opClient:  chaosOpClient,
+	}
+	updatedSecrets := func() int {
+		t.Helper()
+		updated := 0
+		for _, name := range []string{"first", "second"} {
+			secret := &corev1.Secret{}
+			assert.NoError(t, cl.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, secret))
+			if secret.Annotations[VersionAnnotation] == fmt.Sprint(itemVersion) {
+				assert.Equal(t, expectedSecretData, secret.Data)
+				updated++
+			}
+		}
+		return updated
+	}
+
+	// A failing call only skips its Secret until the next poll.
+	assert.NoError(t, h.UpdateKubernetesSecretsTask(ctx))
+	assert.Equal(t, 1, updatedSecrets())
+	assert.NoError(t, h.UpdateKubernetesSecretsTask(ctx))
+	assert.Equal(t, 2, updatedSecrets())
+
+	// A hanging backend does not block the poll past its context.
+	chaosOpClient.Inject(chaos.Fault{Operation: "GetItemByID", Latency: time.Hour})
+	timeoutCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	assert.NoError(t, h.UpdateKubernetesSecretsTask(timeoutCtx))
+}
+
 func TestIsUpdatedSecret(t *testing.T) {
 	secretName := "test-secret"
 	updatedSecrets := map[string]*corev1.Secret{
@@ -0,0 +1,262 @@
+package onepassword
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	onepasswordv1 "github.com/1Password/onepassword-operator/api/v1"
+	kubeSecrets "github.com/1Password/onepassword-operator/pkg/kubernetessecrets"
+	opclient "github.com/1Password/onepassword-operator/pkg/onepassword/client"
+	"github.com/1Password/onepassword-operator/pkg/onepassword/model"
+)
+
+// DefaultShadowCompareTimeout bounds a comparison started with CompareInBackground.
+const DefaultShadowCompareTimeout = 30 * time.Second
+
+// Reasons of the Events recorded by the shadow comparer.
+const (
+	ShadowMismatchReason = "ShadowMismatch"
+	ShadowFailedReason   = "ShadowFailed"
+)
+
+// Results of a comparison recorded by the shadow comparisons metric.
+const (
+	shadowResultMatch    = "match"
+	shadowResultMismatch = "mismatch"
+	shadowResultError    = "error"
+)
+
+var (
+	shadowComparisons = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "onepassword_operator_shadow_comparisons_total",
+			Help: "Number of Secrets rendered through the shadow backend and compared, by result.",
+		},
+		[]string{"result"},
+	)
+	shadowKeyDifferences = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "onepassword_operator_shadow_key_differences_total",
+			Help: "Number of Secret keys rendered differently by the shadow backend, by kind of difference.",
+		},
+		[]string{"difference"},
+	)
+)
+
+func init() {
+	metrics.Registry.MustRegister(shadowComparisons, shadowKeyDifferences)
+}
+
+// ShadowComparer fetches items through a second backend, e.g. a Service Account while Connect serves
+// the Secrets, and reports how the Secret data rendered from both backends differ. It never changes
+// the Secrets, and never reports values: only key names and keyed hashes of the values.
+type ShadowComparer struct {
+	client   opclient.Client
+	recorder record.EventRecorder
+	// hashKey keys the hashes of the values, so they can be compared to each other but not to guesses.
+	hashKey []byte
+	timeout time.Duration
+
+	mu sync.Mutex
+	// running holds the objects whose comparison runs in the background.
+	running map[types.NamespacedName]bool
+}
+
+// NewShadowComparer creates a ShadowComparer fetching items with the shadow client and recording
+// differences as Events with the recorder.
+func NewShadowComparer(shadowClient opclient.Client, recorder record.EventRecorder) *ShadowComparer {
+	hashKey := make([]byte, 32)
+	_, _ = rand.Read(hashKey)
+	return &ShadowComparer{
+		client:   shadowClient,
+		recorder: recorder,
+		hashKey:  hashKey,
+		timeout:  DefaultShadowCompareTimeout,
+		running:  map[types.NamespacedName]bool{},
+	}
+}
+
+// SecretDataDiff describes how the Secret data rendered by the shadow backend differs.
+type SecretDataDiff struct {
+	// MissingKeys are only rendered by the primary backend.
+	MissingKeys []string
+	// ExtraKeys are only rendered by the shadow backend.
+	ExtraKeys []string
+	// ChangedKeys are rendered by both backends with different values.
+	ChangedKeys []ChangedKey
+}
+
+// ChangedKey is a key whose value differs between the backends.
+type ChangedKey struct {
+	Key         string
+	PrimaryHash string
+	ShadowHash  string
+}
+
+// Empty reports whether both backends rendered the same Secret data.
+func (d SecretDataDiff) Empty() bool {
+	return len(d.MissingKeys) == 0 && len(d.ExtraKeys) == 0 && len(d.ChangedKeys) == 0
+}
+
+func (d SecretDataDiff) String() string {
+	var parts []string
+	if len(d.MissingKeys) > 0 {
+		parts = append(parts, fmt.Sprintf("missing keys %s", strings.Join(d.MissingKeys, ", ")))
+	}
+	if len(d.ExtraKeys) > 0 {
+		parts = append(parts, fmt.Sprintf("extra keys %s", strings.Join(d.ExtraKeys, ", ")))
+	}
+	if len(d.ChangedKeys) > 0 {
+		changed := make([]string, len(d.ChangedKeys))
+		for i, key := range d.ChangedKeys {
+			changed[i] = fmt.Sprintf("%s (%s != %s)", key.Key, key.PrimaryHash, key.ShadowHash)
+		}
+		parts = append(parts, fmt.Sprintf("different values for keys %s", strings.Join(changed, ", ")))
+	}
+	return strings.Join(parts, "; ")
+}
+
+// DiffSecretData compares the Secret data rendered from the primary and the shadow backends.
+func (s *ShadowComparer) DiffSecretData(primary, shadow map[string][]byte) SecretDataDiff {
+	var diff SecretDataDiff
+	for key, value := range primary {
+		shadowValue, ok := shadow[key]
+		switch {
+		case !ok:
+			diff.MissingKeys = append(diff.MissingKeys, key)
+		case !hmac.Equal(value, shadowValue):
+			diff.ChangedKeys = append(diff.ChangedKeys, ChangedKey{
+				Key:         key,
+				PrimaryHash: s.hash(value),
+				ShadowHash:  s.hash(shadowValue),
+			})
+		}
+	}
+	for key := range shadow {
+		if _, ok := primary[key]; !ok {
+			diff.ExtraKeys = append(diff.ExtraKeys, key)
+		}
+	}
+	sort.Strings(diff.MissingKeys)
+	sort.Strings(diff.ExtraKeys)
+	sort.Slice(diff.ChangedKeys, func(i, j int) bool { return diff.ChangedKeys[i].Key < diff.ChangedKeys[j].Key })
+	return diff
+}
+
+// CompareInBackground runs Compare in the background with its own timeout, so a slow shadow backend
+// can't delay the updates of the Secrets. It is skipped while the previous comparison of the object runs.
+func (s *ShadowComparer) CompareInBackground(
+	ctx context.Context,
+	object client.Object,
+	path string,
+	primaryItem *model.Item,
+	primaryLinkedItems map[string]*model.Item,
+	allowEmptyValues bool,
+	spec *onepasswordv1.OnePasswordItemSpec,
+) {
+	key := client.ObjectKeyFromObject(object)
+	s.mu.Lock()
+	if s.running[key] {
+		s.mu.Unlock()
+		return
+	}
+	s.running[key] = true
+	s.mu.Unlock()
+
+	object = object.DeepCopyObject().(client.Object)
+	go func() {
+		defer func() {
+			s.mu.Lock()
+			delete(s.running, key)
+			s.mu.Unlock()
+		}()
+		ctx, cancel := context.WithTimeout(ctx, s.timeout)
+		defer cancel()
+		s.Compare(ctx, object, path, primaryItem, primaryLinkedItems, allowEmptyValues, spec)
+	}()
+}
+
+// Compare renders the Secret data of the item at the path through the shadow backend and reports how
+// it differs from the data rendered from the primary item on the object, e.g. the Secret. Failures are
+// reported the same way and never returned, so shadow mode can't affect the Secrets being served.
+func (s *ShadowComparer) Compare(
+	ctx context.Context,
+	object runtime.Object,
+	path string,
+	primaryItem *model.Item,
+	primaryLinkedItems map[string]*model.Item,
+	allowEmptyValues bool,
+	spec *onepasswordv1.OnePasswordItemSpec,
+) {
+	diff, err := s.compare(ctx, path, primaryItem, primaryLinkedItems, allowEmptyValues, spec)
+	if err != nil {
+		shadowComparisons.WithLabelValues(shadowResultError).Inc()
+		log.Error(err, "failed to compare the Secret data with the shadow backend", "itemPath", path)
+		s.recorder.Event(object, corev1.EventTypeWarning, ShadowFailedReason,
+			fmt.Sprintf("Failed to render the Secret data with the shadow backend: %s", err))
+		return
+	}
+	if diff.Empty() {
+		shadowComparisons.WithLabelValues(shadowResultMatch).Inc()
+		return
+	}
+
+	shadowComparisons.WithLabelValues(shadowResultMismatch).Inc()
+	shadowKeyDifferences.WithLabelValues("missing").Add(float64(len(diff.MissingKeys)))
+	shadowKeyDifferences.WithLabelValues("extra").Add(float64(len(diff.ExtraKeys)))
+	shadowKeyDifferences.WithLabelValues("changed").Add(float64(len(diff.ChangedKeys)))
+	s.recorder.Event(object, corev1.EventTypeWarning, ShadowMismatchReason,
+		fmt.Sprintf("The shadow backend renders different Secret data: %s", diff))
+}
+
+func (s *ShadowComparer) compare(
+	ctx context.Context,
+	path string,
+	primaryItem *model.Item,
+	primaryLinkedItems map[string]*model.Item,
+	allowEmptyValues bool,
+	spec *onepasswordv1.OnePasswordItemSpec,
+) (SecretDataDiff, error) {
+	// Keystores and htpasswd files are salted, so their inputs are compared instead.
+	primary, err := kubeSecrets.BuildComparableSecretData(*primaryItem, allowEmptyValues, spec, primaryLinkedItems)
+	if err != nil {
+		return SecretDataDiff{}, fmt.Errorf("failed to render the Secret data of the primary backend: %w", err)
+	}
+
+	item, err := GetOnePasswordItemByPath(ctx, s.client, path)
+	if err != nil {
+		return SecretDataDiff{}, err
+	}
+	linkedItems, err := GetReferencedItems(ctx, s.client, spec)
+	if err != nil {
+		return SecretDataDiff{}, err
+	}
+	shadow, err := kubeSecrets.BuildComparableSecretData(*item, allowEmptyValues, spec, linkedItems)
+	if err != nil {
+		return SecretDataDiff{}, err
+	}
+	return s.DiffSecretData(primary, shadow), nil
+}
+
+// hash returns a short keyed hash of the value.
+func (s *ShadowComparer) hash(value []byte) string {
+	mac := hmac.New(sha256.New, s.hashKey)
+	mac.Write(value)
+	return "hmac-sha256:" + hex.EncodeToString(mac.Sum(nil))[:12]
+}
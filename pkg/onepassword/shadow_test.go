@@ -0,0 +1,194 @@
+package onepassword
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/kubectl/pkg/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	onepasswordv1 "github.com/1Password/onepassword-operator/api/v1"
+	"github.com/1Password/onepassword-operator/pkg/mocks"
+	"github.com/1Password/onepassword-operator/pkg/onepassword/client/testing/chaos"
+	"github.com/1Password/onepassword-operator/pkg/onepassword/model"
+)
+
+func TestShadowComparer_DiffSecretData(t *testing.T) {
+	comparer := NewShadowComparer(&mocks.TestClient{}, record.NewFakeRecorder(1))
+
+	diff := comparer.DiffSecretData(
+		map[string][]byte{"username": []byte("admin"), "password": []byte("s3cr3t"), "notes": []byte("")},
+		map[string][]byte{"username": []byte("admin"), "password": []byte("other"), "website": []byte("url")},
+	)
+	assert.False(t, diff.Empty())
+	assert.Equal(t, []string{"notes"}, diff.MissingKeys)
+	assert.Equal(t, []string{"website"}, diff.ExtraKeys)
+	require.Len(t, diff.ChangedKeys, 1)
+	assert.Equal(t, "password", diff.ChangedKeys[0].Key)
+	assert.NotEqual(t, diff.ChangedKeys[0].PrimaryHash, diff.ChangedKeys[0].ShadowHash)
+	assert.NotContains(t, diff.String(), "s3cr3t")
+	assert.NotContains(t, diff.String(), "other")
+	assert.Equal(t, comparer.hash([]byte("s3cr3t")), diff.ChangedKeys[0].PrimaryHash, "hashes should be stable")
+
+	assert.True(t, comparer.DiffSecretData(
+		map[string][]byte{"username": []byte("admin")},
+		map[string][]byte{"username": []byte("admin")},
+	).Empty())
+}
+
+func TestShadowComparer_Compare(t *testing.T) {
+	primaryItem := createItem()
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "secret", Namespace: namespace}}
+
+	t.Run("should report the differences without values", func(t *testing.T) {
+		shadowClient := &mocks.TestClient{}
+		shadowClient.On("GetVaultsByTitle", mock.Anything).Return([]model.Vault{}, nil)
+		shadowClient.On("GetItemByID", vaultId, itemId).Return(&model.Item{
+			ID:      itemId,
+			VaultID: vaultId,
+			Fields: []model.ItemField{
+				{Label: "username", Value: username},
+				{Label: "password", Value: "rotated"},
+			},
+		}, nil)
+		recorder := record.NewFakeRecorder(1)
+
+		NewShadowComparer(shadowClient, recorder).
+			Compare(context.Background(), secret, itemPath, primaryItem, nil, false, nil)
+		event := <-recorder.Events
+		assert.Contains(t, event, ShadowMismatchReason)
+		assert.Contains(t, event, "different values for keys password")
+		assert.NotContains(t, event, password)
+		assert.NotContains(t, event, "rotated")
+	})
+
+	t.Run("should not record matching data", func(t *testing.T) {
+		shadowClient := &mocks.TestClient{}
+		shadowClient.On("GetVaultsByTitle", mock.Anything).Return([]model.Vault{}, nil)
+		shadowClient.On("GetItemByID", vaultId, itemId).Return(createItem(), nil)
+		recorder := record.NewFakeRecorder(1)
+
+		NewShadowComparer(shadowClient, recorder).
+			Compare(context.Background(), secret, itemPath, primaryItem, nil, false, nil)
+		assert.Empty(t, recorder.Events)
+	})
+
+	t.Run("should compare the inputs of salted encodings", func(t *testing.T) {
+		spec := &onepasswordv1.OnePasswordItemSpec{
+			ItemPath: itemPath,
+			Htpasswd: &onepasswordv1.HtpasswdConfig{
+				Users: []onepasswordv1.HtpasswdUser{{UsernameField: "username", PasswordField: "password"}},
+				Cost:  4,
+			},
+		}
+		shadowClient := &mocks.TestClient{}
+		shadowClient.On("GetVaultsByTitle", mock.Anything).Return([]model.Vault{}, nil)
+		shadowClient.On("GetItemByID", vaultId, itemId).Return(createItem(), nil)
+		recorder := record.NewFakeRecorder(1)
+
+		NewShadowComparer(shadowClient, recorder).
+			Compare(context.Background(), secret, itemPath, primaryItem, nil, false, spec)
+		assert.Empty(t, recorder.Events, "salted hashes of the same password should match")
+
+		rotated := createItem()
+		rotated.Fields[1].Value = "rotated"
+		shadowClient = &mocks.TestClient{}
+		shadowClient.On("GetVaultsByTitle", mock.Anything).Return([]model.Vault{}, nil)
+		shadowClient.On("GetItemByID", vaultId, itemId).Return(rotated, nil)
+
+		NewShadowComparer(shadowClient, recorder).
+			Compare(context.Background(), secret, itemPath, primaryItem, nil, false, spec)
+		assert.Contains(t, <-recorder.Events, "different values for keys auth")
+	})
+
+	t.Run("should report failures of the shadow backend", func(t *testing.T) {
+		shadowClient := &mocks.TestClient{}
+		shadowClient.On("GetVaultsByTitle", mock.Anything).Return([]model.Vault{}, nil)
+		shadowClient.On("GetItemByID", vaultId, itemId).Return(nil, errors.New("unauthorized"))
+		shadowClient.On("GetItemsByTitle", vaultId, itemId).Return([]model.Item{}, nil)
+		recorder := record.NewFakeRecorder(1)
+
+		NewShadowComparer(shadowClient, recorder).
+			Compare(context.Background(), secret, itemPath, primaryItem, nil, false, nil)
+		assert.Contains(t, <-recorder.Events, ShadowFailedReason)
+	})
+}
+
+func TestShadowComparer_CompareInBackground(t *testing.T) {
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "secret", Namespace: namespace}}
+	backend := &mocks.TestClient{}
+	backend.On("GetVaultsByTitle", mock.Anything).Return([]model.Vault{}, nil)
+	backend.On("GetItemByID", vaultId, itemId).Return(createItem(), nil)
+	backend.On("GetItemsByTitle", vaultId, itemId).Return([]model.Item{}, nil)
+	// The shadow backend hangs until the comparison times out.
+	shadowClient := chaos.New(backend, chaos.Fault{Operation: "GetItemByID", Latency: time.Hour})
+	recorder := record.NewFakeRecorder(2)
+	comparer := NewShadowComparer(shadowClient, recorder)
+	comparer.timeout = 20 * time.Millisecond
+
+	comparer.CompareInBackground(context.Background(), secret, itemPath, createItem(), nil, false, nil)
+	comparer.CompareInBackground(context.Background(), secret, itemPath, createItem(), nil, false, nil)
+
+	select {
+	case event := <-recorder.Events:
+		assert.Contains(t, event, ShadowFailedReason)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the comparison should time out")
+	}
+	assert.Never(t, func() bool { return len(recorder.Events) > 0 }, 100*time.Millisecond, 10*time.Millisecond,
+		"a comparison should be skipped while the previous one of the Secret runs")
+}
+
+func TestUpdateSecretHandlerShadow(t *testing.T) {
+	ctx := context.Background()
+	existingSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "shadowed-secret",
+			Namespace: namespace,
+			Annotations: map[string]string{
+				VersionAnnotation:  "123",
+				ItemPathAnnotation: itemPath,
+			},
+		},
+		Data: expectedSecretData,
+	}
+	cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).
+		WithRuntimeObjects([]runtime.Object{defaultNamespace, existingSecret}...).Build()
+
+	mockOpClient := &mocks.TestClient{}
+	mockOpClient.On("GetItemByID", mock.Anything, mock.Anything).Return(createItem(), nil)
+	mockOpClient.On("GetVaultsByTitle", mock.Anything).Return([]model.Vault{}, nil)
+
+	shadowItem := createItem()
+	shadowItem.Fields = shadowItem.Fields[:1]
+	shadowClient := &mocks.TestClient{}
+	shadowClient.On("GetItemByID", mock.Anything, mock.Anything).Return(shadowItem, nil)
+	shadowClient.On("GetVaultsByTitle", mock.Anything).Return([]model.Vault{}, nil)
+	recorder := record.NewFakeRecorder(1)
+
+	h := &SecretUpdateHandler{
+		client:    cl,
+		apiReader: cl,
+		opClient:  mockOpClient,
+		config: SecretUpdateHandlerConfig{
+			Shadow: NewShadowComparer(shadowClient, recorder),
+		},
+	}
+	require.NoError(t, h.UpdateKubernetesSecretsTask(ctx))
+
+	assert.Contains(t, <-recorder.Events, "missing keys password")
+	secret := &corev1.Secret{}
+	require.NoError(t, cl.Get(ctx, types.NamespacedName{Name: "shadowed-secret", Namespace: namespace}, secret))
+	assert.Equal(t, expectedSecretData, secret.Data, "shadow mode should not change the Secret")
+}
@@ -0,0 +1,120 @@
+package template
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// defaultAWSProfile is the profile the AWS CLI and SDKs use when AWS_PROFILE is not set.
+const defaultAWSProfile = "default"
+
+var (
+	awsAccessKeyIDPattern = regexp.MustCompile(`^[A-Z0-9]{16,128}$`)
+	awsProfileNamePattern = regexp.MustCompile(`^[A-Za-z0-9._+@-]+$`)
+	awsRegionPattern      = regexp.MustCompile(`^[a-z]{2}(-[a-z]+)+-\d+$`)
+)
+
+// AWSProfile holds the credentials and settings of a single AWS profile.
+type AWSProfile struct {
+	// Name is the profile name. Defaults to "default".
+	Name            string
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+	Region          string
+}
+
+// BuildAWSCredentials generates an AWS shared credentials file (~/.aws/credentials) with one section per profile.
+func BuildAWSCredentials(profiles []AWSProfile) ([]byte, error) {
+	profiles, err := validateAWSProfiles(profiles)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	for i, p := range profiles {
+		if i > 0 {
+			buf.WriteString("\n")
+		}
+		fmt.Fprintf(&buf, "[%s]\n", p.Name)
+		fmt.Fprintf(&buf, "aws_access_key_id = %s\n", p.AccessKeyID)
+		fmt.Fprintf(&buf, "aws_secret_access_key = %s\n", p.SecretAccessKey)
+		if p.SessionToken != "" {
+			fmt.Fprintf(&buf, "aws_session_token = %s\n", p.SessionToken)
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// BuildAWSConfig generates an AWS config file (~/.aws/config) with the region of each profile.
+// Profiles other than "default" are written as "[profile name]", as the AWS CLI expects.
+// It returns nil when no profile has a region.
+func BuildAWSConfig(profiles []AWSProfile) ([]byte, error) {
+	profiles, err := validateAWSProfiles(profiles)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	for _, p := range profiles {
+		if p.Region == "" {
+			continue
+		}
+		if buf.Len() > 0 {
+			buf.WriteString("\n")
+		}
+		if p.Name == defaultAWSProfile {
+			fmt.Fprintf(&buf, "[%s]\n", p.Name)
+		} else {
+			fmt.Fprintf(&buf, "[profile %s]\n", p.Name)
+		}
+		fmt.Fprintf(&buf, "region = %s\n", p.Region)
+	}
+	if buf.Len() == 0 {
+		return nil, nil
+	}
+	return buf.Bytes(), nil
+}
+
+func validateAWSProfiles(profiles []AWSProfile) ([]AWSProfile, error) {
+	if len(profiles) == 0 {
+		return nil, fmt.Errorf("at least one profile is required")
+	}
+
+	validated := make([]AWSProfile, len(profiles))
+	seen := make(map[string]bool, len(profiles))
+	for i, p := range profiles {
+		p.Name = strings.TrimSpace(p.Name)
+		p.AccessKeyID = strings.TrimSpace(p.AccessKeyID)
+		p.SecretAccessKey = strings.TrimSpace(p.SecretAccessKey)
+		p.SessionToken = strings.TrimSpace(p.SessionToken)
+		p.Region = strings.TrimSpace(p.Region)
+		if p.Name == "" {
+			p.Name = defaultAWSProfile
+		}
+
+		if !awsProfileNamePattern.MatchString(p.Name) {
+			return nil, fmt.Errorf("invalid profile name %q", p.Name)
+		}
+		if seen[p.Name] {
+			return nil, fmt.Errorf("duplicate profile %q", p.Name)
+		}
+		seen[p.Name] = true
+		if !awsAccessKeyIDPattern.MatchString(p.AccessKeyID) {
+			return nil, fmt.Errorf("invalid access key ID for profile %q", p.Name)
+		}
+		if p.SecretAccessKey == "" || strings.ContainsAny(p.SecretAccessKey, " \n") {
+			return nil, fmt.Errorf("invalid secret access key for profile %q", p.Name)
+		}
+		if strings.ContainsAny(p.SessionToken, " \n") {
+			return nil, fmt.Errorf("invalid session token for profile %q", p.Name)
+		}
+		if p.Region != "" && !awsRegionPattern.MatchString(p.Region) {
+			return nil, fmt.Errorf("invalid region %q for profile %q", p.Region, p.Name)
+		}
+		validated[i] = p
+	}
+	return validated, nil
+}
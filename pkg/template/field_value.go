@@ -0,0 +1,76 @@
+package template
+
+import (
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/1Password/onepassword-operator/pkg/onepassword/model"
+)
+
+var (
+	// monthYearPattern matches the Connect spelling of a month/year field, e.g. "202401".
+	monthYearPattern = regexp.MustCompile(`^(\d{4})(\d{2})$`)
+	// sdkMonthYearPattern matches the SDK spelling of a month/year field, e.g. "01/2024".
+	sdkMonthYearPattern = regexp.MustCompile(`^(\d{2})/(\d{4})$`)
+)
+
+// FormatFieldValue renders the value of a field according to its type: dates as RFC 3339,
+// month/year fields as "2006-01", OTP fields as their TOTP secret and addresses on a single line.
+// Values that cannot be interpreted, and fields of other types, are returned unchanged.
+func FormatFieldValue(field model.ItemField) string {
+	value := field.Value
+	switch field.Type() {
+	case model.FieldTypeDate:
+		return formatDate(value)
+	case model.FieldTypeMonthYear:
+		return formatMonthYear(value)
+	case model.FieldTypeOTP:
+		return TOTPSecret(value)
+	case model.FieldTypeAddress:
+		if field.Address != nil {
+			return field.Address.String()
+		}
+	}
+	return value
+}
+
+// TOTPSecret returns the secret of an otpauth:// URI, or the value itself if it is not such a URI,
+// as OTP fields can hold either.
+func TOTPSecret(value string) string {
+	if !strings.HasPrefix(value, "otpauth://") {
+		return value
+	}
+	u, err := url.Parse(value)
+	if err != nil {
+		return value
+	}
+	if secret := u.Query().Get("secret"); secret != "" {
+		return secret
+	}
+	return value
+}
+
+// formatDate converts a date stored as Unix seconds (Connect) or as "2006-01-02" (SDK) to RFC 3339.
+func formatDate(value string) string {
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.Unix(seconds, 0).UTC().Format(time.RFC3339)
+	}
+	if date, err := time.Parse(time.DateOnly, value); err == nil {
+		return date.Format(time.RFC3339)
+	}
+	return value
+}
+
+// formatMonthYear converts a month/year stored as "202401" (Connect) or "01/2024" (SDK) to "2024-01".
+func formatMonthYear(value string) string {
+	if m := monthYearPattern.FindStringSubmatch(value); m != nil {
+		return m[1] + "-" + m[2]
+	}
+	if m := sdkMonthYearPattern.FindStringSubmatch(value); m != nil {
+		return m[2] + "-" + m[1]
+	}
+	return value
+}
@@ -0,0 +1,84 @@
+package template
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/1Password/onepassword-operator/pkg/onepassword/model"
+)
+
+func TestFormatFieldValue(t *testing.T) {
+	tests := []struct {
+		name  string
+		field model.ItemField
+		want  string
+	}{
+		{
+			name:  "connect date",
+			field: model.ItemField{FieldType: "DATE", Value: "1704067200"},
+			want:  "2024-01-01T00:00:00Z",
+		},
+		{
+			name:  "sdk date",
+			field: model.ItemField{FieldType: "Date", Value: "2024-01-31"},
+			want:  "2024-01-31T00:00:00Z",
+		},
+		{
+			name:  "unparsable date",
+			field: model.ItemField{FieldType: "DATE", Value: "someday"},
+			want:  "someday",
+		},
+		{
+			name:  "connect month year",
+			field: model.ItemField{FieldType: "MONTH_YEAR", Value: "202403"},
+			want:  "2024-03",
+		},
+		{
+			name:  "sdk month year",
+			field: model.ItemField{FieldType: "MonthYear", Value: "03/2024"},
+			want:  "2024-03",
+		},
+		{
+			name: "otp uri",
+			field: model.ItemField{
+				FieldType: "OTP",
+				Value:     "otpauth://totp/Example:alice?secret=JBSWY3DPEHPK3PXP&issuer=Example",
+			},
+			want: "JBSWY3DPEHPK3PXP",
+		},
+		{
+			name:  "otp secret",
+			field: model.ItemField{FieldType: "Totp", Value: "JBSWY3DPEHPK3PXP"},
+			want:  "JBSWY3DPEHPK3PXP",
+		},
+		{
+			name: "address",
+			field: model.ItemField{FieldType: "Address", Address: &model.ItemAddress{
+				Street: "1 Main St", City: "Toronto", State: "ON", Zip: "M5V 2T6", Country: "CA",
+			}},
+			want: "1 Main St, Toronto, ON M5V 2T6, CA",
+		},
+		{
+			name:  "address without components",
+			field: model.ItemField{FieldType: "ADDRESS", Value: "1 Main St"},
+			want:  "1 Main St",
+		},
+		{
+			name:  "menu",
+			field: model.ItemField{FieldType: "MENU", Value: "Oracle"},
+			want:  "Oracle",
+		},
+		{
+			name:  "concealed",
+			field: model.ItemField{FieldType: "CONCEALED", Value: "1704067200"},
+			want:  "1704067200",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, FormatFieldValue(tt.field))
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package template
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/mail"
+)
+
+// gcpServiceAccountKey holds the fields of a GCP service account key file that clients require.
+type gcpServiceAccountKey struct {
+	Type         string `json:"type"`
+	ProjectID    string `json:"project_id"`
+	PrivateKeyID string `json:"private_key_id"`
+	PrivateKey   string `json:"private_key"`
+	ClientEmail  string `json:"client_email"`
+	TokenURI     string `json:"token_uri"`
+}
+
+// BuildGCPServiceAccountKey validates a GCP service account key file and returns it unchanged
+// apart from surrounding whitespace. The private key must be a valid PEM encoded key.
+func BuildGCPServiceAccountKey(data []byte) ([]byte, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return nil, fmt.Errorf("service account key cannot be empty")
+	}
+
+	var key gcpServiceAccountKey
+	if err := json.Unmarshal(data, &key); err != nil {
+		return nil, fmt.Errorf("invalid service account key: %w", err)
+	}
+	if key.Type != "service_account" {
+		return nil, fmt.Errorf("invalid service account key: type must be %q, got %q", "service_account", key.Type)
+	}
+	for _, field := range []struct{ name, value string }{
+		{"project_id", key.ProjectID},
+		{"private_key_id", key.PrivateKeyID},
+		{"private_key", key.PrivateKey},
+		{"client_email", key.ClientEmail},
+		{"token_uri", key.TokenURI},
+	} {
+		if field.value == "" {
+			return nil, fmt.Errorf("invalid service account key: %s is missing", field.name)
+		}
+	}
+	if _, err := mail.ParseAddress(key.ClientEmail); err != nil {
+		return nil, fmt.Errorf("invalid service account key: invalid client_email %q", key.ClientEmail)
+	}
+	if _, err := parsePrivateKey([]byte(key.PrivateKey)); err != nil {
+		return nil, fmt.Errorf("invalid service account key: invalid private_key: %w", err)
+	}
+
+	return data, nil
+}
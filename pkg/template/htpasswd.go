@@ -0,0 +1,55 @@
+package template
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// HtpasswdUser holds the credentials of a single htpasswd entry.
+type HtpasswdUser struct {
+	Username string
+	Password string
+}
+
+// BuildHtpasswd generates an htpasswd file with a bcrypt hash per user, as read by ingress-nginx and
+// Traefik basic auth. A cost of 0 uses bcrypt.DefaultCost. Hashes are salted, so every call returns
+// a different file for the same users.
+func BuildHtpasswd(users []HtpasswdUser, cost int) ([]byte, error) {
+	if len(users) == 0 {
+		return nil, fmt.Errorf("at least one user is required")
+	}
+	if cost == 0 {
+		cost = bcrypt.DefaultCost
+	}
+	if cost < bcrypt.MinCost || cost > bcrypt.MaxCost {
+		return nil, fmt.Errorf("bcrypt cost must be between %d and %d, got %d", bcrypt.MinCost, bcrypt.MaxCost, cost)
+	}
+
+	var buf bytes.Buffer
+	seen := make(map[string]bool, len(users))
+	for _, user := range users {
+		if user.Username == "" {
+			return nil, fmt.Errorf("username cannot be empty")
+		}
+		if strings.ContainsAny(user.Username, ":\r\n") {
+			return nil, fmt.Errorf("username %q cannot contain colons or line breaks", user.Username)
+		}
+		if seen[user.Username] {
+			return nil, fmt.Errorf("duplicate user %q", user.Username)
+		}
+		seen[user.Username] = true
+		if user.Password == "" {
+			return nil, fmt.Errorf("password cannot be empty for user %q", user.Username)
+		}
+
+		hash, err := bcrypt.GenerateFromPassword([]byte(user.Password), cost)
+		if err != nil {
+			return nil, fmt.Errorf("failed to hash password for user %q: %w", user.Username, err)
+		}
+		fmt.Fprintf(&buf, "%s:%s\n", user.Username, hash)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,86 @@
+package template
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestBuildHtpasswd(t *testing.T) {
+	tests := []struct {
+		name    string
+		users   []HtpasswdUser
+		cost    int
+		wantErr string
+	}{
+		{
+			name:  "single user with default cost",
+			users: []HtpasswdUser{{Username: "admin", Password: "s3cr3t"}},
+		},
+		{
+			name: "multiple users",
+			users: []HtpasswdUser{
+				{Username: "alice", Password: "p@ss:word"},
+				{Username: "bob", Password: "hunter2"},
+			},
+			cost: bcrypt.MinCost,
+		},
+		{
+			name:    "no users",
+			wantErr: "at least one user",
+		},
+		{
+			name:    "cost out of range",
+			users:   []HtpasswdUser{{Username: "admin", Password: "s3cr3t"}},
+			cost:    3,
+			wantErr: "bcrypt cost",
+		},
+		{
+			name:    "colon in username",
+			users:   []HtpasswdUser{{Username: "ad:min", Password: "s3cr3t"}},
+			wantErr: "cannot contain colons",
+		},
+		{
+			name: "duplicate user",
+			users: []HtpasswdUser{
+				{Username: "admin", Password: "a"},
+				{Username: "admin", Password: "b"},
+			},
+			wantErr: "duplicate user",
+		},
+		{
+			name:    "empty password",
+			users:   []HtpasswdUser{{Username: "admin"}},
+			wantErr: "password cannot be empty",
+		},
+		{
+			name:    "password too long for bcrypt",
+			users:   []HtpasswdUser{{Username: "admin", Password: strings.Repeat("x", 73)}},
+			wantErr: "failed to hash password",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := BuildHtpasswd(tt.users, tt.cost)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			lines := strings.Split(strings.TrimSuffix(string(result), "\n"), "\n")
+			require.Len(t, lines, len(tt.users))
+			for i, line := range lines {
+				username, hash, ok := strings.Cut(line, ":")
+				require.True(t, ok)
+				assert.Equal(t, tt.users[i].Username, username)
+				assert.NoError(t, bcrypt.CompareHashAndPassword([]byte(hash), []byte(tt.users[i].Password)))
+			}
+		})
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"net/url"
+	"strings"
 )
 
 // DockerConfigJSON represents the structure of a .dockerconfigjson file.
@@ -19,37 +21,63 @@ type DockerConfigEntry struct {
 	Auth     string `json:"auth,omitempty"`
 }
 
+// RegistryCredentials holds the credentials of a single registry entry in dockerconfigjson.
+type RegistryCredentials struct {
+	Registry string
+	Username string
+	Password string
+	Email    string
+}
+
 // BuildDockerConfigJSON generates the proper .dockerconfigjson structure for Kubernetes image pull secrets.
 // The auth field is base64-encoded "username:password".
 func BuildDockerConfigJSON(registry, username, password, email string) ([]byte, error) {
-	if registry == "" {
-		return nil, fmt.Errorf("registry cannot be empty")
-	}
-	if username == "" {
-		return nil, fmt.Errorf("username cannot be empty")
-	}
-	if password == "" {
-		return nil, fmt.Errorf("password cannot be empty")
-	}
-
-	// Create base64-encoded auth string
-	authString := fmt.Sprintf("%s:%s", username, password)
-	auth := base64.StdEncoding.EncodeToString([]byte(authString))
-
-	entry := DockerConfigEntry{
+	return BuildMultiRegistryDockerConfigJSON([]RegistryCredentials{{
+		Registry: registry,
 		Username: username,
 		Password: password,
-		Auth:     auth,
-	}
+		Email:    email,
+	}})
+}
 
-	if email != "" {
-		entry.Email = email
+// BuildMultiRegistryDockerConfigJSON generates a .dockerconfigjson with one auths entry per registry.
+// Registries are compared by host, so "ghcr.io" and "https://ghcr.io" are rejected as duplicates.
+func BuildMultiRegistryDockerConfigJSON(credentials []RegistryCredentials) ([]byte, error) {
+	if len(credentials) == 0 {
+		return nil, fmt.Errorf("at least one registry is required")
 	}
 
 	config := DockerConfigJSON{
-		Auths: map[string]DockerConfigEntry{
-			registry: entry,
-		},
+		Auths: make(map[string]DockerConfigEntry, len(credentials)),
+	}
+	seen := make(map[string]string, len(credentials))
+	for _, c := range credentials {
+		if c.Registry == "" {
+			return nil, fmt.Errorf("registry cannot be empty")
+		}
+		if c.Username == "" {
+			return nil, fmt.Errorf("username cannot be empty for registry %q", c.Registry)
+		}
+		if c.Password == "" {
+			return nil, fmt.Errorf("password cannot be empty for registry %q", c.Registry)
+		}
+		normalized := RegistryFromURL(c.Registry)
+		if normalized == "" {
+			normalized = c.Registry
+		}
+		if previous, exists := seen[normalized]; exists {
+			return nil, fmt.Errorf("duplicate registry %q (already configured as %q)", c.Registry, previous)
+		}
+		seen[normalized] = c.Registry
+
+		// Create base64-encoded auth string
+		authString := fmt.Sprintf("%s:%s", c.Username, c.Password)
+		config.Auths[c.Registry] = DockerConfigEntry{
+			Username: c.Username,
+			Password: c.Password,
+			Email:    c.Email,
+			Auth:     base64.StdEncoding.EncodeToString([]byte(authString)),
+		}
 	}
 
 	jsonBytes, err := json.Marshal(config)
@@ -59,3 +87,31 @@ func BuildDockerConfigJSON(registry, username, password, email string) ([]byte,
 
 	return jsonBytes, nil
 }
+
+// RegistryFromURL derives the registry host from a URL such as "https://ghcr.io/org/image".
+// Values without a scheme are treated as a host followed by an optional path.
+// Docker Hub URLs keep the legacy "https://index.docker.io/v1/" key that container runtimes expect.
+func RegistryFromURL(rawURL string) string {
+	value := strings.TrimSpace(rawURL)
+	if value == "" {
+		return ""
+	}
+	if !strings.Contains(value, "://") {
+		value = "https://" + value
+	}
+
+	parsed, err := url.Parse(value)
+	if err != nil || parsed.Host == "" {
+		return ""
+	}
+
+	host := strings.ToLower(parsed.Host)
+	switch host {
+	case "docker.io", "index.docker.io", "registry-1.docker.io", "hub.docker.com":
+		return dockerHubRegistry
+	}
+	return host
+}
+
+// dockerHubRegistry is the auths key container runtimes look up for Docker Hub.
+const dockerHubRegistry = "https://index.docker.io/v1/"
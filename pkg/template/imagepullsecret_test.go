@@ -114,3 +114,98 @@ func TestBuildDockerConfigJSON_ValidJSON(t *testing.T) {
 	assert.Len(t, config.Auths, 1)
 	assert.Contains(t, config.Auths, "registry.example.com")
 }
+
+func TestBuildMultiRegistryDockerConfigJSON(t *testing.T) {
+	tests := []struct {
+		name        string
+		credentials []RegistryCredentials
+		wantErr     bool
+		wantAuths   []string
+	}{
+		{
+			name: "multiple registries",
+			credentials: []RegistryCredentials{
+				{Registry: "ghcr.io", Username: "ghuser", Password: "ghpass"},
+				{Registry: "harbor.example.com", Username: "robot", Password: "harborpass"},
+				{Registry: "https://index.docker.io/v1/", Username: "hubuser", Password: "hubpass"},
+			},
+			wantAuths: []string{"ghcr.io", "harbor.example.com", "https://index.docker.io/v1/"},
+		},
+		{
+			name: "duplicate registry",
+			credentials: []RegistryCredentials{
+				{Registry: "ghcr.io", Username: "a", Password: "a"},
+				{Registry: "ghcr.io", Username: "b", Password: "b"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "duplicate registry with scheme",
+			credentials: []RegistryCredentials{
+				{Registry: "ghcr.io", Username: "a", Password: "a"},
+				{Registry: "https://ghcr.io", Username: "b", Password: "b"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "duplicate docker hub aliases",
+			credentials: []RegistryCredentials{
+				{Registry: "docker.io", Username: "a", Password: "a"},
+				{Registry: "https://index.docker.io/v1/", Username: "b", Password: "b"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "missing password in second entry",
+			credentials: []RegistryCredentials{
+				{Registry: "ghcr.io", Username: "a", Password: "a"},
+				{Registry: "quay.io", Username: "b"},
+			},
+			wantErr: true,
+		},
+		{
+			name:    "no registries",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := BuildMultiRegistryDockerConfigJSON(tt.credentials)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Nil(t, result)
+				return
+			}
+			require.NoError(t, err)
+
+			var config DockerConfigJSON
+			require.NoError(t, json.Unmarshal(result, &config))
+			assert.Len(t, config.Auths, len(tt.wantAuths))
+			for _, registry := range tt.wantAuths {
+				assert.Contains(t, config.Auths, registry)
+			}
+		})
+	}
+}
+
+func TestRegistryFromURL(t *testing.T) {
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{url: "https://ghcr.io/my-org/my-image", want: "ghcr.io"},
+		{url: "harbor.example.com:8443/project", want: "harbor.example.com:8443"},
+		{url: "HTTPS://Quay.IO", want: "quay.io"},
+		{url: "https://hub.docker.com/u/someone", want: "https://index.docker.io/v1/"},
+		{url: "docker.io", want: "https://index.docker.io/v1/"},
+		{url: "", want: ""},
+		{url: "https://", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			assert.Equal(t, tt.want, RegistryFromURL(tt.url))
+		})
+	}
+}
@@ -0,0 +1,158 @@
+package template
+
+import (
+	"bytes"
+	"crypto/x509"
+	"fmt"
+	"strings"
+
+	"github.com/pavlo-v-chernykh/keystore-go/v4"
+	"software.sslmate.com/src/go-pkcs12"
+)
+
+// Keystore formats supported by BuildKeystores.
+const (
+	KeystoreFormatPKCS12 = "PKCS12"
+	KeystoreFormatJKS    = "JKS"
+)
+
+// defaultKeystoreAlias is the alias of the key entry when none is given.
+const defaultKeystoreAlias = "certificate"
+
+// Keystores holds a key store with the private key and its certificate chain, and a trust store
+// with the CA certificates. Either is nil when the PEM data to build it from is missing.
+type Keystores struct {
+	Keystore   []byte
+	Truststore []byte
+}
+
+// KeystoreOptions configures BuildKeystores.
+type KeystoreOptions struct {
+	// Format is KeystoreFormatPKCS12 or KeystoreFormatJKS.
+	Format string
+	// Password protects the stores and the private key entry.
+	Password string
+	// Alias is the alias of the private key entry. Trust store entries are named "<alias>-ca-<n>".
+	// Defaults to "certificate".
+	Alias string
+}
+
+// BuildKeystores assembles a key store from the PEM encoded certificate chain and private key, and
+// a trust store from the PEM encoded CA bundle. The PEM data is validated like in BuildTLSBundle.
+// PKCS#12 stores use modern encryption (AES-256 and PBKDF2), which Java reads since 8u301.
+func BuildKeystores(certPEM, keyPEM, caPEM []byte, options KeystoreOptions) (*Keystores, error) {
+	if options.Password == "" {
+		return nil, fmt.Errorf("keystore password cannot be empty")
+	}
+	if options.Alias == "" {
+		options.Alias = defaultKeystoreAlias
+	}
+	format := strings.ToUpper(options.Format)
+	if format != KeystoreFormatPKCS12 && format != KeystoreFormatJKS {
+		return nil, fmt.Errorf("unsupported keystore format %q", options.Format)
+	}
+
+	hasKeyPair := len(bytes.TrimSpace(certPEM)) > 0 || len(bytes.TrimSpace(keyPEM)) > 0
+	hasCA := len(bytes.TrimSpace(caPEM)) > 0
+	if !hasKeyPair && !hasCA {
+		return nil, fmt.Errorf("a certificate and private key or a CA certificate is required")
+	}
+
+	stores := &Keystores{}
+	if hasKeyPair {
+		// BuildTLSBundle validates the key pair and orders the chain.
+		bundle, err := BuildTLSBundle(certPEM, keyPEM, nil, PrivateKeyFormatPKCS8)
+		if err != nil {
+			return nil, err
+		}
+		chain, err := parseCertificates(bundle.Certificate)
+		if err != nil {
+			return nil, err
+		}
+		key, err := parsePrivateKey(bundle.PrivateKey)
+		if err != nil {
+			return nil, err
+		}
+
+		if format == KeystoreFormatPKCS12 {
+			stores.Keystore, err = pkcs12.Modern.Encode(key, chain[0], chain[1:], options.Password)
+		} else {
+			stores.Keystore, err = buildJKSKeystore(key, chain, options)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode %s keystore: %w", format, err)
+		}
+	}
+
+	if hasCA {
+		caCerts, err := parseCertificates(caPEM)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CA certificate: %w", err)
+		}
+		if len(caCerts) == 0 {
+			return nil, fmt.Errorf("invalid CA certificate: no PEM encoded certificate found")
+		}
+
+		if format == KeystoreFormatPKCS12 {
+			entries := make([]pkcs12.TrustStoreEntry, len(caCerts))
+			for i, cert := range caCerts {
+				entries[i] = pkcs12.TrustStoreEntry{Cert: cert, FriendlyName: truststoreAlias(options.Alias, i)}
+			}
+			stores.Truststore, err = pkcs12.Modern.EncodeTrustStoreEntries(entries, options.Password)
+		} else {
+			stores.Truststore, err = buildJKSTruststore(caCerts, options)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode %s truststore: %w", format, err)
+		}
+	}
+
+	return stores, nil
+}
+
+func buildJKSKeystore(key any, chain []*x509.Certificate, options KeystoreOptions) ([]byte, error) {
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+	entry := keystore.PrivateKeyEntry{
+		// The certificate's start date keeps the entry stable for the same input.
+		CreationTime: chain[0].NotBefore,
+		PrivateKey:   der,
+	}
+	for _, cert := range chain {
+		entry.CertificateChain = append(entry.CertificateChain, keystore.Certificate{Type: "X509", Content: cert.Raw})
+	}
+
+	ks := keystore.New()
+	if err := ks.SetPrivateKeyEntry(options.Alias, entry, []byte(options.Password)); err != nil {
+		return nil, err
+	}
+	return storeJKS(ks, options.Password)
+}
+
+func buildJKSTruststore(certs []*x509.Certificate, options KeystoreOptions) ([]byte, error) {
+	ks := keystore.New(keystore.WithOrderedAliases())
+	for i, cert := range certs {
+		entry := keystore.TrustedCertificateEntry{
+			CreationTime: cert.NotBefore,
+			Certificate:  keystore.Certificate{Type: "X509", Content: cert.Raw},
+		}
+		if err := ks.SetTrustedCertificateEntry(truststoreAlias(options.Alias, i), entry); err != nil {
+			return nil, err
+		}
+	}
+	return storeJKS(ks, options.Password)
+}
+
+func storeJKS(ks keystore.KeyStore, password string) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := ks.Store(&buf, []byte(password)); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func truststoreAlias(alias string, index int) string {
+	return fmt.Sprintf("%s-ca-%d", alias, index)
+}
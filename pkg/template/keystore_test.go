@@ -0,0 +1,134 @@
+package template
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+
+	"github.com/pavlo-v-chernykh/keystore-go/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"software.sslmate.com/src/go-pkcs12"
+)
+
+func TestBuildKeystores(t *testing.T) {
+	root := newTestCert(t, "root", newECKey(t), nil, true)
+	intermediate := newTestCert(t, "intermediate", newECKey(t), root, true)
+	rsaKey := newRSAKey(t)
+	leaf := newTestCert(t, "leaf.example.com", rsaKey, intermediate, false)
+	pkcs8DER, err := x509.MarshalPKCS8PrivateKey(rsaKey)
+	require.NoError(t, err)
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8DER})
+
+	// The chain is out of order on purpose; it is reordered like in BuildTLSBundle.
+	certPEM := concat(intermediate.pem, leaf.pem)
+	const password = "changeit"
+
+	t.Run("PKCS12", func(t *testing.T) {
+		stores, err := BuildKeystores(certPEM, keyPEM, root.pem, KeystoreOptions{
+			Format:   KeystoreFormatPKCS12,
+			Password: password,
+		})
+		require.NoError(t, err)
+
+		key, cert, caCerts, err := pkcs12.DecodeChain(stores.Keystore, password)
+		require.NoError(t, err)
+		assert.Equal(t, leaf.cert.Raw, cert.Raw)
+		assert.Equal(t, rsaKey, key)
+		require.Len(t, caCerts, 1)
+		assert.Equal(t, intermediate.cert.Raw, caCerts[0].Raw)
+
+		trusted, err := pkcs12.DecodeTrustStore(stores.Truststore, password)
+		require.NoError(t, err)
+		require.Len(t, trusted, 1)
+		assert.Equal(t, root.cert.Raw, trusted[0].Raw)
+	})
+
+	t.Run("JKS", func(t *testing.T) {
+		stores, err := BuildKeystores(certPEM, keyPEM, concat(root.pem, intermediate.pem), KeystoreOptions{
+			Format:   "jks",
+			Password: password,
+			Alias:    "server",
+		})
+		require.NoError(t, err)
+
+		ks := keystore.New()
+		require.NoError(t, ks.Load(bytes.NewReader(stores.Keystore), []byte(password)))
+		entry, err := ks.GetPrivateKeyEntry("server", []byte(password))
+		require.NoError(t, err)
+		assert.Equal(t, pkcs8DER, entry.PrivateKey)
+		require.Len(t, entry.CertificateChain, 2)
+		assert.Equal(t, leaf.cert.Raw, entry.CertificateChain[0].Content)
+
+		ts := keystore.New(keystore.WithOrderedAliases())
+		require.NoError(t, ts.Load(bytes.NewReader(stores.Truststore), []byte(password)))
+		assert.Equal(t, []string{"server-ca-0", "server-ca-1"}, ts.Aliases())
+	})
+
+	t.Run("truststore only", func(t *testing.T) {
+		stores, err := BuildKeystores(nil, nil, root.pem, KeystoreOptions{
+			Format:   KeystoreFormatJKS,
+			Password: password,
+		})
+		require.NoError(t, err)
+		assert.Nil(t, stores.Keystore)
+		assert.NotEmpty(t, stores.Truststore)
+	})
+
+	errorTests := []struct {
+		name    string
+		cert    []byte
+		key     []byte
+		ca      []byte
+		options KeystoreOptions
+		wantErr string
+	}{
+		{
+			name:    "missing password",
+			cert:    certPEM,
+			key:     keyPEM,
+			options: KeystoreOptions{Format: KeystoreFormatPKCS12},
+			wantErr: "password cannot be empty",
+		},
+		{
+			name:    "unsupported format",
+			cert:    certPEM,
+			key:     keyPEM,
+			options: KeystoreOptions{Format: "BKS", Password: password},
+			wantErr: "unsupported keystore format",
+		},
+		{
+			name:    "nothing to store",
+			options: KeystoreOptions{Format: KeystoreFormatPKCS12, Password: password},
+			wantErr: "is required",
+		},
+		{
+			name:    "key does not match certificate",
+			cert:    leaf.pem,
+			key:     pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: mustPKCS8(t, newECKey(t))}),
+			options: KeystoreOptions{Format: KeystoreFormatPKCS12, Password: password},
+			wantErr: "private key",
+		},
+		{
+			name:    "invalid CA",
+			ca:      []byte("not a certificate"),
+			options: KeystoreOptions{Format: KeystoreFormatJKS, Password: password},
+			wantErr: "invalid CA certificate",
+		},
+	}
+	for _, tt := range errorTests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := BuildKeystores(tt.cert, tt.key, tt.ca, tt.options)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func mustPKCS8(t *testing.T, key any) []byte {
+	t.Helper()
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	return der
+}
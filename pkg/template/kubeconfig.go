@@ -0,0 +1,82 @@
+package template
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+// defaultKubeconfigName is the name of the cluster, user and context when none is given.
+const defaultKubeconfigName = "default"
+
+// KubeconfigOptions holds the values a kubeconfig is assembled from.
+type KubeconfigOptions struct {
+	// Name is the name of the cluster, user and context. Defaults to "default".
+	Name string
+	// Server is the https URL of the API server.
+	Server string
+	// CA is the PEM encoded CA bundle of the API server. When empty, the system roots are used.
+	CA []byte
+	// Token is the bearer token of the user.
+	Token string
+	// Namespace is the default namespace of the context.
+	Namespace string
+}
+
+// BuildKubeconfig generates a kubeconfig with a single cluster, user and context, which is set
+// as the current context.
+func BuildKubeconfig(options KubeconfigOptions) ([]byte, error) {
+	name := strings.TrimSpace(options.Name)
+	if name == "" {
+		name = defaultKubeconfigName
+	}
+
+	server := strings.TrimSpace(options.Server)
+	parsed, err := url.Parse(server)
+	if err != nil || parsed.Host == "" {
+		return nil, fmt.Errorf("invalid server URL %q", server)
+	}
+	if parsed.Scheme != "https" {
+		return nil, fmt.Errorf("server URL must use https, got %q", server)
+	}
+
+	token := strings.TrimSpace(options.Token)
+	if token == "" {
+		return nil, fmt.Errorf("token cannot be empty")
+	}
+
+	cluster := clientcmdapi.NewCluster()
+	cluster.Server = server
+	if len(strings.TrimSpace(string(options.CA))) > 0 {
+		certs, err := parseCertificates(options.CA)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CA certificate: %w", err)
+		}
+		if len(certs) == 0 {
+			return nil, fmt.Errorf("invalid CA certificate: no PEM encoded certificate found")
+		}
+		cluster.CertificateAuthorityData = encodeCertificates(certs)
+	}
+
+	user := clientcmdapi.NewAuthInfo()
+	user.Token = token
+
+	context := clientcmdapi.NewContext()
+	context.Cluster = name
+	context.AuthInfo = name
+	context.Namespace = strings.TrimSpace(options.Namespace)
+
+	config := clientcmdapi.NewConfig()
+	config.Clusters[name] = cluster
+	config.AuthInfos[name] = user
+	config.Contexts[name] = context
+	config.CurrentContext = name
+
+	if err := clientcmd.Validate(*config); err != nil {
+		return nil, fmt.Errorf("invalid kubeconfig: %w", err)
+	}
+	return clientcmd.Write(*config)
+}
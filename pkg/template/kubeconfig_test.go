@@ -0,0 +1,87 @@
+package template
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+func TestBuildKubeconfig(t *testing.T) {
+	ca := newTestCert(t, "kubernetes", newECKey(t), nil, true)
+
+	tests := []struct {
+		name     string
+		options  KubeconfigOptions
+		wantErr  string
+		validate func(t *testing.T, result []byte)
+	}{
+		{
+			name: "server, CA and token",
+			options: KubeconfigOptions{
+				Name:      "prod",
+				Server:    "https://api.prod.example.com:6443",
+				CA:        concat([]byte("subject=CN=kubernetes\n"), ca.pem),
+				Token:     "eyJhbGciOi\n",
+				Namespace: "apps",
+			},
+			validate: func(t *testing.T, result []byte) {
+				config, err := clientcmd.Load(result)
+				require.NoError(t, err)
+
+				assert.Equal(t, "prod", config.CurrentContext)
+				cluster := config.Clusters["prod"]
+				require.NotNil(t, cluster)
+				assert.Equal(t, "https://api.prod.example.com:6443", cluster.Server)
+				assert.Equal(t, ca.pem, cluster.CertificateAuthorityData)
+				assert.Equal(t, "eyJhbGciOi", config.AuthInfos["prod"].Token)
+				assert.Equal(t, "apps", config.Contexts["prod"].Namespace)
+			},
+		},
+		{
+			name:    "default name without CA",
+			options: KubeconfigOptions{Server: "https://10.0.0.1", Token: "token"},
+			validate: func(t *testing.T, result []byte) {
+				config, err := clientcmd.Load(result)
+				require.NoError(t, err)
+
+				assert.Equal(t, "default", config.CurrentContext)
+				assert.Empty(t, config.Clusters["default"].CertificateAuthorityData)
+			},
+		},
+		{
+			name:    "plain http server",
+			options: KubeconfigOptions{Server: "http://10.0.0.1", Token: "token"},
+			wantErr: "must use https",
+		},
+		{
+			name:    "invalid server",
+			options: KubeconfigOptions{Server: "api.example.com", Token: "token"},
+			wantErr: "invalid server URL",
+		},
+		{
+			name:    "missing token",
+			options: KubeconfigOptions{Server: "https://10.0.0.1"},
+			wantErr: "token cannot be empty",
+		},
+		{
+			name:    "invalid CA",
+			options: KubeconfigOptions{Server: "https://10.0.0.1", Token: "token", CA: []byte("not a certificate")},
+			wantErr: "invalid CA certificate",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := BuildKubeconfig(tt.options)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			tt.validate(t, result)
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package template
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ed25519"
+	"encoding/pem"
+	"errors"
+	"fmt"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// SSHKey holds an SSH private key in OpenSSH format together with its public key and fingerprint.
+type SSHKey struct {
+	// PrivateKey is the "OPENSSH PRIVATE KEY" PEM block of the key.
+	PrivateKey []byte
+	// PublicKey is the public key in authorized_keys format, without a trailing newline.
+	PublicKey []byte
+	// Fingerprint is the SHA256 fingerprint of the public key, e.g. "SHA256:...".
+	Fingerprint string
+}
+
+// BuildSSHKey parses a PEM encoded private key in PKCS#1, SEC 1, PKCS#8 or OpenSSH format
+// and converts it to OpenSSH format. Passphrase protected keys are rejected.
+func BuildSSHKey(privateKeyPEM []byte) (*SSHKey, error) {
+	raw, err := ssh.ParseRawPrivateKey(bytes.TrimSpace(privateKeyPEM))
+	if err != nil {
+		var passphraseErr *ssh.PassphraseMissingError
+		if errors.As(err, &passphraseErr) {
+			return nil, fmt.Errorf("passphrase protected ssh keys are not supported")
+		}
+		return nil, fmt.Errorf("invalid ssh private key: %w", err)
+	}
+
+	// ParseRawPrivateKey returns ed25519 keys by pointer, which MarshalPrivateKey does not accept.
+	if key, ok := raw.(*ed25519.PrivateKey); ok {
+		raw = *key
+	}
+	signer, ok := raw.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("unsupported ssh private key type %T", raw)
+	}
+
+	block, err := ssh.MarshalPrivateKey(signer, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode ssh private key: %w", err)
+	}
+
+	publicKey, err := ssh.NewPublicKey(signer.Public())
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive ssh public key: %w", err)
+	}
+
+	var privateKey bytes.Buffer
+	if err := pem.Encode(&privateKey, block); err != nil {
+		return nil, fmt.Errorf("failed to encode ssh private key: %w", err)
+	}
+
+	return &SSHKey{
+		PrivateKey:  privateKey.Bytes(),
+		PublicKey:   bytes.TrimSuffix(ssh.MarshalAuthorizedKey(publicKey), []byte("\n")),
+		Fingerprint: ssh.FingerprintSHA256(publicKey),
+	}, nil
+}
@@ -3,7 +3,9 @@ package template
 import (
 	"bytes"
 	"fmt"
+	"slices"
 	"text/template"
+	"time"
 
 	"github.com/1Password/onepassword-operator/pkg/onepassword/model"
 )
@@ -19,6 +21,55 @@ type TemplateContext struct {
 	// FieldsByID provides precise access: field_id -> value
 	// Use this when field labels might collide across sections.
 	FieldsByID map[string]string
+	// FieldTypes maps field_label -> field type in the Connect spelling, e.g. "CONCEALED".
+	// If duplicate labels exist across sections, the last one wins, like in Fields.
+	FieldTypes map[string]string
+	// FormattedFields maps field_label -> value rendered according to the field type, e.g. dates as
+	// RFC 3339 and OTP fields as their TOTP secret. See FormatFieldValue.
+	FormattedFields map[string]string
+	// FieldList holds every field in item order, for ranging over fields or branching on their type.
+	FieldList []Field
+	// Files maps file_name -> content of the file attachments.
+	Files map[string]string
+
+	// URLs holds the websites of the item. PrimaryURL is the primary one, or the first if none is marked.
+	URLs       []URL
+	PrimaryURL string
+	// Tags holds the tags of the item. Use HasTag to branch on a tag.
+	Tags []string
+
+	ID       string
+	VaultID  string
+	Title    string
+	Category string
+	Version  int
+	// CreatedAt and UpdatedAt can be formatted in templates, e.g. {{ .UpdatedAt.Format "2006-01-02" }}.
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// Field describes a single field of the item.
+type Field struct {
+	ID    string
+	Label string
+	Value string
+	// Formatted is the value rendered according to the field type. See FormatFieldValue.
+	Formatted string
+	Type      string
+	Purpose   string
+	Section   string
+}
+
+// URL describes a website of the item.
+type URL struct {
+	URL     string
+	Label   string
+	Primary bool
+}
+
+// HasTag reports whether the item has the given tag, e.g. {{ if .HasTag "production" }}.
+func (c *TemplateContext) HasTag(tag string) bool {
+	return slices.Contains(c.Tags, tag)
 }
 
 // BuildTemplateContext constructs a TemplateContext from a 1Password item.
@@ -27,6 +78,35 @@ func BuildTemplateContext(item *model.Item) *TemplateContext {
 		Fields:     make(map[string]string),
 		Sections:   make(map[string]map[string]string),
 		FieldsByID: make(map[string]string),
+		FieldTypes: make(map[string]string),
+		Files:      make(map[string]string),
+
+		FormattedFields: make(map[string]string),
+		Tags:            item.Tags,
+		ID:              item.ID,
+		VaultID:         item.VaultID,
+		Title:           item.Title,
+		Category:        item.Category,
+		Version:         item.Version,
+		CreatedAt:       item.CreatedAt,
+		UpdatedAt:       item.UpdatedAt,
+	}
+
+	for _, url := range item.URLs {
+		ctx.URLs = append(ctx.URLs, URL{URL: url.URL, Label: url.Label, Primary: url.Primary})
+		if url.Primary && ctx.PrimaryURL == "" {
+			ctx.PrimaryURL = url.URL
+		}
+	}
+	if ctx.PrimaryURL == "" && len(item.URLs) > 0 {
+		ctx.PrimaryURL = item.URLs[0].URL
+	}
+
+	for i := range item.Files {
+		// Files whose content was not loaded are left out.
+		if content, err := item.Files[i].Content(); err == nil {
+			ctx.Files[item.Files[i].Name] = string(content)
+		}
 	}
 
 	// Build section map by section ID for efficient lookup
@@ -42,37 +122,63 @@ func BuildTemplateContext(item *model.Item) *TemplateContext {
 	for _, field := range item.Fields {
 		// Add to flat Fields map (last one wins if duplicate labels)
 		ctx.Fields[field.Label] = field.Value
+		ctx.FieldTypes[field.Label] = field.Type()
+		formatted := FormatFieldValue(field)
+		ctx.FormattedFields[field.Label] = formatted
 
 		// Add to FieldsByID for precise access
 		ctx.FieldsByID[field.ID] = field.Value
 
 		// Add to Sections map if field has a section
+		sectionTitle := ""
 		if field.SectionID != "" {
-			sectionTitle := sectionMap[field.SectionID]
+			sectionTitle = sectionMap[field.SectionID]
 			if sectionTitle == "" {
 				// Section ID exists but not in sections array, use ID as fallback
 				sectionTitle = field.SectionID
 			}
-			if ctx.Sections[sectionTitle] == nil {
-				ctx.Sections[sectionTitle] = make(map[string]string)
-			}
-			ctx.Sections[sectionTitle][field.Label] = field.Value
-		} else {
-			// Field without section - add to a default/empty section
-			if ctx.Sections[""] == nil {
-				ctx.Sections[""] = make(map[string]string)
-			}
-			ctx.Sections[""][field.Label] = field.Value
 		}
+		// Fields without section are added to a default/empty section
+		if ctx.Sections[sectionTitle] == nil {
+			ctx.Sections[sectionTitle] = make(map[string]string)
+		}
+		ctx.Sections[sectionTitle][field.Label] = field.Value
+
+		ctx.FieldList = append(ctx.FieldList, Field{
+			ID:        field.ID,
+			Label:     field.Label,
+			Value:     field.Value,
+			Formatted: formatted,
+			Type:      field.Type(),
+			Purpose:   field.Purpose,
+			Section:   sectionTitle,
+		})
 	}
 
 	return ctx
 }
 
+// rootTemplateName is the name of the template being processed, which partials cannot use.
+const rootTemplateName = "secret"
+
 // ProcessTemplate processes a Go template string with the given context.
 func ProcessTemplate(tmpl string, ctx *TemplateContext) ([]byte, error) {
-	t, err := template.New("secret").Parse(tmpl)
-	if err != nil {
+	return ProcessTemplateWithPartials(tmpl, nil, ctx)
+}
+
+// ProcessTemplateWithPartials processes a Go template string with the given context. Each partial is
+// parsed as a named template that tmpl can include with {{ template "<name>" . }}.
+func ProcessTemplateWithPartials(tmpl string, partials map[string]string, ctx *TemplateContext) ([]byte, error) {
+	t := template.New(rootTemplateName)
+	for name, partial := range partials {
+		if name == rootTemplateName {
+			return nil, fmt.Errorf("partial name %q is reserved", name)
+		}
+		if _, err := t.New(name).Parse(partial); err != nil {
+			return nil, fmt.Errorf("failed to parse partial %q: %w", name, err)
+		}
+	}
+	if _, err := t.Parse(tmpl); err != nil {
 		return nil, fmt.Errorf("failed to parse template: %w", err)
 	}
 
@@ -2,6 +2,7 @@ package template
 
 import (
 	"testing"
+	"time"
 
 	"github.com/1Password/onepassword-operator/pkg/onepassword/model"
 	"github.com/stretchr/testify/assert"
@@ -164,3 +165,175 @@ func TestBuildTemplateContext_DuplicateLabels(t *testing.T) {
 	assert.Equal(t, "first", ctx.FieldsByID["field-1"])
 	assert.Equal(t, "second", ctx.FieldsByID["field-2"])
 }
+
+func TestBuildTemplateContext_Metadata(t *testing.T) {
+	createdAt := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	files := []model.File{{Name: "ca.pem"}, {Name: "not-loaded.bin"}}
+	files[0].SetContent([]byte("-----BEGIN CERTIFICATE-----"))
+
+	item := &model.Item{
+		ID:       "item-id",
+		VaultID:  "vault-id",
+		Title:    "Payments API",
+		Category: model.CategoryAPICredential,
+		Version:  7,
+		Tags:     []string{"production", "payments"},
+		URLs: []model.ItemURL{
+			{URL: "https://staging.example.com", Label: "staging"},
+			{URL: "https://api.example.com", Label: "website", Primary: true},
+		},
+		Fields: []model.ItemField{
+			{ID: "username", Label: "username", Value: "svc", FieldType: "STRING", Purpose: "USERNAME"},
+			{ID: "otp", Label: "one-time password", Value: "otpauth://totp/x?secret=ABC", FieldType: "Totp", SectionID: "s1"},
+		},
+		Sections:  []model.ItemSection{{ID: "s1", Title: "Security"}},
+		Files:     files,
+		CreatedAt: createdAt,
+		UpdatedAt: createdAt.Add(time.Hour),
+	}
+
+	ctx := BuildTemplateContext(item)
+
+	assert.Equal(t, "item-id", ctx.ID)
+	assert.Equal(t, "vault-id", ctx.VaultID)
+	assert.Equal(t, "Payments API", ctx.Title)
+	assert.Equal(t, "API_CREDENTIAL", ctx.Category)
+	assert.Equal(t, 7, ctx.Version)
+	assert.Equal(t, createdAt, ctx.CreatedAt)
+	assert.Equal(t, createdAt.Add(time.Hour), ctx.UpdatedAt)
+	assert.Equal(t, "https://api.example.com", ctx.PrimaryURL)
+	require.Len(t, ctx.URLs, 2)
+	assert.Equal(t, "staging", ctx.URLs[0].Label)
+	assert.True(t, ctx.HasTag("production"))
+	assert.False(t, ctx.HasTag("staging"))
+	assert.Equal(t, map[string]string{"ca.pem": "-----BEGIN CERTIFICATE-----"}, ctx.Files)
+
+	// Field types are normalized to the Connect spelling.
+	assert.Equal(t, "OTP", ctx.FieldTypes["one-time password"])
+	require.Len(t, ctx.FieldList, 2)
+	assert.Equal(t, Field{
+		ID: "otp", Label: "one-time password", Value: "otpauth://totp/x?secret=ABC", Formatted: "ABC",
+		Type: "OTP", Section: "Security",
+	}, ctx.FieldList[1])
+	assert.Equal(t, "ABC", ctx.FormattedFields["one-time password"])
+	assert.Equal(t, "USERNAME", ctx.FieldList[0].Purpose)
+}
+
+func TestBuildTemplateContext_PrimaryURLFallsBackToFirst(t *testing.T) {
+	ctx := BuildTemplateContext(&model.Item{
+		URLs: []model.ItemURL{{URL: "https://first.example.com"}, {URL: "https://second.example.com"}},
+	})
+	assert.Equal(t, "https://first.example.com", ctx.PrimaryURL)
+}
+
+func TestProcessTemplate_Metadata(t *testing.T) {
+	files := []model.File{{Name: "config.yaml"}}
+	files[0].SetContent([]byte("debug: false"))
+	ctx := BuildTemplateContext(&model.Item{
+		Title:     "db",
+		Tags:      []string{"production"},
+		URLs:      []model.ItemURL{{URL: "https://db.example.com", Primary: true}},
+		Files:     files,
+		UpdatedAt: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+		Fields: []model.ItemField{
+			{Label: "user", Value: "app", FieldType: "STRING"},
+			{Label: "password", Value: "secret", FieldType: "CONCEALED"},
+		},
+	})
+
+	tests := []struct {
+		name     string
+		template string
+		want     string
+	}{
+		{
+			name:     "primary URL",
+			template: `endpoint={{ .PrimaryURL }}`,
+			want:     "endpoint=https://db.example.com",
+		},
+		{
+			name:     "file attachment",
+			template: `{{ index .Files "config.yaml" }}`,
+			want:     "debug: false",
+		},
+		{
+			name:     "branch on tag",
+			template: `{{ if .HasTag "production" }}prod{{ else }}dev{{ end }}`,
+			want:     "prod",
+		},
+		{
+			name:     "updated at",
+			template: `{{ .Title }} rotated {{ .UpdatedAt.Format "2006-01-02" }}`,
+			want:     "db rotated 2024-03-01",
+		},
+		{
+			name:     "range over concealed fields",
+			template: `{{ range .FieldList }}{{ if eq .Type "CONCEALED" }}{{ .Label }};{{ end }}{{ end }}`,
+			want:     "password;",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ProcessTemplate(tt.template, ctx)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, string(result))
+		})
+	}
+}
+
+func TestProcessTemplateWithPartials(t *testing.T) {
+	ctx := &TemplateContext{
+		Fields: map[string]string{"username": "app", "password": "secret", "host": "db"},
+	}
+	partials := map[string]string{
+		"credentials": `{{ .Fields.username }}:{{ .Fields.password }}`,
+		"dsn":         `postgresql://{{ template "credentials" . }}@{{ .Fields.host }}`,
+	}
+
+	tests := []struct {
+		name     string
+		template string
+		partials map[string]string
+		want     string
+		wantErr  string
+	}{
+		{
+			name:     "nested partials",
+			template: `DATABASE_URL={{ template "dsn" . }}`,
+			partials: partials,
+			want:     "DATABASE_URL=postgresql://app:secret@db",
+		},
+		{
+			name:     "unknown partial",
+			template: `{{ template "missing" . }}`,
+			partials: partials,
+			wantErr:  "failed to execute template",
+		},
+		{
+			name:     "invalid partial",
+			template: `{{ .Fields.host }}`,
+			partials: map[string]string{"broken": `{{ .Fields.host`},
+			wantErr:  `failed to parse partial "broken"`,
+		},
+		{
+			name:     "reserved partial name",
+			template: `{{ .Fields.host }}`,
+			partials: map[string]string{"secret": `x`},
+			wantErr:  "reserved",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ProcessTemplateWithPartials(tt.template, tt.partials, ctx)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, string(result))
+		})
+	}
+}
@@ -0,0 +1,214 @@
+package template
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+)
+
+const (
+	// PrivateKeyFormatPKCS8 encodes the private key as a PKCS#8 "PRIVATE KEY" block.
+	PrivateKeyFormatPKCS8 = "PKCS8"
+	// PrivateKeyFormatPKCS1 encodes RSA keys as PKCS#1 "RSA PRIVATE KEY" blocks and
+	// EC keys as SEC 1 "EC PRIVATE KEY" blocks.
+	PrivateKeyFormatPKCS1 = "PKCS1"
+)
+
+// TLSBundle holds the normalized PEM data of a kubernetes.io/tls Secret.
+type TLSBundle struct {
+	// Certificate is the leaf certificate followed by its intermediates, ordered leaf first.
+	Certificate []byte
+	// PrivateKey is the private key of the leaf certificate.
+	PrivateKey []byte
+	// CA is the CA bundle, if any.
+	CA []byte
+}
+
+// BuildTLSBundle validates and normalizes PEM encoded TLS material.
+// The certificate chain is reordered so the certificate matching the private key comes first and every
+// following certificate issues the one before it. The private key is re-encoded in the requested format,
+// which defaults to PKCS#8. Text outside of PEM blocks is dropped.
+func BuildTLSBundle(certPEM, keyPEM, caPEM []byte, keyFormat string) (*TLSBundle, error) {
+	certs, err := parseCertificates(certPEM)
+	if err != nil {
+		return nil, fmt.Errorf("invalid certificate: %w", err)
+	}
+	if len(certs) == 0 {
+		return nil, fmt.Errorf("invalid certificate: no PEM encoded certificate found")
+	}
+
+	key, err := parsePrivateKey(keyPEM)
+	if err != nil {
+		return nil, fmt.Errorf("invalid private key: %w", err)
+	}
+
+	chain, err := orderChain(certs, key)
+	if err != nil {
+		return nil, err
+	}
+
+	encodedKey, err := encodePrivateKey(key, keyFormat)
+	if err != nil {
+		return nil, err
+	}
+
+	bundle := &TLSBundle{
+		Certificate: encodeCertificates(chain),
+		PrivateKey:  encodedKey,
+	}
+
+	if len(bytes.TrimSpace(caPEM)) > 0 {
+		caCerts, err := parseCertificates(caPEM)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CA certificate: %w", err)
+		}
+		if len(caCerts) == 0 {
+			return nil, fmt.Errorf("invalid CA certificate: no PEM encoded certificate found")
+		}
+		bundle.CA = encodeCertificates(caCerts)
+	}
+
+	return bundle, nil
+}
+
+func parseCertificates(data []byte) ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			return nil, fmt.Errorf("unexpected PEM block of type %q", block.Type)
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		certs = append(certs, cert)
+	}
+	return certs, nil
+}
+
+func parsePrivateKey(data []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM encoded private key found")
+	}
+
+	var key any
+	var err error
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		key, err = x509.ParseECPrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "ENCRYPTED PRIVATE KEY":
+		return nil, fmt.Errorf("encrypted private keys are not supported")
+	default:
+		return nil, fmt.Errorf("unexpected PEM block of type %q", block.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("unsupported private key type %T", key)
+	}
+	return signer, nil
+}
+
+// orderChain puts the certificate matching the key first, followed by its issuers in order.
+// Certificates that are not part of the chain are rejected.
+func orderChain(certs []*x509.Certificate, key crypto.Signer) ([]*x509.Certificate, error) {
+	leafIndex := -1
+	for i, cert := range certs {
+		if publicKeysEqual(cert.PublicKey, key.Public()) {
+			leafIndex = i
+			break
+		}
+	}
+	if leafIndex == -1 {
+		return nil, fmt.Errorf("private key does not match any certificate")
+	}
+
+	remaining := make([]*x509.Certificate, 0, len(certs)-1)
+	remaining = append(remaining, certs[:leafIndex]...)
+	remaining = append(remaining, certs[leafIndex+1:]...)
+
+	chain := []*x509.Certificate{certs[leafIndex]}
+	for len(remaining) > 0 {
+		current := chain[len(chain)-1]
+		next := -1
+		for i, candidate := range remaining {
+			if current.CheckSignatureFrom(candidate) == nil {
+				next = i
+				break
+			}
+		}
+		if next == -1 {
+			return nil, fmt.Errorf("certificate %q is not part of the chain of %q",
+				remaining[0].Subject.String(), chain[0].Subject.String())
+		}
+		chain = append(chain, remaining[next])
+		remaining = append(remaining[:next], remaining[next+1:]...)
+	}
+	return chain, nil
+}
+
+func publicKeysEqual(a, b crypto.PublicKey) bool {
+	switch key := a.(type) {
+	case *rsa.PublicKey:
+		return key.Equal(b)
+	case *ecdsa.PublicKey:
+		return key.Equal(b)
+	case ed25519.PublicKey:
+		return key.Equal(b)
+	}
+	return false
+}
+
+func encodeCertificates(certs []*x509.Certificate) []byte {
+	var buf bytes.Buffer
+	for _, cert := range certs {
+		_ = pem.Encode(&buf, &pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})
+	}
+	return buf.Bytes()
+}
+
+func encodePrivateKey(key crypto.Signer, format string) ([]byte, error) {
+	var block *pem.Block
+	switch format {
+	case "", PrivateKeyFormatPKCS8:
+		der, err := x509.MarshalPKCS8PrivateKey(key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode private key: %w", err)
+		}
+		block = &pem.Block{Type: "PRIVATE KEY", Bytes: der}
+	case PrivateKeyFormatPKCS1:
+		switch k := key.(type) {
+		case *rsa.PrivateKey:
+			block = &pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(k)}
+		case *ecdsa.PrivateKey:
+			der, err := x509.MarshalECPrivateKey(k)
+			if err != nil {
+				return nil, fmt.Errorf("failed to encode private key: %w", err)
+			}
+			block = &pem.Block{Type: "EC PRIVATE KEY", Bytes: der}
+		default:
+			return nil, fmt.Errorf("private key of type %T cannot be encoded as %s", key, format)
+		}
+	default:
+		return nil, fmt.Errorf("unknown private key format %q", format)
+	}
+	return pem.EncodeToMemory(block), nil
+}
@@ -0,0 +1,72 @@
+package fakeconnect
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Fault changes how the server answers the requests it matches, e.g. to simulate an outage.
+type Fault struct {
+	// PathPrefix limits the fault to the requests whose path starts with it. Empty matches every request.
+	PathPrefix string
+	// Latency delays the response. The request fails early when the client cancels it.
+	Latency time.Duration
+	// StatusCode fails the request with the status. Zero serves the request after the latency.
+	StatusCode int
+	// RetryAfter is sent in the Retry-After header, e.g. with http.StatusTooManyRequests.
+	RetryAfter time.Duration
+	// Times is the number of requests the fault applies to. Zero applies it until ClearFaults is called.
+	Times int
+}
+
+// InjectFault adds the fault. Every fault matching a request applies in the order they were added:
+// their latencies add up and the first one with a status code fails the request.
+func (s *Server) InjectFault(fault Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = append(s.faults, &fault)
+}
+
+// ClearFaults removes all faults.
+func (s *Server) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = nil
+}
+
+// FailRequests fails the next requests with the status code, e.g. http.StatusInternalServerError.
+func (s *Server) FailRequests(times int, statusCode int) {
+	s.InjectFault(Fault{StatusCode: statusCode, Times: times})
+}
+
+// RateLimit answers the next requests with 429 Too Many Requests.
+func (s *Server) RateLimit(times int, retryAfter time.Duration) {
+	s.InjectFault(Fault{StatusCode: http.StatusTooManyRequests, RetryAfter: retryAfter, Times: times})
+}
+
+// SetLatency delays every response until ClearFaults is called.
+func (s *Server) SetLatency(latency time.Duration) {
+	s.InjectFault(Fault{Latency: latency})
+}
+
+// apply applies the fault to the request and reports whether the request should still be served.
+func (f Fault) apply(w http.ResponseWriter, r *http.Request) bool {
+	if f.Latency > 0 {
+		timer := time.NewTimer(f.Latency)
+		defer timer.Stop()
+		select {
+		case <-r.Context().Done():
+			return false
+		case <-timer.C:
+		}
+	}
+	if f.StatusCode == 0 {
+		return true
+	}
+	if f.RetryAfter > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(int(f.RetryAfter.Round(time.Second).Seconds())))
+	}
+	writeError(w, f.StatusCode, http.StatusText(f.StatusCode))
+	return false
+}
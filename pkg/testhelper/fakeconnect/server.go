@@ -0,0 +1,407 @@
+package fakeconnect
+
+import (
+	"crypto/rand"
+	"encoding/base32"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/1Password/connect-sdk-go/onepassword"
+)
+
+// Version is the Connect version the server reports. connect-sdk-go requires at least 1.3.0 to download files.
+const Version = "1.7.3"
+
+// Server is an in-process fake of the 1Password Connect API. It serves the vaults, items and files
+// endpoints used by connect-sdk-go and the operator, so tests can run without a 1Password account.
+type Server struct {
+	*httptest.Server
+
+	// Token is the bearer token requests must send. Empty accepts any token.
+	Token string
+
+	mu       sync.Mutex
+	vaults   []*onepassword.Vault
+	items    map[string][]*onepassword.Item
+	contents map[string][]byte
+	faults   []*Fault
+	requests int
+}
+
+// NewServer starts a fake Connect server accepting the token.
+func NewServer(token string) *Server {
+	s := &Server{
+		Token:    token,
+		items:    map[string][]*onepassword.Item{},
+		contents: map[string][]byte{},
+	}
+	s.Server = httptest.NewServer(s.handler())
+	return s
+}
+
+// Requests returns the number of requests the server received, including the ones failed by a fault.
+func (s *Server) Requests() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests
+}
+
+// AddVault adds a vault with the name and returns it.
+func (s *Server) AddVault(name string) onepassword.Vault {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	vault := &onepassword.Vault{ID: newID(), Name: name, CreatedAt: now, UpdatedAt: now}
+	s.vaults = append(s.vaults, vault)
+	return *vault
+}
+
+// AddItem adds the item to the vault and returns it. The ID, version and field IDs are set when empty.
+func (s *Server) AddItem(vaultID string, item onepassword.Item) (onepassword.Item, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.vault(vaultID) == nil {
+		return onepassword.Item{}, fmt.Errorf("vault %q not found", vaultID)
+	}
+
+	stored := copyItem(&item)
+	if stored.ID == "" {
+		stored.ID = newID()
+	}
+	if stored.Version == 0 {
+		stored.Version = 1
+	}
+	stored.Vault = onepassword.ItemVault{ID: vaultID}
+	for _, field := range stored.Fields {
+		if field.ID == "" {
+			field.ID = newID()
+		}
+	}
+	now := time.Now()
+	stored.CreatedAt, stored.UpdatedAt = now, now
+	s.items[vaultID] = append(s.items[vaultID], stored)
+	return *copyItem(stored), nil
+}
+
+// AddFile attaches a file with the content to the item, bumps its version and returns the file.
+func (s *Server) AddFile(vaultID, itemID, name string, content []byte) (onepassword.File, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	item := s.item(vaultID, itemID)
+	if item == nil {
+		return onepassword.File{}, fmt.Errorf("item %q not found in vault %q", itemID, vaultID)
+	}
+	id := newID()
+	file := &onepassword.File{
+		ID:          id,
+		Name:        name,
+		Size:        len(content),
+		ContentPath: fmt.Sprintf("/v1/vaults/%s/items/%s/files/%s/content", vaultID, itemID, id),
+	}
+	item.Files = append(item.Files, file)
+	s.contents[id] = append([]byte(nil), content...)
+	bump(item)
+	return *file, nil
+}
+
+// BumpVersion increments the version of the item, as an edit in 1Password does.
+func (s *Server) BumpVersion(vaultID, itemID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	item := s.item(vaultID, itemID)
+	if item == nil {
+		return fmt.Errorf("item %q not found in vault %q", itemID, vaultID)
+	}
+	bump(item)
+	return nil
+}
+
+// EditField sets the value of the field with the label, adding the field when it does not exist,
+// and bumps the version of the item.
+func (s *Server) EditField(vaultID, itemID, label, value string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	item := s.item(vaultID, itemID)
+	if item == nil {
+		return fmt.Errorf("item %q not found in vault %q", itemID, vaultID)
+	}
+	edited := false
+	for _, field := range item.Fields {
+		if field.Label == label {
+			field.Value = value
+			edited = true
+		}
+	}
+	if !edited {
+		item.Fields = append(item.Fields, &onepassword.ItemField{
+			ID:    newID(),
+			Label: label,
+			Value: value,
+			Type:  onepassword.FieldTypeString,
+		})
+	}
+	bump(item)
+	return nil
+}
+
+// DeleteItem removes the item and its files.
+func (s *Server) DeleteItem(vaultID, itemID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	items := s.items[vaultID]
+	for i, item := range items {
+		if item.ID != itemID {
+			continue
+		}
+		for _, file := range item.Files {
+			delete(s.contents, file.ID)
+		}
+		s.items[vaultID] = append(items[:i], items[i+1:]...)
+		return nil
+	}
+	return fmt.Errorf("item %q not found in vault %q", itemID, vaultID)
+}
+
+func (s *Server) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /heartbeat", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("."))
+	})
+	mux.HandleFunc("GET /v1/vaults", s.getVaults)
+	mux.HandleFunc("GET /v1/vaults/{vault}", s.getVault)
+	mux.HandleFunc("GET /v1/vaults/{vault}/items", s.getItems)
+	mux.HandleFunc("GET /v1/vaults/{vault}/items/{item}", s.getItem)
+	mux.HandleFunc("GET /v1/vaults/{vault}/items/{item}/files", s.getFiles)
+	mux.HandleFunc("GET /v1/vaults/{vault}/items/{item}/files/{file}", s.getFile)
+	mux.HandleFunc("GET /v1/vaults/{vault}/items/{item}/files/{file}/content", s.getFileContent)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("1Password-Connect-Version", Version)
+		for _, fault := range s.recordRequest(r) {
+			if !fault.apply(w, r) {
+				return
+			}
+		}
+		if s.Token != "" && r.Header.Get("Authorization") != "Bearer "+s.Token {
+			writeError(w, http.StatusUnauthorized, "Invalid token signature")
+			return
+		}
+		mux.ServeHTTP(w, r)
+	})
+}
+
+// recordRequest counts the request and returns the faults matching it.
+func (s *Server) recordRequest(r *http.Request) []Fault {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests++
+	var matching []Fault
+	remaining := s.faults[:0]
+	for _, fault := range s.faults {
+		if !strings.HasPrefix(r.URL.Path, fault.PathPrefix) {
+			remaining = append(remaining, fault)
+			continue
+		}
+		matching = append(matching, *fault)
+		if fault.Times > 0 {
+			fault.Times--
+			if fault.Times == 0 {
+				continue
+			}
+		}
+		remaining = append(remaining, fault)
+	}
+	s.faults = remaining
+	return matching
+}
+
+func (s *Server) getVaults(w http.ResponseWriter, r *http.Request) {
+	title, ok := titleFilter(w, r)
+	if !ok {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	vaults := []onepassword.Vault{}
+	for _, vault := range s.vaults {
+		if title == nil || vault.Name == *title {
+			vaults = append(vaults, *vault)
+		}
+	}
+	writeJSON(w, vaults)
+}
+
+func (s *Server) getVault(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	vault := s.vault(r.PathValue("vault"))
+	if vault == nil {
+		writeError(w, http.StatusNotFound, "vault not found")
+		return
+	}
+	writeJSON(w, vault)
+}
+
+// getItems returns the summaries of the items of the vault, without their fields, sections and files.
+func (s *Server) getItems(w http.ResponseWriter, r *http.Request) {
+	title, ok := titleFilter(w, r)
+	if !ok {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	vaultID := r.PathValue("vault")
+	if s.vault(vaultID) == nil {
+		writeError(w, http.StatusNotFound, "vault not found")
+		return
+	}
+	items := []onepassword.Item{}
+	for _, item := range s.items[vaultID] {
+		if title != nil && item.Title != *title {
+			continue
+		}
+		summary := *copyItem(item)
+		summary.Fields, summary.Sections, summary.Files = nil, nil, nil
+		items = append(items, summary)
+	}
+	writeJSON(w, items)
+}
+
+func (s *Server) getItem(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	item := s.item(r.PathValue("vault"), r.PathValue("item"))
+	if item == nil {
+		writeError(w, http.StatusNotFound, "item not found")
+		return
+	}
+	writeJSON(w, item)
+}
+
+func (s *Server) getFiles(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	item := s.item(r.PathValue("vault"), r.PathValue("item"))
+	if item == nil {
+		writeError(w, http.StatusNotFound, "item not found")
+		return
+	}
+	files := []*onepassword.File{}
+	writeJSON(w, append(files, item.Files...))
+}
+
+func (s *Server) getFile(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	file := s.file(r.PathValue("vault"), r.PathValue("item"), r.PathValue("file"))
+	if file == nil {
+		writeError(w, http.StatusNotFound, "file not found")
+		return
+	}
+	writeJSON(w, file)
+}
+
+func (s *Server) getFileContent(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	file := s.file(r.PathValue("vault"), r.PathValue("item"), r.PathValue("file"))
+	if file == nil {
+		writeError(w, http.StatusNotFound, "file not found")
+		return
+	}
+	w.Header().Set("Content-Type", "application/octet-stream")
+	_, _ = w.Write(s.contents[file.ID])
+}
+
+func (s *Server) vault(vaultID string) *onepassword.Vault {
+	for _, vault := range s.vaults {
+		if vault.ID == vaultID {
+			return vault
+		}
+	}
+	return nil
+}
+
+func (s *Server) item(vaultID, itemID string) *onepassword.Item {
+	for _, item := range s.items[vaultID] {
+		if item.ID == itemID {
+			return item
+		}
+	}
+	return nil
+}
+
+func (s *Server) file(vaultID, itemID, fileID string) *onepassword.File {
+	item := s.item(vaultID, itemID)
+	if item == nil {
+		return nil
+	}
+	for _, file := range item.Files {
+		if file.ID == fileID {
+			return file
+		}
+	}
+	return nil
+}
+
+var titleFilterPattern = regexp.MustCompile(`^title eq "(.*)"$`)
+
+// titleFilter returns the title of a `title eq "<title>"` filter, or nil without filter.
+// Other filters are rejected, as they are not used by the operator.
+func titleFilter(w http.ResponseWriter, r *http.Request) (*string, bool) {
+	filter := r.URL.Query().Get("filter")
+	if filter == "" {
+		return nil, true
+	}
+	match := titleFilterPattern.FindStringSubmatch(filter)
+	if match == nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("unsupported filter %q", filter))
+		return nil, false
+	}
+	return &match[1], true
+}
+
+func writeJSON(w http.ResponseWriter, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+// writeError writes an error in the format of Connect, which connect-sdk-go returns as *onepassword.Error.
+func writeError(w http.ResponseWriter, statusCode int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	_ = json.NewEncoder(w).Encode(onepassword.Error{StatusCode: statusCode, Message: message})
+}
+
+func bump(item *onepassword.Item) {
+	item.Version++
+	item.UpdatedAt = time.Now()
+}
+
+// copyItem deep copies the item, so callers can't modify the items of the server.
+func copyItem(item *onepassword.Item) *onepassword.Item {
+	data, err := json.Marshal(item)
+	if err != nil {
+		panic(err)
+	}
+	var copied onepassword.Item
+	if err := json.Unmarshal(data, &copied); err != nil {
+		panic(err)
+	}
+	return &copied
+}
+
+var idEncoding = base32.NewEncoding("abcdefghijklmnopqrstuvwxyz234567").WithPadding(base32.NoPadding)
+
+// newID returns a random 26 character ID, in the format of 1Password IDs.
+func newID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return idEncoding.EncodeToString(b)
+}